@@ -0,0 +1,28 @@
+package analyzer
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Result is what an analyzer makes of a text. Score runs from -1, entirely
+// negative, through 0, neutral or no opinion at all, to 1, entirely positive.
+type Result struct {
+	Score    float64
+	Keywords []string
+}
+
+// Analyzer is implemented by every way the store can read review texts with.
+type Analyzer interface {
+	Name() string
+	Analyze(text string) Result
+}
+
+// Tokenize lowercases a text and splits it into words, apostrophes are dropped
+// so "don't" stays a single word.
+func Tokenize(text string) []string {
+	text = strings.NewReplacer("'", "", "’", "").Replace(strings.ToLower(text))
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
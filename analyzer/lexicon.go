@@ -0,0 +1,137 @@
+package analyzer
+
+import "math"
+
+// Lexicon scores a text on the sentiment words it contains, in Indonesian and
+// English. A negation flips the next sentiment word within negationReach
+// words and an intensifier strengthens it, "tidak terlalu bagus" and "not very
+// good" both score negative.
+type Lexicon struct {
+	words        map[string]float64
+	negations    map[string]bool
+	intensifiers map[string]float64
+	stopwords    map[string]bool
+}
+
+const negationReach = 3
+
+func NewLexicon() *Lexicon {
+	return &Lexicon{
+		words:        sentimentWords,
+		negations:    set(negations),
+		intensifiers: intensifiers,
+		stopwords:    set(stopwords),
+	}
+}
+
+func (lexicon *Lexicon) Name() string {
+	return "lexicon"
+}
+
+func (lexicon *Lexicon) Analyze(text string) Result {
+	tokens := Tokenize(text)
+
+	result := Result{Keywords: []string{}}
+	seen := map[string]bool{}
+	total, scored := 0.0, 0
+	negatedFor, boost := 0, 1.0
+	for _, token := range tokens {
+		if lexicon.negations[token] {
+			negatedFor = negationReach
+			continue
+		}
+		if factor, ok := lexicon.intensifiers[token]; ok {
+			boost *= factor
+			continue
+		}
+
+		if score, ok := lexicon.words[token]; ok {
+			score *= boost
+			if negatedFor > 0 {
+				// a negated opinion is weaker than its opposite, "not bad"
+				// isn't quite "good"
+				score *= -0.5
+			}
+			total += score
+			scored++
+			negatedFor, boost = 0, 1.0
+		} else if negatedFor > 0 {
+			negatedFor--
+		}
+
+		if len([]rune(token)) >= 3 && !lexicon.stopwords[token] && !seen[token] {
+			seen[token] = true
+			result.Keywords = append(result.Keywords, token)
+		}
+	}
+
+	if scored > 0 {
+		result.Score = math.Max(-1, math.Min(1, total/float64(scored)))
+		result.Score = math.Round(result.Score*1000) / 1000
+	}
+	return result
+}
+
+func set(words []string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, word := range words {
+		set[word] = true
+	}
+	return set
+}
+
+var sentimentWords = map[string]float64{
+	// Indonesian
+	"bagus": 0.8, "baik": 0.6, "mantap": 0.9, "mantab": 0.9, "keren": 0.8, "suka": 0.7,
+	"puas": 0.9, "memuaskan": 0.9, "cepat": 0.5, "rapi": 0.6, "nyaman": 0.7, "awet": 0.6,
+	"murah": 0.4, "sesuai": 0.5, "recommended": 0.8, "rekomendasi": 0.7, "cantik": 0.7,
+	"lembut": 0.5, "ramah": 0.6, "aman": 0.5, "oke": 0.4, "ok": 0.4, "terbaik": 1,
+	"sempurna": 1, "senang": 0.8, "cocok": 0.6, "pas": 0.4, "berkualitas": 0.8, "original": 0.5,
+	"asli": 0.5, "worth": 0.7, "top": 0.7, "jos": 0.8, "joss": 0.8, "love": 0.9,
+	"jelek": -0.8, "buruk": -0.8, "rusak": -0.9, "kecewa": -0.9, "mengecewakan": -0.9,
+	"lambat": -0.6, "lama": -0.4, "mahal": -0.4, "palsu": -0.9, "kotor": -0.7, "cacat": -0.9,
+	"sobek": -0.8, "luntur": -0.7, "bau": -0.6, "kasar": -0.6, "sempit": -0.4, "kebesaran": -0.4,
+	"kekecilan": -0.4, "salah": -0.6, "hilang": -0.7, "retak": -0.8, "penyok": -0.7,
+	"parah": -0.9, "zonk": -0.9, "nyesel": -0.8, "menyesal": -0.8, "tipis": -0.4, "lecet": -0.6,
+	"terburuk": -1, "marah": -0.8, "bohong": -0.9, "penipu": -1, "telat": -0.6,
+	// English
+	"good": 0.7, "great": 0.9, "excellent": 1, "amazing": 1, "awesome": 0.9, "nice": 0.6,
+	"perfect": 1, "happy": 0.8, "satisfied": 0.8, "fast": 0.5, "quick": 0.5, "comfortable": 0.7,
+	"beautiful": 0.8, "recommend": 0.8, "best": 1, "fine": 0.3, "soft": 0.4, "sturdy": 0.6,
+	"durable": 0.6, "cheap": 0.3, "fits": 0.4, "friendly": 0.6, "lovely": 0.8, "like": 0.5,
+	"bad": -0.7, "poor": -0.7, "terrible": -1, "awful": -1, "horrible": -1, "worst": -1,
+	"broken": -0.9, "damaged": -0.9, "disappointed": -0.9, "disappointing": -0.9, "slow": -0.6,
+	"late": -0.6, "expensive": -0.4, "fake": -0.9, "dirty": -0.7, "defective": -0.9, "torn": -0.8,
+	"wrong": -0.6, "missing": -0.7, "ugly": -0.8, "smell": -0.5, "smells": -0.5, "rough": -0.5,
+	"tight": -0.3, "small": -0.2, "refund": -0.5, "waste": -0.9, "hate": -0.9, "scam": -1,
+	"useless": -0.9, "faded": -0.6, "cracked": -0.8, "rude": -0.8,
+}
+
+var negations = []string{
+	"tidak", "tak", "gak", "ga", "nggak", "ngga", "enggak", "bukan", "belum", "kurang", "jangan",
+	"not", "no", "never", "dont", "doesnt", "didnt", "isnt", "wasnt", "arent", "werent", "cant",
+	"couldnt", "wont", "wouldnt", "hardly", "without",
+}
+
+var intensifiers = map[string]float64{
+	"sangat": 1.5, "banget": 1.5, "bgt": 1.5, "sekali": 1.3, "amat": 1.3, "terlalu": 1.3,
+	"paling": 1.5, "super": 1.5, "agak": 0.6, "lumayan": 0.7, "cukup": 0.8, "sedikit": 0.6,
+	"very": 1.5, "really": 1.4, "so": 1.3, "extremely": 1.8, "too": 1.3, "quite": 0.8,
+	"pretty": 0.8, "slightly": 0.6, "somewhat": 0.7, "bit": 0.6,
+}
+
+var stopwords = []string{
+	// Indonesian
+	"yang", "dan", "di", "ke", "dari", "ini", "itu", "untuk", "dengan", "ada", "juga", "saya",
+	"aku", "kami", "kita", "anda", "dia", "nya", "sudah", "udah", "sih", "deh", "dong", "kok",
+	"kak", "gan", "min", "seller", "pada", "akan", "bisa", "lagi", "atau", "tapi", "karena",
+	"jadi", "buat", "sama", "kalau", "kalo", "masih", "hanya", "cuma", "semua", "banyak",
+	"barang", "produk", "beli", "pesan", "pesanan", "datang", "sampai", "sampe", "terima",
+	"kasih", "makasih", "thanks", "yg", "dgn", "utk", "tdk", "sdh",
+	// English
+	"the", "and", "for", "with", "this", "that", "was", "were", "are", "but", "have", "has",
+	"had", "you", "your", "its", "they", "them", "our", "from", "all", "also", "just", "one",
+	"very", "really", "item", "product", "order", "bought", "got", "get", "would", "will",
+	"what", "when", "then", "than", "there", "their", "which", "been", "being", "after",
+	"before", "about", "into", "out", "much", "more", "some", "any", "only", "even", "still",
+}
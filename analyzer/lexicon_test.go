@@ -0,0 +1,39 @@
+package analyzer_test
+
+import (
+	"project/analyzer"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLexicon(t *testing.T) {
+	lexicon := analyzer.NewLexicon()
+
+	t.Run("Successfully score a positive Indonesian review", func(t *testing.T) {
+		result := lexicon.Analyze("Bahannya bagus banget, pengiriman cepat!")
+
+		assert.Greater(t, result.Score, 0.5)
+		assert.Equal(t, []string{"bahannya", "bagus", "pengiriman", "cepat"}, result.Keywords)
+	})
+
+	t.Run("Successfully score a negative English review", func(t *testing.T) {
+		result := lexicon.Analyze("The zipper was broken and the delivery was late")
+
+		assert.Less(t, result.Score, -0.5)
+		assert.Equal(t, []string{"zipper", "broken", "delivery", "late"}, result.Keywords)
+	})
+
+	t.Run("Successfully flip negated words", func(t *testing.T) {
+		assert.Less(t, lexicon.Analyze("tidak terlalu bagus").Score, 0.0)
+		assert.Less(t, lexicon.Analyze("I don't like it").Score, 0.0)
+		assert.Greater(t, lexicon.Analyze("not bad at all").Score, 0.0)
+	})
+
+	t.Run("Successfully score a text without opinions as neutral", func(t *testing.T) {
+		result := lexicon.Analyze("Lorem ipsum dolor sit amet")
+
+		assert.Equal(t, 0.0, result.Score)
+		assert.Equal(t, []string{"lorem", "ipsum", "dolor", "sit", "amet"}, result.Keywords)
+	})
+}
@@ -0,0 +1,58 @@
+package carrier
+
+import (
+	"fmt"
+	"time"
+)
+
+type Status string
+
+const (
+	InTransit Status = "in_transit"
+	Delivered Status = "delivered"
+	Exception Status = "exception"
+)
+
+type LabelRequest struct {
+	OrderID   uint
+	Service   string
+	Weight    float64
+	Recipient string
+	Address   string
+}
+
+type Label struct {
+	TrackingNumber string
+	Cost           float64
+}
+
+type TrackingStatus struct {
+	Status      Status
+	Description string
+	DeliveredAt *time.Time
+}
+
+// Carrier is implemented by every shipping provider the store can create labels with.
+type Carrier interface {
+	Name() string
+	CreateLabel(request LabelRequest) (Label, error)
+	Track(trackingNumber string) (TrackingStatus, error)
+}
+
+type Registry map[string]Carrier
+
+func NewRegistry(carriers ...Carrier) Registry {
+	registry := Registry{}
+	for _, carrier := range carriers {
+		registry[carrier.Name()] = carrier
+	}
+	return registry
+}
+
+func (registry Registry) Get(name string) (Carrier, error) {
+	carrier, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown carrier %q", name)
+	}
+	return carrier, nil
+}
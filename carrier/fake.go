@@ -0,0 +1,57 @@
+package carrier
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Fake is an in-memory carrier for tests and local development. Every label
+// stays in transit until Deliver is called with its tracking number.
+type Fake struct {
+	mu       sync.Mutex
+	sequence int
+	statuses map[string]TrackingStatus
+}
+
+func NewFake() *Fake {
+	return &Fake{statuses: map[string]TrackingStatus{}}
+}
+
+func (fake *Fake) Name() string {
+	return "fake"
+}
+
+func (fake *Fake) CreateLabel(request LabelRequest) (Label, error) {
+	if request.Weight <= 0 {
+		return Label{}, errors.New("weight must be greater than zero")
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	fake.sequence++
+	trackingNumber := fmt.Sprintf("FAKE%06d%04d", request.OrderID, fake.sequence)
+	fake.statuses[trackingNumber] = TrackingStatus{Status: InTransit, Description: "Shipment picked up"}
+
+	return Label{TrackingNumber: trackingNumber, Cost: 9000 + 1000*request.Weight}, nil
+}
+
+func (fake *Fake) Track(trackingNumber string) (TrackingStatus, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	status, ok := fake.statuses[trackingNumber]
+	if !ok {
+		return TrackingStatus{}, fmt.Errorf("tracking number %s not found", trackingNumber)
+	}
+	return status, nil
+}
+
+func (fake *Fake) Deliver(trackingNumber string, at time.Time) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	fake.statuses[trackingNumber] = TrackingStatus{Status: Delivered, Description: "Shipment delivered", DeliveredAt: &at}
+}
@@ -0,0 +1,51 @@
+package carrier_test
+
+import (
+	"project/carrier"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeCarrier(t *testing.T) {
+	fake := carrier.NewFake()
+	registry := carrier.NewRegistry(fake)
+
+	t.Run("Successfully create and deliver a label", func(t *testing.T) {
+		provider, err := registry.Get("fake")
+		assert.NoError(t, err)
+
+		label, err := provider.CreateLabel(carrier.LabelRequest{OrderID: 7, Weight: 2})
+		assert.NoError(t, err)
+		assert.Equal(t, "FAKE0000070001", label.TrackingNumber)
+		assert.Equal(t, float64(11000), label.Cost)
+
+		status, err := provider.Track(label.TrackingNumber)
+		assert.NoError(t, err)
+		assert.Equal(t, carrier.InTransit, status.Status)
+
+		at := time.Date(2024, 12, 20, 10, 0, 0, 0, time.UTC)
+		fake.Deliver(label.TrackingNumber, at)
+
+		status, err = provider.Track(label.TrackingNumber)
+		assert.NoError(t, err)
+		assert.Equal(t, carrier.Delivered, status.Status)
+		assert.Equal(t, at, *status.DeliveredAt)
+	})
+
+	t.Run("Failed to create a label without weight", func(t *testing.T) {
+		_, err := fake.CreateLabel(carrier.LabelRequest{OrderID: 7})
+		assert.Error(t, err)
+	})
+
+	t.Run("Failed to track an unknown tracking number", func(t *testing.T) {
+		_, err := fake.Track("UNKNOWN")
+		assert.Error(t, err)
+	})
+
+	t.Run("Failed to get an unknown carrier", func(t *testing.T) {
+		_, err := registry.Get("jne")
+		assert.Error(t, err)
+	})
+}
@@ -44,8 +44,19 @@ func main() {
 		log.Fatal("can't init service context %w", err)
 	}
 	crn.AddFunc("* * * * *", helper.CronExcel(*migrateDb, *seedDb))
+	crn.AddFunc("*/15 * * * *", ctx.Svc.Shipment.PollInTransit)
+	crn.AddFunc("* * * * *", ctx.Svc.Product.PublishScheduled)
+	crn.AddFunc("* * * * *", ctx.Svc.Price.ApplyScheduled)
+	crn.AddFunc("0 1 * * *", ctx.Svc.Payment.ReconcileDaily)
+	crn.AddFunc("0 2 * * *", ctx.Svc.Segment.ComputeRFM)
+	crn.AddFunc("0 3 * * *", ctx.Svc.Trash.PurgeExpired)
+	crn.AddFunc("0 4 * * *", ctx.Svc.Review.SummarizeSentiment)
 	crn.Start()
 
+	// segments are matched against the RFM scores, which are empty after a
+	// migration until the nightly run
+	ctx.Svc.Segment.ComputeRFM()
+
 	if !shouldLaunchServer(*migrateDb, *seedDb) {
 		return
 	}
@@ -7,17 +7,61 @@ import (
 )
 
 type Config struct {
-	DBHost      string
-	DBPort      string
-	DBUser      string
-	DBPassword  string
-	DBName      string
-	AppDebug    bool
-	AppSecret   string
-	ServerPort  string
-	DBMigrate   bool
-	DBSeeding   bool
-	RedisConfig RedisConfig
+	DBHost        string
+	DBPort        string
+	DBUser        string
+	DBPassword    string
+	DBName        string
+	AppDebug      bool
+	AppSecret     string
+	ServerPort    string
+	DBMigrate     bool
+	DBSeeding     bool
+	RedisConfig   RedisConfig
+	StoreConfig   StoreConfig
+	PaymentConfig PaymentConfig
+	StorageConfig StorageConfig
+	ImageConfig   ImageConfig
+	// TrashRetentionDays is how long deleted catalog rows stay in the trash
+	// before they are purged.
+	TrashRetentionDays int
+	// SentimentWindowDays is how many days of reviews make up a product's
+	// latest sentiment, it's compared with as many days before.
+	SentimentWindowDays int
+}
+
+// StoreConfig is printed in the header of invoices and packing slips.
+type StoreConfig struct {
+	Name     string
+	Address  string
+	Phone    string
+	LogoPath string
+}
+
+// PaymentConfig holds the secret payment providers sign their webhooks with.
+type PaymentConfig struct {
+	WebhookSecret string
+}
+
+// StorageConfig selects where uploaded files are kept, the driver is one of
+// cdn, local or s3.
+type StorageConfig struct {
+	Driver      string
+	CdnURL      string
+	LocalDir    string
+	PublicURL   string
+	S3Endpoint  string
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+}
+
+// ImageConfig limits the size in bytes and the dimensions of uploaded images.
+type ImageConfig struct {
+	MaxBytes  int64
+	MaxWidth  int
+	MaxHeight int
 }
 
 type RedisConfig struct {
@@ -61,6 +105,33 @@ func LoadConfig(migrateDb bool, seedDb bool) (Config, error) {
 			Password: viper.GetString("REDIS_PASSWORD"),
 			Prefix:   viper.GetString("REDIS_PREFIX"),
 		},
+		StoreConfig: StoreConfig{
+			Name:     viper.GetString("STORE_NAME"),
+			Address:  viper.GetString("STORE_ADDRESS"),
+			Phone:    viper.GetString("STORE_PHONE"),
+			LogoPath: viper.GetString("STORE_LOGO_PATH"),
+		},
+		PaymentConfig: PaymentConfig{
+			WebhookSecret: viper.GetString("PAYMENT_WEBHOOK_SECRET"),
+		},
+		StorageConfig: StorageConfig{
+			Driver:      viper.GetString("STORAGE_DRIVER"),
+			CdnURL:      viper.GetString("STORAGE_CDN_URL"),
+			LocalDir:    viper.GetString("STORAGE_LOCAL_DIR"),
+			PublicURL:   viper.GetString("STORAGE_PUBLIC_URL"),
+			S3Endpoint:  viper.GetString("STORAGE_S3_ENDPOINT"),
+			S3Region:    viper.GetString("STORAGE_S3_REGION"),
+			S3Bucket:    viper.GetString("STORAGE_S3_BUCKET"),
+			S3AccessKey: viper.GetString("STORAGE_S3_ACCESS_KEY"),
+			S3SecretKey: viper.GetString("STORAGE_S3_SECRET_KEY"),
+		},
+		ImageConfig: ImageConfig{
+			MaxBytes:  viper.GetInt64("IMAGE_MAX_BYTES"),
+			MaxWidth:  viper.GetInt("IMAGE_MAX_WIDTH"),
+			MaxHeight: viper.GetInt("IMAGE_MAX_HEIGHT"),
+		},
+		TrashRetentionDays:  viper.GetInt("TRASH_RETENTION_DAYS"),
+		SentimentWindowDays: viper.GetInt("SENTIMENT_WINDOW_DAYS"),
 	}
 	return config, nil
 }
@@ -74,6 +145,15 @@ func setDefaultValues(migrateDb bool, seedDb bool) {
 	viper.SetDefault("APP_DEBUG", true)
 	viper.SetDefault("APP_SECRET", "team-1")
 	viper.SetDefault("SERVER_PORT", ":8080")
+	viper.SetDefault("STORE_NAME", "Ecommerce Dashboard")
+	viper.SetDefault("STORAGE_DRIVER", "cdn")
+	viper.SetDefault("STORAGE_CDN_URL", "https://cdn-lumoshive-academy.vercel.app/api/v1/upload")
+	viper.SetDefault("STORAGE_LOCAL_DIR", "uploads")
+	viper.SetDefault("IMAGE_MAX_BYTES", 5<<20)
+	viper.SetDefault("IMAGE_MAX_WIDTH", 6000)
+	viper.SetDefault("IMAGE_MAX_HEIGHT", 6000)
+	viper.SetDefault("TRASH_RETENTION_DAYS", 30)
+	viper.SetDefault("SENTIMENT_WINDOW_DAYS", 30)
 
 	viper.SetDefault("DB_MIGRATE", migrateDb)
 	viper.SetDefault("DB_SEEDING", seedDb)
@@ -38,6 +38,8 @@ func ConnectDB(cfg config.Config) (*gorm.DB, error) {
 		DO $$ BEGIN CREATE TYPE orderstatus AS ENUM('created', 'processed', 'canceled', 'completed');
 		EXCEPTION WHEN duplicate_object THEN null; END $$;
 	`)
+	db.Exec(`ALTER TYPE orderstatus ADD VALUE IF NOT EXISTS 'delivered'`)
+	db.Exec(`CREATE SEQUENCE IF NOT EXISTS invoice_number_seq`)
 
 	// Call Migrate function to auto-migrate database schemas
 	if cfg.DBMigrate {
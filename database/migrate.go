@@ -21,6 +21,10 @@ func Migrate(db *gorm.DB) error {
 		return err
 	}
 
+	if err = createIndexes(db); err != nil {
+		return err
+	}
+
 	return createViews(db)
 }
 
@@ -30,14 +34,34 @@ func autoMigrates(db *gorm.DB) error {
 		&domain.Category{},
 		&domain.PasswordResetToken{},
 		&domain.Product{},
+		&domain.ProductCategory{},
 		&domain.ProductVariant{},
+		&domain.PriceHistory{},
+		&domain.ScheduledPrice{},
+		&domain.OptionType{},
+		&domain.OptionValue{},
+		&domain.VariantOptionValue{},
 		&domain.Image{},
 		&domain.Customer{},
+		&domain.CustomerAddress{},
 		&domain.Order{},
 		&domain.OrderItem{},
 		&domain.Review{},
+		&domain.ProductSentiment{},
 		&domain.Stock{},
 		&domain.Promotion{},
+		&domain.PromotionUsage{},
+		&domain.Banner{},
+		&domain.Supplier{},
+		&domain.PurchaseOrder{},
+		&domain.PurchaseOrderItem{},
+		&domain.Shipment{},
+		&domain.Payment{},
+		&domain.Refund{},
+		&domain.RefundItem{},
+		&domain.OrderNote{},
+		&domain.CustomerRFM{},
+		&domain.CustomerSegment{},
 	)
 }
 
@@ -49,21 +73,67 @@ func dropTables(db *gorm.DB) error {
 		&domain.Order{},
 		&domain.OrderItem{},
 		&domain.Customer{},
+		&domain.CustomerAddress{},
 		&domain.Product{},
+		&domain.ProductCategory{},
 		&domain.ProductVariant{},
+		&domain.PriceHistory{},
+		&domain.ScheduledPrice{},
+		&domain.OptionType{},
+		&domain.OptionValue{},
+		&domain.VariantOptionValue{},
 		&domain.Image{},
 		&domain.Review{},
+		&domain.ProductSentiment{},
 		&domain.Stock{},
 		&domain.Promotion{},
+		&domain.PromotionUsage{},
+		&domain.Banner{},
+		&domain.Supplier{},
+		&domain.PurchaseOrder{},
+		&domain.PurchaseOrderItem{},
+		&domain.Shipment{},
+		&domain.Payment{},
+		&domain.Refund{},
+		&domain.RefundItem{},
+		&domain.OrderNote{},
+		&domain.CustomerRFM{},
+		&domain.CustomerSegment{},
 	)
 }
 
 func setupJoinTables(db *gorm.DB) error {
 	var err error
 
+	if err = db.SetupJoinTable(&domain.Product{}, "Categories", &domain.ProductCategory{}); err != nil {
+		return err
+	}
+
+	if err = db.SetupJoinTable(&domain.ProductVariant{}, "OptionValues", &domain.VariantOptionValue{}); err != nil {
+		return err
+	}
+
 	return err
 }
 
+// createIndexes adds the indexes AutoMigrate can't express, like the GIN index
+// backing the product full-text search.
+func createIndexes(db *gorm.DB) error {
+	if err := db.Exec(`
+		CREATE INDEX IF NOT EXISTS idx_products_search
+		ON products USING GIN (to_tsvector('simple', name || ' ' || description))
+	`).Error; err != nil {
+		return err
+	}
+
+	// idx_variant_option only covers variants without options, those are
+	// unique on option_key
+	return db.Exec(`
+		CREATE UNIQUE INDEX idx_variant_option ON product_variants (product_id, size, color)
+		WHERE deleted_at IS NULL AND option_key = ''
+	`).Error
+}
+
 func createViews(db *gorm.DB) error {
 	var err error
 	if err = queryOrders(db); err != nil {
@@ -79,7 +149,7 @@ func createViews(db *gorm.DB) error {
 
 func queryOrders(db *gorm.DB) error {
 	query := db.Raw(`
-		SELECT orders.id, customers.name AS customer_name, customers.address AS customer_address, orders.payment_method, orderitems.total, orders.status
+		SELECT orders.id, customers.name AS customer_name, customers.address AS customer_address, orders.payment_method, orderitems.total AS subtotal, orders.discount, orderitems.total - orders.discount AS total, orders.status, orders.tracking_number, orders.invoice_number, orders.created_at, orders.customer_id
 		FROM orders
 		JOIN (
 			SELECT order_id, SUM(quantity * unit_price) AS total
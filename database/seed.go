@@ -20,7 +20,8 @@ func SeedAll(db *gorm.DB) error {
 				return fmt.Errorf("%s seeder fail with %s", name, errorMessage)
 			}
 		}
-		return nil
+		// the seeded variants only have the size and color columns
+		return LinkLegacyOptions(tx)
 	})
 }
 
@@ -30,11 +31,13 @@ func dataSeeds() []interface{} {
 		domain.CategorySeeder(),
 		domain.CustomerSeed(),
 		domain.SeedProducts(),
+		domain.SeedProductCategories(),
 		domain.SeedImages(),
 		domain.SeedProductVariants(),
 		domain.SeedStock(),
 		domain.SeedPromotions(),
 		domain.OrderSeed(),
 		domain.ReviewSeed(),
+		domain.SupplierSeed(),
 	}
 }
@@ -0,0 +1,64 @@
+package database
+
+import (
+	"project/domain"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// LinkLegacyOptions turns the size and color columns of the given variants,
+// or of every variant when none are given, into values of the global Size
+// and Color option types and links the variants to them. It is safe to run
+// again, existing values and links are kept.
+func LinkLegacyOptions(tx *gorm.DB, variantIds ...int) error {
+	all := len(variantIds) == 0
+	if all {
+		variantIds = []int{0}
+	}
+
+	for position, name := range domain.LegacyOptions {
+		column := strings.ToLower(name)
+
+		optionType := domain.OptionType{}
+		if err := tx.Where("category_id IS NULL AND LOWER(name) = ?", column).
+			Attrs(domain.OptionType{Name: name, Position: position}).
+			FirstOrCreate(&optionType).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec(`
+			INSERT INTO option_values (option_type_id, value, position)
+			SELECT DISTINCT ?, pv.`+column+`, 0 FROM product_variants pv
+			WHERE pv.deleted_at IS NULL AND pv.`+column+` <> '' AND (? OR pv.id IN ?)
+			ON CONFLICT (option_type_id, value) DO NOTHING`,
+			optionType.ID, all, variantIds).Error; err != nil {
+			return err
+		}
+
+		// a changed size or color replaces the previous link
+		if err := tx.Exec(`
+			DELETE FROM variant_option_values vov USING option_values ov
+			WHERE ov.id = vov.option_value_id AND ov.option_type_id = ? AND NOT ? AND vov.product_variant_id IN ?`,
+			optionType.ID, all, variantIds).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec(`
+			INSERT INTO variant_option_values (product_variant_id, option_value_id)
+			SELECT pv.id, ov.id FROM product_variants pv
+			JOIN option_values ov ON ov.option_type_id = ? AND ov.value = pv.`+column+`
+			WHERE pv.deleted_at IS NULL AND (? OR pv.id IN ?)
+			ON CONFLICT DO NOTHING`,
+			optionType.ID, all, variantIds).Error; err != nil {
+			return err
+		}
+	}
+
+	// same format as domain.OptionKey
+	return tx.Exec(`
+		UPDATE product_variants SET option_key = COALESCE((
+			SELECT string_agg(option_value_id::text, '-' ORDER BY option_value_id)
+			FROM variant_option_values WHERE product_variant_id = product_variants.id), '')
+		WHERE ? OR id IN ?`, all, variantIds).Error
+}
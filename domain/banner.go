@@ -1,5 +1,7 @@
 package domain
 
+import "gorm.io/gorm"
+
 type Banner struct {
 	ID        uint `gorm:"primaryKey"`
 	Title     string
@@ -8,6 +10,10 @@ type Banner struct {
 	EndDate   string `gorm:"type:date"`
 	IsPublish bool
 	ImageUrl  string
+	// ImageRenditions holds the url of the thumbnail, medium and large WebP
+	// renditions of the image.
+	ImageRenditions map[string]string `gorm:"type:jsonb;serializer:json"`
+	DeletedAt       gorm.DeletedAt    `gorm:"index" json:"-"`
 }
 
 func BannerSeed() []Banner {
@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+type BulkAction string
+
+const (
+	BulkProcess BulkAction = "process"
+	BulkCancel  BulkAction = "cancel"
+	BulkShip    BulkAction = "ship"
+)
+
+type BulkJobStatus string
+
+const (
+	BulkJobRunning  BulkJobStatus = "running"
+	BulkJobFinished BulkJobStatus = "finished"
+)
+
+type BulkOrderRequest struct {
+	OrderIDs        []uint          `json:"order_ids" binding:"required,min=1"`
+	Action          BulkAction      `json:"action" binding:"required,oneof=process cancel ship"`
+	TrackingNumbers map[uint]string `json:"tracking_numbers"`
+	Async           bool            `json:"async"`
+}
+
+type BulkOrderResult struct {
+	OrderID uint   `json:"order_id"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// BulkJob tracks a bulk order action. Asynchronous jobs are polled by ID until
+// their status is finished.
+type BulkJob struct {
+	ID         string            `json:"id"`
+	Action     BulkAction        `json:"action"`
+	Status     BulkJobStatus     `json:"status"`
+	Total      int               `json:"total"`
+	Processed  int               `json:"processed"`
+	Succeeded  int               `json:"succeeded"`
+	Failed     int               `json:"failed"`
+	Results    []BulkOrderResult `json:"results"`
+	CreatedAt  time.Time         `json:"created_at"`
+	FinishedAt *time.Time        `json:"finished_at,omitempty"`
+}
+
+func (request BulkOrderRequest) Validate() error {
+	seen := map[uint]bool{}
+	for _, id := range request.OrderIDs {
+		if seen[id] {
+			return fmt.Errorf("order %d is listed more than once", id)
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+// Confirmation returns the confirmation that applies the action to an order
+// with the given status through Order.Confirm.
+func (request BulkOrderRequest) Confirmation(orderId uint, status Status) (OrderConfirmation, error) {
+	switch request.Action {
+	case BulkProcess:
+		if status != Created {
+			return OrderConfirmation{}, fmt.Errorf("order with status %s can't be processed", status)
+		}
+		return OrderConfirmation{Accept: true}, nil
+	case BulkCancel:
+		if status != Created && status != Processed {
+			return OrderConfirmation{}, fmt.Errorf("order with status %s can't be canceled", status)
+		}
+		return OrderConfirmation{Accept: false}, nil
+	case BulkShip:
+		if status != Processed {
+			return OrderConfirmation{}, fmt.Errorf("order with status %s can't be shipped", status)
+		}
+		trackingNumber := request.TrackingNumbers[orderId]
+		if trackingNumber == "" {
+			return OrderConfirmation{}, errors.New("tracking number is missing")
+		}
+		return OrderConfirmation{Accept: true, TrackingNumber: &trackingNumber}, nil
+	}
+	return OrderConfirmation{}, fmt.Errorf("unknown action %s", request.Action)
+}
+
+// Clone copies the job with results of its own, so recording on the copy
+// doesn't touch the original.
+func (job BulkJob) Clone() BulkJob {
+	job.Results = append([]BulkOrderResult{}, job.Results...)
+	return job
+}
+
+func (job *BulkJob) Record(orderId uint, err error) {
+	result := BulkOrderResult{OrderID: orderId, Success: err == nil}
+	if err != nil {
+		result.Error = err.Error()
+		job.Failed++
+	} else {
+		job.Succeeded++
+	}
+	job.Processed++
+	job.Results = append(job.Results, result)
+}
+
+func (job *BulkJob) Finish(at time.Time) {
+	job.Status = BulkJobFinished
+	job.FinishedAt = &at
+}
@@ -0,0 +1,63 @@
+package domain_test
+
+import (
+	"errors"
+	"project/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBulkOrderConfirmation(t *testing.T) {
+	t.Run("Successfully ship with a tracking number", func(t *testing.T) {
+		request := domain.BulkOrderRequest{Action: domain.BulkShip, TrackingNumbers: map[uint]string{1: "JNE001"}}
+
+		confirmation, err := request.Confirmation(1, domain.Processed)
+		assert.NoError(t, err)
+		assert.True(t, confirmation.Accept)
+		assert.Equal(t, "JNE001", *confirmation.TrackingNumber)
+	})
+
+	t.Run("Failed to ship without a tracking number", func(t *testing.T) {
+		request := domain.BulkOrderRequest{Action: domain.BulkShip, TrackingNumbers: map[uint]string{1: "JNE001"}}
+
+		_, err := request.Confirmation(2, domain.Processed)
+		assert.Error(t, err)
+	})
+
+	t.Run("Failed to process an order twice", func(t *testing.T) {
+		request := domain.BulkOrderRequest{Action: domain.BulkProcess}
+
+		_, err := request.Confirmation(1, domain.Processed)
+		assert.Error(t, err)
+	})
+
+	t.Run("Successfully cancel a processed order", func(t *testing.T) {
+		request := domain.BulkOrderRequest{Action: domain.BulkCancel}
+
+		confirmation, err := request.Confirmation(1, domain.Processed)
+		assert.NoError(t, err)
+		assert.False(t, confirmation.Accept)
+	})
+
+	t.Run("Failed to list an order twice", func(t *testing.T) {
+		request := domain.BulkOrderRequest{Action: domain.BulkCancel, OrderIDs: []uint{1, 2, 1}}
+
+		assert.Error(t, request.Validate())
+	})
+}
+
+func TestBulkJobRecord(t *testing.T) {
+	job := domain.BulkJob{Status: domain.BulkJobRunning, Total: 2}
+
+	job.Record(1, nil)
+	job.Record(2, errors.New("order not found"))
+	job.Finish(time.Now())
+
+	assert.Equal(t, 2, job.Processed)
+	assert.Equal(t, 1, job.Succeeded)
+	assert.Equal(t, 1, job.Failed)
+	assert.Equal(t, "order not found", job.Results[1].Error)
+	assert.Equal(t, domain.BulkJobFinished, job.Status)
+}
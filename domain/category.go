@@ -3,14 +3,125 @@ package domain
 import (
 	"errors"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type Category struct {
 	ID        uint   `gorm:"primaryKey;autoincrement" json:"id"`
 	Name      string `gorm:"type:varchar(50)" json:"name" binding:"required"`
 	Icon      string `gorm:"type:varchar(200)" json:"image" binding:"required"`
+	ParentID  *uint  `gorm:"index" json:"parent_id"`
+	Position  int    `gorm:"not null" json:"position"`
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	IconRenditions map[string]string `gorm:"type:jsonb;serializer:json" json:"image_renditions,omitempty"`
+
+	ProductCount int             `gorm:"->;-:migration" json:"product_count"`
+	Children     []*Category     `gorm:"foreignKey:ParentID;constraint:OnDelete:SET NULL" json:"children,omitempty"`
+	Breadcrumbs  []CategoryCrumb `gorm:"-" json:"breadcrumbs,omitempty"`
+}
+
+// CategoryCrumb is one step of the path from a root category down to a
+// category, e.g. Fruits > Tropical > Mango.
+type CategoryCrumb struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+// CategoryMove places a category under ParentID, or at the root when it is
+// nil, at Position among its new siblings. Without a position it goes last.
+type CategoryMove struct {
+	ParentID *uint `json:"parent_id"`
+	Position *int  `json:"position" binding:"omitempty,min=0"`
+}
+
+// CheckParent rejects moving a category under itself or one of its
+// descendants. ancestors is the path from the root down to the new parent.
+func (move CategoryMove) CheckParent(id uint, ancestors []CategoryCrumb) error {
+	for _, ancestor := range ancestors {
+		if ancestor.ID == id {
+			return errors.New("category can't be moved under itself or its descendants")
+		}
+	}
+	return nil
+}
+
+// Reorder puts the category at the requested position among its siblings and
+// renumbers them from zero. siblings must not contain the moved category.
+func (move CategoryMove) Reorder(category *Category, siblings []*Category) []*Category {
+	position := len(siblings)
+	if move.Position != nil && *move.Position < position {
+		position = *move.Position
+	}
+
+	ordered := make([]*Category, 0, len(siblings)+1)
+	ordered = append(ordered, siblings[:position]...)
+	ordered = append(ordered, category)
+	ordered = append(ordered, siblings[position:]...)
+	for i := range ordered {
+		ordered[i].Position = i
+	}
+	return ordered
+}
+
+// BuildCategoryTree nests the categories under their parents. The input order
+// is kept among siblings, categories whose parent is missing become roots.
+func BuildCategoryTree(categories []Category) []*Category {
+	nodes := make(map[uint]*Category, len(categories))
+	for i := range categories {
+		categories[i].Children = []*Category{}
+		nodes[categories[i].ID] = &categories[i]
+	}
+
+	roots := []*Category{}
+	for i := range categories {
+		category := &categories[i]
+		if category.ParentID != nil {
+			if parent, ok := nodes[*category.ParentID]; ok {
+				parent.Children = append(parent.Children, category)
+				continue
+			}
+		}
+		roots = append(roots, category)
+	}
+	return roots
+}
+
+// ProductCategory links a product to one of its categories. The primary one is
+// also kept on Product.CategoryID.
+type ProductCategory struct {
+	ProductID  int  `gorm:"primaryKey"`
+	CategoryID uint `gorm:"primaryKey"`
+	CreatedAt  time.Time
+}
+
+type CategoryAssignment struct {
+	CategoryID uint `json:"category_id" binding:"required"`
+	Primary    bool `json:"primary"`
+}
+
+// ErrCategoryHasProducts is returned when deleting a category that still has
+// products without forcing it.
+var ErrCategoryHasProducts = errors.New("category still has products")
+
+// CategoryDelete holds the options for deleting a category that still has
+// products: Force unlinks them and ReassignTo moves them to another category.
+type CategoryDelete struct {
+	Force      bool  `form:"force"`
+	ReassignTo *uint `form:"reassign_to"`
+}
+
+func (options CategoryDelete) Validate(id uint) error {
+	if options.ReassignTo != nil && !options.Force {
+		return errors.New("reassign_to needs force=true")
+	}
+	if options.ReassignTo != nil && *options.ReassignTo == id {
+		return errors.New("can't reassign products to the deleted category")
+	}
+	return nil
 }
 
 func (c *Category) Validate() error {
@@ -23,6 +134,19 @@ func (c *Category) Validate() error {
 	return nil
 }
 
+// seedCategoryOf spreads the seeded products over the seeded categories.
+func seedCategoryOf(productID int) uint {
+	return uint((productID-1)%len(CategorySeeder()) + 1)
+}
+
+func SeedProductCategories() []ProductCategory {
+	var links []ProductCategory
+	for productID := 1; productID <= 26; productID++ {
+		links = append(links, ProductCategory{ProductID: productID, CategoryID: seedCategoryOf(productID)})
+	}
+	return links
+}
+
 func CategorySeeder() []Category {
 	return []Category{
 		{
@@ -0,0 +1,61 @@
+package domain_test
+
+import (
+	"project/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func uintPtr(v uint) *uint { return &v }
+
+func TestBuildCategoryTree(t *testing.T) {
+	categories := []domain.Category{
+		{ID: 1, Name: "Fruits"},
+		{ID: 2, Name: "Tropical", ParentID: uintPtr(1)},
+		{ID: 3, Name: "Mango", ParentID: uintPtr(2)},
+		{ID: 4, Name: "Vegetables"},
+		{ID: 5, Name: "Orphan", ParentID: uintPtr(99)},
+	}
+
+	tree := domain.BuildCategoryTree(categories)
+
+	assert.Len(t, tree, 3)
+	assert.Equal(t, "Fruits", tree[0].Name)
+	assert.Equal(t, "Tropical", tree[0].Children[0].Name)
+	assert.Equal(t, "Mango", tree[0].Children[0].Children[0].Name)
+	assert.Empty(t, tree[1].Children)
+	assert.Equal(t, "Orphan", tree[2].Name)
+}
+
+func TestCategoryMove(t *testing.T) {
+	t.Run("Failed to move a category under its descendant", func(t *testing.T) {
+		move := domain.CategoryMove{ParentID: uintPtr(3)}
+		ancestors := []domain.CategoryCrumb{{ID: 1, Name: "Fruits"}, {ID: 2, Name: "Tropical"}, {ID: 3, Name: "Mango"}}
+
+		assert.Error(t, move.CheckParent(2, ancestors))
+		assert.NoError(t, move.CheckParent(4, ancestors))
+	})
+
+	t.Run("Successfully reorder among siblings", func(t *testing.T) {
+		position := 1
+		move := domain.CategoryMove{Position: &position}
+		siblings := []*domain.Category{{ID: 1}, {ID: 2}, {ID: 3}}
+
+		ordered := move.Reorder(&domain.Category{ID: 4}, siblings)
+
+		ids := []uint{}
+		for i, category := range ordered {
+			ids = append(ids, category.ID)
+			assert.Equal(t, i, category.Position)
+		}
+		assert.Equal(t, []uint{1, 4, 2, 3}, ids)
+	})
+
+	t.Run("Successfully move last without a position", func(t *testing.T) {
+		ordered := domain.CategoryMove{}.Reorder(&domain.Category{ID: 4}, []*domain.Category{{ID: 1}})
+
+		assert.Equal(t, uint(4), ordered[1].ID)
+		assert.Equal(t, 1, ordered[1].Position)
+	})
+}
@@ -1,9 +1,80 @@
 package domain
 
+import (
+	"errors"
+	"time"
+)
+
 type Customer struct {
-	ID      uint   `gorm:"primaryKey" json:"-"`
-	Name    string `json:"name"`
-	Address string `json:"address"`
+	ID        uint              `gorm:"primaryKey" json:"id"`
+	Name      string            `json:"name" binding:"required"`
+	Email     string            `gorm:"type:varchar(100);index" json:"email" binding:"omitempty,email"`
+	Phone     string            `gorm:"type:varchar(30)" json:"phone"`
+	Address   string            `json:"address"`
+	Tags      []string          `gorm:"type:jsonb;serializer:json" json:"tags"`
+	Notes     string            `json:"notes"`
+	Addresses []CustomerAddress `gorm:"foreignKey:CustomerID" json:"addresses,omitempty" binding:"dive"`
+	CreatedAt time.Time         `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time         `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+type CustomerAddress struct {
+	ID         uint   `gorm:"primaryKey;autoIncrement" json:"id"`
+	CustomerID uint   `gorm:"not null;index" json:"-"`
+	Label      string `gorm:"type:varchar(50)" json:"label"`
+	Recipient  string `json:"recipient"`
+	Phone      string `gorm:"type:varchar(30)" json:"phone"`
+	Address    string `gorm:"not null" json:"address" binding:"required"`
+	City       string `json:"city"`
+	PostalCode string `gorm:"type:varchar(10)" json:"postal_code"`
+	IsDefault  bool   `gorm:"default:false" json:"is_default"`
+}
+
+type CustomerFilter struct {
+	Search string `form:"search"`
+	Tag    string `form:"tag"`
+}
+
+// CustomerMetrics are computed from the orders that weren't canceled.
+type CustomerMetrics struct {
+	OrderCount        int        `json:"order_count"`
+	TotalSpend        float64    `json:"total_spend"`
+	AverageOrderValue float64    `json:"average_order_value"`
+	FirstOrderAt      *time.Time `json:"first_order_at"`
+	LastOrderAt       *time.Time `json:"last_order_at"`
+}
+
+type CustomerDetail struct {
+	Customer
+	Metrics CustomerMetrics `json:"metrics"`
+}
+
+// SetDefaultAddress makes sure exactly one address is the default one when
+// addresses are given and copies it to Address, which orders are shipped to.
+func (customer *Customer) SetDefaultAddress() error {
+	if len(customer.Addresses) == 0 {
+		return nil
+	}
+
+	defaults := 0
+	for _, address := range customer.Addresses {
+		if address.IsDefault {
+			defaults++
+		}
+	}
+	if defaults > 1 {
+		return errors.New("only one address can be the default")
+	}
+	if defaults == 0 {
+		customer.Addresses[0].IsDefault = true
+	}
+
+	for _, address := range customer.Addresses {
+		if address.IsDefault {
+			customer.Address = address.Address
+		}
+	}
+	return nil
 }
 
 func CustomerSeed() []Customer {
@@ -0,0 +1,39 @@
+package domain_test
+
+import (
+	"project/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCustomerSetDefaultAddress(t *testing.T) {
+	t.Run("Successfully default to the first address", func(t *testing.T) {
+		customer := domain.Customer{Addresses: []domain.CustomerAddress{{Address: "Jl. Satu"}, {Address: "Jl. Dua"}}}
+
+		assert.NoError(t, customer.SetDefaultAddress())
+		assert.True(t, customer.Addresses[0].IsDefault)
+		assert.Equal(t, "Jl. Satu", customer.Address)
+	})
+
+	t.Run("Successfully use the chosen default address", func(t *testing.T) {
+		customer := domain.Customer{Addresses: []domain.CustomerAddress{{Address: "Jl. Satu"}, {Address: "Jl. Dua", IsDefault: true}}}
+
+		assert.NoError(t, customer.SetDefaultAddress())
+		assert.False(t, customer.Addresses[0].IsDefault)
+		assert.Equal(t, "Jl. Dua", customer.Address)
+	})
+
+	t.Run("Successfully keep the address without addresses", func(t *testing.T) {
+		customer := domain.Customer{Address: "Alamat Satu"}
+
+		assert.NoError(t, customer.SetDefaultAddress())
+		assert.Equal(t, "Alamat Satu", customer.Address)
+	})
+
+	t.Run("Failed with two default addresses", func(t *testing.T) {
+		customer := domain.Customer{Addresses: []domain.CustomerAddress{{Address: "Jl. Satu", IsDefault: true}, {Address: "Jl. Dua", IsDefault: true}}}
+
+		assert.Error(t, customer.SetDefaultAddress())
+	})
+}
@@ -7,5 +7,7 @@ type DataPage struct {
 	Pages       int         `json:"pages"`
 	CurrentPage uint        `json:"current_page"`
 	Limit       uint        `json:"per_page"`
+	Filters     interface{} `json:"filters,omitempty"`
+	Facets      interface{} `json:"facets,omitempty"`
 	Data        interface{} `json:"data"`
 }
@@ -1,18 +1,81 @@
 package domain
 
 import (
+	"fmt"
 	"time"
 
 	"gorm.io/gorm"
 )
 
 type Image struct {
-	ID        int             `gorm:"primaryKey;autoIncrement" json:"id"`
-	ProductID int             `gorm:"not null" json:"product_id"`
-	URLPath   string          `gorm:"type:varchar(150)" json:"url_path"`
-	CreatedAt time.Time       `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
-	DeletedAt *gorm.DeletedAt `gorm:"index" json:"deleted_at" swaggerignore:"true"`
+	ID               int               `gorm:"primaryKey;autoIncrement" json:"id"`
+	ProductID        int               `gorm:"not null;uniqueIndex:idx_image_primary,where:is_primary AND deleted_at IS NULL" json:"product_id"`
+	ProductVariantID *int              `gorm:"index" json:"product_variant_id"`
+	URLPath          string            `gorm:"type:varchar(150)" json:"url_path"`
+	FileID           string            `gorm:"type:varchar(100)" json:"-"`
+	Renditions       map[string]string `gorm:"type:jsonb;serializer:json" json:"renditions"`
+	RenditionFileIDs []string          `gorm:"type:jsonb;serializer:json" json:"-"`
+	AltText          string            `gorm:"type:varchar(150)" json:"alt_text"`
+	Position         int               `gorm:"not null;default:0" json:"position"`
+	IsPrimary        bool              `gorm:"not null;default:false" json:"is_primary"`
+	CreatedAt        time.Time         `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt        time.Time         `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt        *gorm.DeletedAt   `gorm:"index" json:"deleted_at" swaggerignore:"true"`
+}
+
+// FileIDs returns the storage keys of the image and of its renditions.
+func (image Image) FileIDs() []string {
+	ids := []string{}
+	if image.FileID != "" {
+		ids = append(ids, image.FileID)
+	}
+	return append(ids, image.RenditionFileIDs...)
+}
+
+type ImageUpdate struct {
+	AltText *string `json:"alt_text" binding:"omitempty,max=150"`
+	// ProductVariantID attaches the image to a variant, 0 detaches it.
+	ProductVariantID *int `json:"product_variant_id" binding:"omitempty,min=0"`
+}
+
+func (input ImageUpdate) Apply(image *Image) {
+	if input.AltText != nil {
+		image.AltText = *input.AltText
+	}
+	if input.ProductVariantID != nil {
+		image.ProductVariantID = nil
+		if *input.ProductVariantID > 0 {
+			image.ProductVariantID = input.ProductVariantID
+		}
+	}
+}
+
+type ImageOrder struct {
+	IDs []int `json:"ids" binding:"required,min=1"`
+}
+
+// Apply sets the position of every image of a product, the order has to list
+// each of them exactly once.
+func (order ImageOrder) Apply(images []Image) error {
+	if len(order.IDs) != len(images) {
+		return fmt.Errorf("order must list all %d images of the product", len(images))
+	}
+
+	positions := map[int]int{}
+	for i, id := range order.IDs {
+		if _, ok := positions[id]; ok {
+			return fmt.Errorf("image %d is listed twice", id)
+		}
+		positions[id] = i
+	}
+	for i := range images {
+		position, ok := positions[images[i].ID]
+		if !ok {
+			return fmt.Errorf("order doesn't list image %d", images[i].ID)
+		}
+		images[i].Position = position
+	}
+	return nil
 }
 
 func SeedImages() []Image {
@@ -55,5 +118,13 @@ func SeedImages() []Image {
 		},
 	}
 
+	positions := map[int]int{}
+	for i := range images {
+		images[i].Position = positions[images[i].ProductID]
+		images[i].IsPrimary = images[i].Position == 0
+		images[i].AltText = fmt.Sprintf("Product %d image %d", images[i].ProductID, images[i].Position+1)
+		positions[images[i].ProductID]++
+	}
+
 	return images
 }
@@ -0,0 +1,57 @@
+package domain_test
+
+import (
+	"project/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageOrder(t *testing.T) {
+	t.Run("Successfully reorder images", func(t *testing.T) {
+		images := []domain.Image{{ID: 1}, {ID: 2, Position: 1}, {ID: 3, Position: 2}}
+
+		err := domain.ImageOrder{IDs: []int{3, 1, 2}}.Apply(images)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, images[0].Position)
+		assert.Equal(t, 2, images[1].Position)
+		assert.Equal(t, 0, images[2].Position)
+	})
+
+	t.Run("Missing image", func(t *testing.T) {
+		err := domain.ImageOrder{IDs: []int{1, 4}}.Apply([]domain.Image{{ID: 1}, {ID: 2}})
+		assert.EqualError(t, err, "order doesn't list image 2")
+	})
+
+	t.Run("Image listed twice", func(t *testing.T) {
+		err := domain.ImageOrder{IDs: []int{1, 1}}.Apply([]domain.Image{{ID: 1}, {ID: 2}})
+		assert.EqualError(t, err, "image 1 is listed twice")
+	})
+
+	t.Run("Incomplete order", func(t *testing.T) {
+		err := domain.ImageOrder{IDs: []int{1}}.Apply([]domain.Image{{ID: 1}, {ID: 2}})
+		assert.Error(t, err)
+	})
+}
+
+func TestImageUpdate(t *testing.T) {
+	variantId, detach := 4, 0
+	image := domain.Image{AltText: "old"}
+
+	domain.ImageUpdate{ProductVariantID: &variantId}.Apply(&image)
+	assert.Equal(t, "old", image.AltText)
+	assert.Equal(t, 4, *image.ProductVariantID)
+
+	alt := "Fresh mango"
+	domain.ImageUpdate{AltText: &alt, ProductVariantID: &detach}.Apply(&image)
+	assert.Equal(t, "Fresh mango", image.AltText)
+	assert.Nil(t, image.ProductVariantID)
+}
+
+func TestImageFileIDs(t *testing.T) {
+	assert.Empty(t, domain.Image{URLPath: "https://example.com/seeded.jpg"}.FileIDs())
+
+	image := domain.Image{FileID: "file-1", RenditionFileIDs: []string{"file-2", "file-3"}}
+	assert.Equal(t, []string{"file-1", "file-2", "file-3"}, image.FileIDs())
+}
@@ -0,0 +1,32 @@
+package domain
+
+type NewOrder struct {
+	CustomerID    uint           `json:"customer_id" binding:"required"`
+	PaymentMethod string         `json:"payment_method" binding:"required"`
+	VoucherCode   string         `json:"voucher_code"`
+	Items         []NewOrderItem `json:"items" binding:"required,min=1,dive"`
+}
+
+type NewOrderItem struct {
+	VariantID uint `json:"variant_id" binding:"required"`
+	Quantity  uint `json:"quantity" binding:"required,min=1"`
+}
+
+// Lines merges items that point to the same variant so stock is validated on the total quantity.
+func (newOrder NewOrder) Lines() []NewOrderItem {
+	return mergeLines(newOrder.Items)
+}
+
+func mergeLines(items []NewOrderItem) []NewOrderItem {
+	var lines []NewOrderItem
+	index := map[uint]int{}
+	for _, item := range items {
+		if i, ok := index[item.VariantID]; ok {
+			lines[i].Quantity += item.Quantity
+			continue
+		}
+		index[item.VariantID] = len(lines)
+		lines = append(lines, item)
+	}
+	return lines
+}
@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OptionType is a kind of variant attribute like size, weight or breed. Types
+// without a category apply to every product, the others to the products of
+// the category and its subcategories.
+type OptionType struct {
+	ID         uint           `gorm:"primaryKey;autoIncrement" json:"id"`
+	CategoryID *uint          `gorm:"index" json:"category_id"`
+	Name       string         `gorm:"type:varchar(50);not null" json:"name"`
+	Position   int            `gorm:"not null" json:"position"`
+	Values     []*OptionValue `gorm:"foreignKey:OptionTypeID;constraint:OnDelete:CASCADE" json:"values"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
+
+type OptionValue struct {
+	ID           uint        `gorm:"primaryKey;autoIncrement" json:"id"`
+	OptionTypeID uint        `gorm:"not null;uniqueIndex:idx_option_value" json:"option_type_id"`
+	OptionType   *OptionType `json:"option_type,omitempty"`
+	Value        string      `gorm:"type:varchar(50);not null;uniqueIndex:idx_option_value" json:"value"`
+	Position     int         `gorm:"not null" json:"position"`
+}
+
+// VariantOptionValue links a variant to the option values defining it.
+type VariantOptionValue struct {
+	ProductVariantID int  `gorm:"primaryKey"`
+	OptionValueID    uint `gorm:"primaryKey"`
+}
+
+type NewOptionType struct {
+	Name   string   `json:"name" binding:"required,max=50"`
+	Values []string `json:"values" binding:"dive,required,max=50"`
+}
+
+type NewOptionValue struct {
+	Value string `json:"value" binding:"required,max=50"`
+}
+
+// OptionType builds the option type with its values in the given order,
+// values are unique without regard to case.
+func (newType NewOptionType) OptionType(categoryId *uint) (OptionType, error) {
+	optionType := OptionType{CategoryID: categoryId, Name: strings.TrimSpace(newType.Name)}
+	if optionType.Name == "" {
+		return OptionType{}, errors.New("name is required")
+	}
+
+	seen := map[string]bool{}
+	for _, value := range newType.Values {
+		value = strings.TrimSpace(value)
+		if seen[strings.ToLower(value)] {
+			return OptionType{}, errors.New("value " + value + " is listed twice")
+		}
+		seen[strings.ToLower(value)] = true
+		optionType.Values = append(optionType.Values, &OptionValue{Value: value, Position: len(optionType.Values)})
+	}
+	return optionType, nil
+}
+
+// OptionKey identifies a set of option values regardless of their order, no
+// two live variants of a product may share one.
+func OptionKey(values []*OptionValue) (string, error) {
+	types := map[uint]bool{}
+	ids := make([]int, 0, len(values))
+	for _, value := range values {
+		if types[value.OptionTypeID] {
+			return "", errors.New("a variant can only have one value per option type")
+		}
+		types[value.OptionTypeID] = true
+		ids = append(ids, int(value.ID))
+	}
+	sort.Ints(ids)
+
+	key := make([]string, len(ids))
+	for i, id := range ids {
+		key[i] = strconv.Itoa(id)
+	}
+	return strings.Join(key, "-"), nil
+}
+
+// LegacyOptions are the option types whose values are mirrored into the size
+// and color columns of a variant.
+var LegacyOptions = []string{"Size", "Color"}
+
+// SyncLegacyOptions copies the size and color option values into the Size and
+// Color columns that orders, stock and search still read.
+func (variant *ProductVariant) SyncLegacyOptions() {
+	for _, value := range variant.OptionValues {
+		if value.OptionType == nil {
+			continue
+		}
+		switch {
+		case strings.EqualFold(value.OptionType.Name, LegacyOptions[0]):
+			variant.Size = value.Value
+		case strings.EqualFold(value.OptionType.Name, LegacyOptions[1]):
+			variant.Color = value.Value
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package domain_test
+
+import (
+	"project/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewOptionType(t *testing.T) {
+	t.Run("Builds values in order", func(t *testing.T) {
+		optionType, err := domain.NewOptionType{Name: " Weight ", Values: []string{"500g", "1kg"}}.OptionType(uintPtr(2))
+		assert.NoError(t, err)
+		assert.Equal(t, "Weight", optionType.Name)
+		assert.Equal(t, uint(2), *optionType.CategoryID)
+		assert.Len(t, optionType.Values, 2)
+		assert.Equal(t, "1kg", optionType.Values[1].Value)
+		assert.Equal(t, 1, optionType.Values[1].Position)
+	})
+
+	t.Run("Duplicate values", func(t *testing.T) {
+		_, err := domain.NewOptionType{Name: "Breed", Values: []string{"Angus", "angus"}}.OptionType(nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestOptionKey(t *testing.T) {
+	t.Run("Order independent", func(t *testing.T) {
+		a, err := domain.OptionKey([]*domain.OptionValue{{ID: 12, OptionTypeID: 1}, {ID: 3, OptionTypeID: 2}})
+		assert.NoError(t, err)
+		b, _ := domain.OptionKey([]*domain.OptionValue{{ID: 3, OptionTypeID: 2}, {ID: 12, OptionTypeID: 1}})
+		assert.Equal(t, "3-12", a)
+		assert.Equal(t, a, b)
+	})
+
+	t.Run("One value per type", func(t *testing.T) {
+		_, err := domain.OptionKey([]*domain.OptionValue{{ID: 1, OptionTypeID: 1}, {ID: 2, OptionTypeID: 1}})
+		assert.Error(t, err)
+	})
+}
+
+func TestSyncLegacyOptions(t *testing.T) {
+	variant := domain.ProductVariant{OptionValues: []*domain.OptionValue{
+		{Value: "XL", OptionType: &domain.OptionType{Name: "size"}},
+		{Value: "Blue", OptionType: &domain.OptionType{Name: "Color"}},
+		{Value: "1kg", OptionType: &domain.OptionType{Name: "Weight"}},
+	}}
+	variant.SyncLegacyOptions()
+	assert.Equal(t, "XL", variant.Size)
+	assert.Equal(t, "Blue", variant.Color)
+}
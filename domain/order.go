@@ -12,14 +12,27 @@ const (
 	Processed Status = "processed"
 	Canceled  Status = "canceled"
 	Completed Status = "completed"
+	Delivered Status = "delivered"
 )
 
+func (status Status) Valid() bool {
+	switch status {
+	case Created, Processed, Canceled, Completed, Delivered:
+		return true
+	}
+	return false
+}
+
 type Order struct {
 	ID             uint        `gorm:"primaryKey;autoIncrement" json:"id"`
 	CustomerID     uint        `json:"-"`
 	Customer       Customer    `json:"customer"`
 	PaymentMethod  string      `json:"payment_method"`
 	TrackingNumber string      `json:"tracking_number"`
+	PromotionID    *uint       `json:"-"`
+	VoucherCode    string      `json:"voucher_code,omitempty"`
+	Discount       float64     `gorm:"type:float;default:0" json:"discount"`
+	InvoiceNumber  *uint       `gorm:"uniqueIndex" json:"invoice_number,omitempty"`
 	Status         Status      `gorm:"type:orderstatus" json:"status"`
 	Items          []OrderItem `gorm:"foreignKey:OrderID" json:"items"`
 	CreatedAt      time.Time   `gorm:"autoCreateTime" json:"created_at"`
@@ -56,6 +69,20 @@ func (order *Order) Ship(confirmation OrderConfirmation) error {
 	return nil
 }
 
+func (order *Order) Deliver() error {
+	if order.Status != Completed {
+		return errors.New("only shipped orders can be delivered")
+	}
+	order.Status = Delivered
+	return nil
+}
+
+// StockDeducted reports whether the stock of the order items has been taken,
+// which happens when the order is processed.
+func (order *Order) StockDeducted() bool {
+	return order.Status == Processed || order.Status == Completed || order.Status == Delivered
+}
+
 func (order *Order) setTrackingNumber(trackingNumber string) error {
 	if trackingNumber == "" {
 		return errors.New("invalid tracking number")
@@ -63,3 +90,25 @@ func (order *Order) setTrackingNumber(trackingNumber string) error {
 	order.TrackingNumber = trackingNumber
 	return nil
 }
+
+func (order *Order) Subtotal() float64 {
+	var subtotal float64
+	for _, item := range order.Items {
+		subtotal += item.UnitPrice * float64(item.Quantity)
+	}
+	return subtotal
+}
+
+// ApplyPromotion discounts the order by what the promotion takes off the cart
+// the order was priced from, used is how many times the customer has already
+// used the promotion.
+func (order *Order) ApplyPromotion(promotion Promotion, cart Cart, used int, now time.Time) error {
+	discount, err := promotion.Evaluate(cart, used, now)
+	if err != nil {
+		return err
+	}
+	order.PromotionID = &promotion.ID
+	order.VoucherCode = promotion.VoucherCode
+	order.Discount = discount
+	return nil
+}
@@ -0,0 +1,71 @@
+package domain_test
+
+import (
+	"project/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func voucher() domain.Promotion {
+	return domain.Promotion{
+		ID:          2,
+		Type:        domain.Voucher,
+		Status:      domain.Active,
+		StartDate:   "2024-12-15T00:00:00Z",
+		EndDate:     "2024-12-22T00:00:00Z",
+		VoucherCode: "CGDG",
+		Limit:       1,
+		Percentage:  30,
+	}
+}
+
+func TestOrderApplyPromotion(t *testing.T) {
+	now := time.Date(2024, 12, 20, 10, 0, 0, 0, time.UTC)
+	order := domain.Order{Items: []domain.OrderItem{
+		{Quantity: 2, UnitPrice: 100000},
+		{Quantity: 1, UnitPrice: 50000},
+	}}
+	cart := domain.Cart{Lines: []domain.CartLine{
+		{VariantID: 1, ProductID: 1, Quantity: 2, UnitPrice: 100000},
+		{VariantID: 2, ProductID: 2, Quantity: 1, UnitPrice: 50000},
+	}}
+
+	t.Run("Successfully apply voucher", func(t *testing.T) {
+		err := order.ApplyPromotion(voucher(), cart, 0, now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, float64(250000), order.Subtotal())
+		assert.Equal(t, float64(75000), order.Discount)
+		assert.Equal(t, "CGDG", order.VoucherCode)
+	})
+
+	t.Run("Failed to apply expired voucher", func(t *testing.T) {
+		err := order.ApplyPromotion(voucher(), cart, 0, now.AddDate(0, 1, 0))
+
+		assert.EqualError(t, err, "voucher is not valid today")
+	})
+
+	t.Run("Failed to apply used up voucher", func(t *testing.T) {
+		promotion := voucher()
+		promotion.Limit = 0
+
+		err := order.ApplyPromotion(promotion, cart, 0, now)
+
+		assert.EqualError(t, err, "voucher has been fully used")
+	})
+}
+
+func TestNewOrderLines(t *testing.T) {
+	newOrder := domain.NewOrder{Items: []domain.NewOrderItem{
+		{VariantID: 1, Quantity: 2},
+		{VariantID: 2, Quantity: 1},
+		{VariantID: 1, Quantity: 3},
+	}}
+
+	assert.Equal(t, []domain.NewOrderItem{
+		{VariantID: 1, Quantity: 5},
+		{VariantID: 2, Quantity: 1},
+	}, newOrder.Lines())
+}
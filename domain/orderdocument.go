@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+type OrderDocument string
+
+const (
+	Invoice     OrderDocument = "invoice"
+	PackingSlip OrderDocument = "packing-slip"
+)
+
+type OrderDocumentRequest struct {
+	OrderIDs []uint        `json:"order_ids" binding:"required,min=1"`
+	Document OrderDocument `json:"document" binding:"required,oneof=invoice packing-slip"`
+}
+
+var ErrNotInvoiceable = errors.New("order can't be invoiced before it's processed")
+
+// Invoiceable reports whether an order has been paid for, only those get an
+// invoice number.
+func (order *Order) Invoiceable() bool {
+	return order.Status == Processed || order.Status == Completed || order.Status == Delivered
+}
+
+func (order OrderTotal) InvoiceCode() string {
+	if order.InvoiceNumber == nil {
+		return ""
+	}
+	return fmt.Sprintf("INV-%06d", *order.InvoiceNumber)
+}
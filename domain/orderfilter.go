@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+type OrderFilter struct {
+	CustomerID    uint     `form:"customer_id" json:"customer_id,omitempty"`
+	Status        string   `form:"status" json:"status,omitempty"`
+	PaymentMethod string   `form:"payment_method" json:"payment_method,omitempty"`
+	CustomerName  string   `form:"customer_name" json:"customer_name,omitempty"`
+	DateFrom      string   `form:"date_from" json:"date_from,omitempty"`
+	DateTo        string   `form:"date_to" json:"date_to,omitempty"`
+	MinTotal      *float64 `form:"min_total" json:"min_total,omitempty"`
+	MaxTotal      *float64 `form:"max_total" json:"max_total,omitempty"`
+	Search        string   `form:"search" json:"search,omitempty"`
+	SortBy        string   `form:"sort_by" json:"sort_by"`
+	SortOrder     string   `form:"sort_order" json:"sort_order"`
+}
+
+var orderSortColumns = map[string]string{
+	"created_at": "created_at",
+	"total":      "total",
+}
+
+// Validate checks the filter values and fills in the default sorting.
+func (filter *OrderFilter) Validate() error {
+	if filter.SortBy == "" {
+		filter.SortBy = "created_at"
+	}
+	if _, ok := orderSortColumns[filter.SortBy]; !ok {
+		return errors.New("sort_by must be created_at or total")
+	}
+
+	if filter.SortOrder == "" {
+		filter.SortOrder = "desc"
+	}
+	if filter.SortOrder != "asc" && filter.SortOrder != "desc" {
+		return errors.New("sort_order must be asc or desc")
+	}
+
+	if filter.Status != "" && !Status(filter.Status).Valid() {
+		return errors.New("status must be created, processed, canceled, completed or delivered")
+	}
+
+	for _, date := range []string{filter.DateFrom, filter.DateTo} {
+		if _, err := time.Parse("2006-01-02", date); date != "" && err != nil {
+			return errors.New("dates must use the yyyy-mm-dd format")
+		}
+	}
+
+	if filter.MinTotal != nil && filter.MaxTotal != nil && *filter.MinTotal > *filter.MaxTotal {
+		return errors.New("min_total can't be greater than max_total")
+	}
+	return nil
+}
+
+func (filter OrderFilter) OrderBy() string {
+	return orderSortColumns[filter.SortBy] + " " + filter.SortOrder
+}
@@ -0,0 +1,55 @@
+package domain_test
+
+import (
+	"project/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderFilterValidate(t *testing.T) {
+	t.Run("Sort by newest by default", func(t *testing.T) {
+		filter := domain.OrderFilter{}
+		assert.NoError(t, filter.Validate())
+		assert.Equal(t, "created_at", filter.SortBy)
+		assert.Equal(t, "desc", filter.SortOrder)
+		assert.Equal(t, "created_at desc", filter.OrderBy())
+	})
+
+	t.Run("Successfully sort by total ascending", func(t *testing.T) {
+		filter := domain.OrderFilter{SortBy: "total", SortOrder: "asc"}
+		assert.NoError(t, filter.Validate())
+		assert.Equal(t, "total asc", filter.OrderBy())
+	})
+
+	t.Run("Failed with an unknown sort", func(t *testing.T) {
+		filter := domain.OrderFilter{SortBy: "customer_name"}
+		assert.EqualError(t, filter.Validate(), "sort_by must be created_at or total")
+	})
+
+	t.Run("Failed with an unknown sort order", func(t *testing.T) {
+		filter := domain.OrderFilter{SortOrder: "up"}
+		assert.EqualError(t, filter.Validate(), "sort_order must be asc or desc")
+	})
+
+	t.Run("Successfully filter by status", func(t *testing.T) {
+		filter := domain.OrderFilter{Status: "delivered"}
+		assert.NoError(t, filter.Validate())
+	})
+
+	t.Run("Failed with an unknown status", func(t *testing.T) {
+		filter := domain.OrderFilter{Status: "shipped"}
+		assert.EqualError(t, filter.Validate(), "status must be created, processed, canceled, completed or delivered")
+	})
+
+	t.Run("Failed with a badly formatted date", func(t *testing.T) {
+		filter := domain.OrderFilter{DateFrom: "2024-01-01", DateTo: "01/31/2024"}
+		assert.EqualError(t, filter.Validate(), "dates must use the yyyy-mm-dd format")
+	})
+
+	t.Run("Failed with an inverted total range", func(t *testing.T) {
+		min, max := 200000.0, 100000.0
+		filter := domain.OrderFilter{MinTotal: &min, MaxTotal: &max}
+		assert.EqualError(t, filter.Validate(), "min_total can't be greater than max_total")
+	})
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+type NoteVisibility string
+
+const (
+	NoteInternal NoteVisibility = "internal"
+	NoteCustomer NoteVisibility = "customer"
+)
+
+func (visibility NoteVisibility) Valid() bool {
+	return visibility == NoteInternal || visibility == NoteCustomer
+}
+
+var ErrNotNoteAuthor = errors.New("only the author can change this note")
+
+type OrderNote struct {
+	ID         uint           `gorm:"primaryKey;autoIncrement" json:"id"`
+	OrderID    uint           `gorm:"not null;index" json:"order_id"`
+	AuthorID   uint           `gorm:"not null" json:"-"`
+	Author     NoteAuthor     `gorm:"foreignKey:AuthorID" json:"author"`
+	Visibility NoteVisibility `gorm:"type:varchar(20);default:internal" json:"visibility"`
+	Content    string         `gorm:"not null" json:"content"`
+	CreatedAt  time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// NoteAuthor is the public part of the user who wrote a note.
+type NoteAuthor struct {
+	ID       uint   `json:"id"`
+	FullName string `json:"full_name"`
+}
+
+func (NoteAuthor) TableName() string {
+	return "users"
+}
+
+type NewOrderNote struct {
+	Visibility NoteVisibility `json:"visibility" binding:"omitempty,oneof=internal customer"`
+	Content    string         `json:"content" binding:"required"`
+}
+
+func (note *OrderNote) Edit(authorId uint, edit NewOrderNote) error {
+	if note.AuthorID != authorId {
+		return ErrNotNoteAuthor
+	}
+	note.Content = edit.Content
+	if edit.Visibility != "" {
+		note.Visibility = edit.Visibility
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package domain_test
+
+import (
+	"project/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderNoteEdit(t *testing.T) {
+	note := domain.OrderNote{AuthorID: 1, Visibility: domain.NoteInternal, Content: "customer called"}
+
+	t.Run("Successfully edit own note", func(t *testing.T) {
+		err := note.Edit(1, domain.NewOrderNote{Content: "customer called, deliver after 5pm"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "customer called, deliver after 5pm", note.Content)
+		assert.Equal(t, domain.NoteInternal, note.Visibility)
+	})
+
+	t.Run("Failed to edit a note of another user", func(t *testing.T) {
+		err := note.Edit(2, domain.NewOrderNote{Content: "changed", Visibility: domain.NoteCustomer})
+
+		assert.ErrorIs(t, err, domain.ErrNotNoteAuthor)
+		assert.Equal(t, domain.NoteInternal, note.Visibility)
+	})
+}
+
+func TestOrderHideInternalNotes(t *testing.T) {
+	order := domain.OrderTotal{Notes: []domain.OrderNote{
+		{ID: 1, Visibility: domain.NoteInternal, Content: "customer called"},
+		{ID: 2, Visibility: domain.NoteCustomer, Content: "gift wrapped"},
+	}}
+
+	order.HideInternalNotes()
+
+	assert.Len(t, order.Notes, 1)
+	assert.Equal(t, uint(2), order.Notes[0].ID)
+}
+
+func TestNoteVisibilityValid(t *testing.T) {
+	assert.True(t, domain.NoteInternal.Valid())
+	assert.True(t, domain.NoteCustomer.Valid())
+	assert.False(t, domain.NoteVisibility("public").Valid())
+}
@@ -1,11 +1,31 @@
 package domain
 
+import "time"
+
 type OrderTotal struct {
 	ID              uint                `json:"id"`
 	CustomerName    string              `json:"customer_name"`
 	CustomerAddress string              `json:"customer_address"`
 	PaymentMethod   string              `json:"payment_method"`
+	Subtotal        float32             `json:"subtotal"`
+	Discount        float32             `json:"discount"`
 	Total           float32             `json:"total"`
 	Status          string              `json:"status"`
+	TrackingNumber  string              `json:"tracking_number"`
+	InvoiceNumber   *uint               `json:"invoice_number,omitempty"`
+	CreatedAt       time.Time           `json:"created_at"`
+	CustomerID      uint                `json:"customer_id"`
 	Items           []OrderItemSubtotal `gorm:"foreignKey:OrderID" json:"items"`
+	Notes           []OrderNote         `gorm:"foreignKey:OrderID" json:"notes,omitempty"`
+}
+
+// HideInternalNotes keeps only the notes meant for the customer.
+func (order *OrderTotal) HideInternalNotes() {
+	notes := []OrderNote{}
+	for _, note := range order.Notes {
+		if note.Visibility == NoteCustomer {
+			notes = append(notes, note)
+		}
+	}
+	order.Notes = notes
 }
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+type PaymentStatus string
+
+const (
+	PaymentPending  PaymentStatus = "pending"
+	PaymentPaid     PaymentStatus = "paid"
+	PaymentFailed   PaymentStatus = "failed"
+	PaymentRefunded PaymentStatus = "refunded"
+)
+
+type Payment struct {
+	ID                uint          `gorm:"primaryKey;autoIncrement" json:"id"`
+	OrderID           uint          `gorm:"not null;index" json:"order_id"`
+	Amount            float64       `gorm:"type:float;not null" json:"amount"`
+	Method            string        `gorm:"type:varchar(50)" json:"method"`
+	Provider          string        `gorm:"type:varchar(50);not null" json:"provider"`
+	ProviderReference string        `gorm:"type:varchar(100);uniqueIndex" json:"provider_reference"`
+	PaymentURL        string        `json:"payment_url,omitempty"`
+	Status            PaymentStatus `gorm:"type:varchar(20);default:pending" json:"status"`
+	PaidAt            *time.Time    `json:"paid_at"`
+	CreatedAt         time.Time     `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time     `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+type NewPayment struct {
+	Provider string `json:"provider" binding:"required"`
+	Method   string `json:"method"`
+}
+
+// PaymentMismatch is an order whose paid amount differs from its total.
+type PaymentMismatch struct {
+	OrderID    uint    `json:"order_id"`
+	Status     string  `json:"status"`
+	Total      float64 `json:"total"`
+	Paid       float64 `json:"paid"`
+	Difference float64 `json:"difference"`
+}
+
+func (payment *Payment) MarkPaid(amount float64, at time.Time) error {
+	if payment.Status != PaymentPending {
+		return fmt.Errorf("payment with status %s can't be paid", payment.Status)
+	}
+	// providers settle the amount that was actually captured
+	if amount > 0 {
+		payment.Amount = amount
+	}
+	payment.Status = PaymentPaid
+	payment.PaidAt = &at
+	return nil
+}
+
+func (payment *Payment) MarkFailed() error {
+	if payment.Status != PaymentPending {
+		return fmt.Errorf("payment with status %s can't fail", payment.Status)
+	}
+	payment.Status = PaymentFailed
+	return nil
+}
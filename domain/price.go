@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// PriceSource tells what changed the price of a product.
+type PriceSource string
+
+const (
+	PriceCreated   PriceSource = "created"
+	PriceManual    PriceSource = "manual"
+	PriceScheduled PriceSource = "scheduled"
+	PriceSale      PriceSource = "sale"
+)
+
+// PriceHistory records the price and sale of a product from ChangedAt until
+// the next change.
+type PriceHistory struct {
+	ID           uint        `gorm:"primaryKey" json:"id"`
+	ProductID    int         `gorm:"not null;index" json:"product_id"`
+	Price        float64     `gorm:"not null" json:"price"`
+	SalePrice    *float64    `json:"sale_price"`
+	SaleStartsAt *time.Time  `json:"sale_starts_at"`
+	SaleEndsAt   *time.Time  `json:"sale_ends_at"`
+	Source       PriceSource `gorm:"type:varchar(20);not null" json:"source"`
+	ChangedAt    time.Time   `gorm:"not null;index" json:"changed_at"`
+}
+
+func NewPriceHistory(product Product, source PriceSource, now time.Time) PriceHistory {
+	return PriceHistory{
+		ProductID:    product.ID,
+		Price:        product.Price,
+		SalePrice:    product.SalePrice,
+		SaleStartsAt: product.SaleStartsAt,
+		SaleEndsAt:   product.SaleEndsAt,
+		Source:       source,
+		ChangedAt:    now,
+	}
+}
+
+// ScheduledPrice changes the price of a product at EffectiveAt, the cron
+// applies it and sets AppliedAt.
+type ScheduledPrice struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	ProductID   int        `gorm:"not null;index" json:"product_id"`
+	Price       float64    `gorm:"not null" json:"price" binding:"required,gt=0"`
+	EffectiveAt time.Time  `gorm:"not null;index" json:"effective_at" binding:"required" example:"2026-12-01T00:00:00+07:00"`
+	AppliedAt   *time.Time `json:"applied_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+func (scheduled ScheduledPrice) Validate(now time.Time) error {
+	if !scheduled.EffectiveAt.After(now) {
+		return errors.New("effective_at must be in the future")
+	}
+	return nil
+}
+
+// ProductSale puts a product on sale between StartsAt and EndsAt, either may
+// be left open. Without a sale price the sale ends.
+type ProductSale struct {
+	SalePrice *float64   `json:"sale_price" binding:"omitempty,gt=0"`
+	StartsAt  *time.Time `json:"starts_at" example:"2026-12-01T00:00:00+07:00"`
+	EndsAt    *time.Time `json:"ends_at" example:"2026-12-08T00:00:00+07:00"`
+}
+
+func (sale ProductSale) Apply(product *Product) error {
+	if sale.SalePrice == nil {
+		product.SalePrice, product.SaleStartsAt, product.SaleEndsAt = nil, nil, nil
+		return nil
+	}
+	if *sale.SalePrice >= product.Price {
+		return errors.New("sale price must be lower than the price")
+	}
+	if sale.StartsAt != nil && sale.EndsAt != nil && !sale.EndsAt.After(*sale.StartsAt) {
+		return errors.New("sale must end after it starts")
+	}
+	product.SalePrice, product.SaleStartsAt, product.SaleEndsAt = sale.SalePrice, sale.StartsAt, sale.EndsAt
+	return nil
+}
@@ -0,0 +1,75 @@
+package domain_test
+
+import (
+	"project/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductSale(t *testing.T) {
+	now := time.Date(2026, 12, 1, 10, 0, 0, 0, time.UTC)
+	start, end := now.Add(-time.Hour), now.Add(time.Hour)
+
+	t.Run("Sale price applies within its window", func(t *testing.T) {
+		product := domain.Product{Price: 100000}
+		sale := 75000.0
+		assert.NoError(t, domain.ProductSale{SalePrice: &sale, StartsAt: &start, EndsAt: &end}.Apply(&product))
+
+		assert.Equal(t, sale, product.PriceAt(now))
+		assert.Equal(t, float64(100000), product.PriceAt(start.Add(-time.Minute)))
+		assert.Equal(t, float64(100000), product.PriceAt(end))
+	})
+
+	t.Run("Open ended sale", func(t *testing.T) {
+		product := domain.Product{Price: 100000}
+		sale := 75000.0
+		assert.NoError(t, domain.ProductSale{SalePrice: &sale}.Apply(&product))
+		assert.True(t, product.OnSale(now))
+	})
+
+	t.Run("Sale price must be lower", func(t *testing.T) {
+		product := domain.Product{Price: 100000}
+		sale := 100000.0
+		assert.Error(t, domain.ProductSale{SalePrice: &sale}.Apply(&product))
+	})
+
+	t.Run("Sale must end after it starts", func(t *testing.T) {
+		product := domain.Product{Price: 100000}
+		sale := 75000.0
+		assert.Error(t, domain.ProductSale{SalePrice: &sale, StartsAt: &end, EndsAt: &start}.Apply(&product))
+	})
+
+	t.Run("No sale price ends the sale", func(t *testing.T) {
+		sale := 75000.0
+		product := domain.Product{Price: 100000, SalePrice: &sale, SaleEndsAt: &end}
+		assert.NoError(t, domain.ProductSale{}.Apply(&product))
+		assert.Nil(t, product.SalePrice)
+		assert.Nil(t, product.SaleEndsAt)
+		assert.False(t, product.OnSale(now))
+	})
+
+	t.Run("Price raised above the sale price", func(t *testing.T) {
+		sale := 120000.0
+		product := domain.Product{Price: 100000, SalePrice: &sale}
+		assert.Equal(t, float64(100000), product.PriceAt(now))
+	})
+}
+
+func TestScheduledPrice(t *testing.T) {
+	now := time.Now()
+	assert.NoError(t, domain.ScheduledPrice{Price: 90000, EffectiveAt: now.Add(time.Hour)}.Validate(now))
+	assert.Error(t, domain.ScheduledPrice{Price: 90000, EffectiveAt: now}.Validate(now))
+}
+
+func TestNewPriceHistory(t *testing.T) {
+	sale := 75000.0
+	now := time.Now()
+	history := domain.NewPriceHistory(domain.Product{ID: 3, Price: 100000, SalePrice: &sale}, domain.PriceSale, now)
+	assert.Equal(t, 3, history.ProductID)
+	assert.Equal(t, float64(100000), history.Price)
+	assert.Equal(t, &sale, history.SalePrice)
+	assert.Equal(t, domain.PriceSale, history.Source)
+	assert.Equal(t, now, history.ChangedAt)
+}
@@ -7,17 +7,45 @@ import (
 )
 
 type Product struct {
-	ID          int             `gorm:"primaryKey;autoIncrement" json:"id"`
-	Name        string          `gorm:"type:varchar(50);not null" json:"name" binding:"required,min=5"`
-	SKUProduct  string          `gorm:"type:varchar(100);unique;not null" json:"sku_product" binding:"required"`
-	Price       float64         `gorm:"not null" json:"price" binding:"required"`
-	Description string          `gorm:"type:text;not null" json:"description" binding:"required"`
-	CreatedAt   time.Time       `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt   time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
-	DeletedAt   *gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty" swaggerignore:"true"`
+	ID         int     `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name       string  `gorm:"type:varchar(50);not null" json:"name" binding:"required,min=5"`
+	SKUProduct string  `gorm:"type:varchar(100);unique;not null" json:"sku_product" binding:"required"`
+	Price      float64 `gorm:"not null" json:"price" binding:"required"`
+	// SalePrice replaces the price between SaleStartsAt and SaleEndsAt.
+	SalePrice    *float64        `json:"sale_price"`
+	SaleStartsAt *time.Time      `json:"sale_starts_at"`
+	SaleEndsAt   *time.Time      `json:"sale_ends_at"`
+	Description  string          `gorm:"type:text;not null" json:"description" binding:"required"`
+	CategoryID   *uint           `gorm:"index" json:"category_id"`
+	Status       ProductStatus   `gorm:"type:varchar(20);not null;default:active;index" json:"status"`
+	PublishAt    *time.Time      `gorm:"index" json:"publish_at"`
+	CreatedAt    time.Time       `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt    *gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty" swaggerignore:"true"`
 
 	Image          []*Image          `gorm:"foreignKey:ProductID" json:"image"`
 	ProductVariant []*ProductVariant `gorm:"foreignKey:ProductID" json:"product_variant"`
+	Categories     []*Category       `gorm:"many2many:product_categories" json:"categories,omitempty"`
+	Rating         *ProductRating    `gorm:"-" json:"rating,omitempty"`
+}
+
+// OnSale reports whether the sale price applies at the given time.
+func (product Product) OnSale(now time.Time) bool {
+	if product.SalePrice == nil || *product.SalePrice >= product.Price {
+		return false
+	}
+	if product.SaleStartsAt != nil && now.Before(*product.SaleStartsAt) {
+		return false
+	}
+	return product.SaleEndsAt == nil || now.Before(*product.SaleEndsAt)
+}
+
+// PriceAt is the price the product sells at, its sale price during the sale.
+func (product Product) PriceAt(now time.Time) float64 {
+	if product.OnSale(now) {
+		return *product.SalePrice
+	}
+	return product.Price
 }
 
 func SeedProducts() []Product {
@@ -180,6 +208,12 @@ func SeedProducts() []Product {
 		},
 	}
 
+	for i := range products {
+		categoryID := seedCategoryOf(i + 1)
+		products[i].CategoryID = &categoryID
+		products[i].Status = ProductActive
+	}
+
 	return products
 }
 
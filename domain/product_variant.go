@@ -2,7 +2,9 @@ package domain
 
 import (
 	"errors"
+	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"golang.org/x/exp/rand"
@@ -11,13 +13,111 @@ import (
 
 type ProductVariant struct {
 	ID        int             `gorm:"primaryKey;autoIncrement" json:"id"`
-	ProductID int             `gorm:"not null" json:"product_id"`
-	Size      string          `gorm:"type:varchar(50)" json:"size"`
-	Color     string          `gorm:"type:varchar(50)" json:"color"`
+	ProductID int             `gorm:"not null;index:idx_variant_option,unique,where:deleted_at IS NULL AND option_key = ''" json:"product_id"`
+	SKU       string          `gorm:"type:varchar(100);index:idx_variant_sku,unique,where:deleted_at IS NULL AND sku <> ''" json:"sku"`
+	Size      string          `gorm:"type:varchar(50);index:idx_variant_option" json:"size"`
+	Color     string          `gorm:"type:varchar(50);index:idx_variant_option" json:"color"`
+	Price     *float64        `json:"price"`
+	Weight    float64         `gorm:"not null;default:0" json:"weight"`
 	Stock     int             `gorm:"default:0;check:stock>=0" json:"stock"`
+	OptionKey string          `gorm:"type:varchar(200);index:idx_variant_option_key,unique,where:deleted_at IS NULL AND option_key <> ''" json:"-"`
 	CreatedAt time.Time       `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
 	DeletedAt *gorm.DeletedAt `gorm:"index" json:"deleted_at" swaggerignore:"true"`
+
+	OptionValues []*OptionValue `gorm:"many2many:variant_option_values" json:"option_values,omitempty"`
+}
+
+// ProductVariantInput holds the editable fields of a variant. A nil Price
+// sells the variant at the product price. When OptionValueIDs is given the
+// variant is defined by those values, and size and color follow from them.
+type ProductVariantInput struct {
+	SKU            string   `json:"sku" binding:"max=100"`
+	Size           string   `json:"size" binding:"max=50"`
+	Color          string   `json:"color" binding:"max=50"`
+	Price          *float64 `json:"price" binding:"omitempty,gt=0"`
+	Weight         float64  `json:"weight" binding:"gte=0"`
+	OptionValueIDs []uint   `json:"option_value_ids"`
+}
+
+// NewProductVariant also takes the opening stock, later changes go through
+// the stock ledger.
+type NewProductVariant struct {
+	ProductVariantInput
+	Stock int `json:"stock" binding:"gte=0"`
+}
+
+func (input ProductVariantInput) Apply(variant *ProductVariant) {
+	variant.SKU = strings.TrimSpace(input.SKU)
+	variant.Size = strings.TrimSpace(input.Size)
+	variant.Color = strings.TrimSpace(input.Color)
+	variant.Price = input.Price
+	variant.Weight = input.Weight
+
+	variant.OptionValues = nil
+	for _, id := range input.OptionValueIDs {
+		variant.OptionValues = append(variant.OptionValues, &OptionValue{ID: id})
+	}
+}
+
+// PriceFor is the price the variant sells at, its override or else the
+// product price at the given time.
+func (variant ProductVariant) PriceFor(product Product, now time.Time) float64 {
+	if variant.Price != nil {
+		return *variant.Price
+	}
+	return product.PriceAt(now)
+}
+
+// SameOption reports whether both variants have the same size and color,
+// which must be unique within a product.
+func (variant ProductVariant) SameOption(other ProductVariant) bool {
+	return strings.EqualFold(variant.Size, other.Size) && strings.EqualFold(variant.Color, other.Color)
+}
+
+// VariantGenerator creates one variant for every size and color combination.
+type VariantGenerator struct {
+	Sizes  []string `json:"sizes" binding:"required,min=1,dive,required,max=50"`
+	Colors []string `json:"colors" binding:"required,min=1,dive,required,max=50"`
+	Price  *float64 `json:"price" binding:"omitempty,gt=0"`
+	Weight float64  `json:"weight" binding:"gte=0"`
+	Stock  int      `json:"stock" binding:"gte=0"`
+}
+
+// Variants builds the cartesian product of the sizes and colors, skipping
+// duplicates and the combinations the product already has. SKUs follow the
+// product SKU, e.g. SKU-001-M-RED.
+func (generator VariantGenerator) Variants(product Product, existing []ProductVariant) []ProductVariant {
+	variants := []ProductVariant{}
+	for _, size := range generator.Sizes {
+		for _, color := range generator.Colors {
+			variant := ProductVariant{
+				ProductID: product.ID,
+				Size:      strings.TrimSpace(size),
+				Color:     strings.TrimSpace(color),
+				Price:     generator.Price,
+				Weight:    generator.Weight,
+				Stock:     generator.Stock,
+			}
+			variant.SKU = strings.ToUpper(strings.ReplaceAll(
+				fmt.Sprintf("%s-%s-%s", product.SKUProduct, variant.Size, variant.Color), " ", ""))
+
+			if hasOption(existing, variant) || hasOption(variants, variant) {
+				continue
+			}
+			variants = append(variants, variant)
+		}
+	}
+	return variants
+}
+
+func hasOption(variants []ProductVariant, variant ProductVariant) bool {
+	for _, other := range variants {
+		if other.SameOption(variant) {
+			return true
+		}
+	}
+	return false
 }
 
 func (variant *ProductVariant) DeductStock(quantity uint) error {
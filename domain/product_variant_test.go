@@ -0,0 +1,49 @@
+package domain_test
+
+import (
+	"project/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVariantGenerator(t *testing.T) {
+	product := domain.Product{ID: 1, SKUProduct: "SKU-001", Price: 100000}
+
+	t.Run("Successfully generate every size and color", func(t *testing.T) {
+		generator := domain.VariantGenerator{Sizes: []string{"S", "M"}, Colors: []string{"Red", "Navy Blue"}, Stock: 5}
+
+		variants := generator.Variants(product, nil)
+
+		assert.Len(t, variants, 4)
+		assert.Equal(t, "SKU-001-S-RED", variants[0].SKU)
+		assert.Equal(t, "SKU-001-M-NAVYBLUE", variants[3].SKU)
+		assert.Equal(t, 5, variants[3].Stock)
+	})
+
+	t.Run("Skip duplicates and existing combinations", func(t *testing.T) {
+		generator := domain.VariantGenerator{Sizes: []string{"S", "s", "M"}, Colors: []string{"Red"}}
+		existing := []domain.ProductVariant{{Size: "M", Color: "red"}}
+
+		variants := generator.Variants(product, existing)
+
+		assert.Len(t, variants, 1)
+		assert.Equal(t, "S", variants[0].Size)
+	})
+}
+
+func TestProductVariantPriceFor(t *testing.T) {
+	product := domain.Product{Price: 100000}
+	override := 120000.0
+
+	now := time.Now()
+
+	assert.Equal(t, float64(100000), domain.ProductVariant{}.PriceFor(product, now))
+	assert.Equal(t, override, domain.ProductVariant{Price: &override}.PriceFor(product, now))
+
+	sale := 80000.0
+	product.SalePrice = &sale
+	assert.Equal(t, sale, domain.ProductVariant{}.PriceFor(product, now))
+	assert.Equal(t, override, domain.ProductVariant{Price: &override}.PriceFor(product, now))
+}
@@ -0,0 +1,83 @@
+package domain
+
+import "errors"
+
+type ProductSearch struct {
+	Query      string   `form:"q" json:"q,omitempty"`
+	CategoryID *uint    `form:"category_id" json:"category_id,omitempty"`
+	MinPrice   *float64 `form:"min_price" json:"min_price,omitempty"`
+	MaxPrice   *float64 `form:"max_price" json:"max_price,omitempty"`
+	Size       string   `form:"size" json:"size,omitempty"`
+	Color      string   `form:"color" json:"color,omitempty"`
+	InStock    bool     `form:"in_stock" json:"in_stock,omitempty"`
+	// Attributes filters on variant option values by option type name, bound
+	// from attr[name]=value query parameters
+	Attributes map[string]string `form:"-" json:"attributes,omitempty"`
+	SortBy     string            `form:"sort_by" json:"sort_by"`
+	SortOrder  string            `form:"sort_order" json:"sort_order"`
+}
+
+const (
+	SortRelevance   = "relevance"
+	SortPrice       = "price"
+	SortName        = "name"
+	SortNewest      = "newest"
+	SortBestSelling = "best_selling"
+)
+
+var productSortOrders = map[string]string{
+	SortRelevance:   "desc",
+	SortPrice:       "asc",
+	SortName:        "asc",
+	SortNewest:      "desc",
+	SortBestSelling: "desc",
+}
+
+// Validate checks the search values and fills in the default sorting:
+// relevance when there is a query, newest otherwise.
+func (search *ProductSearch) Validate() error {
+	if search.SortBy == "" {
+		search.SortBy = SortNewest
+		if search.Query != "" {
+			search.SortBy = SortRelevance
+		}
+	}
+	defaultOrder, ok := productSortOrders[search.SortBy]
+	if !ok {
+		return errors.New("sort_by must be relevance, price, name, newest or best_selling")
+	}
+	if search.SortBy == SortRelevance && search.Query == "" {
+		return errors.New("sorting by relevance needs a search query")
+	}
+
+	if search.SortOrder == "" {
+		search.SortOrder = defaultOrder
+	}
+	if search.SortOrder != "asc" && search.SortOrder != "desc" {
+		return errors.New("sort_order must be asc or desc")
+	}
+
+	if search.MinPrice != nil && search.MaxPrice != nil && *search.MinPrice > *search.MaxPrice {
+		return errors.New("min_price can't be greater than max_price")
+	}
+	return nil
+}
+
+type Facet struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+type CategoryFacet struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// ProductFacets counts the matching products per size, color and category so
+// the storefront can show how many results each refinement would leave.
+type ProductFacets struct {
+	Size     []Facet         `json:"size"`
+	Color    []Facet         `json:"color"`
+	Category []CategoryFacet `json:"category"`
+}
@@ -0,0 +1,46 @@
+package domain_test
+
+import (
+	"project/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductSearchValidate(t *testing.T) {
+	t.Run("Sort by relevance when searching", func(t *testing.T) {
+		search := domain.ProductSearch{Query: "kaos"}
+		assert.NoError(t, search.Validate())
+		assert.Equal(t, domain.SortRelevance, search.SortBy)
+		assert.Equal(t, "desc", search.SortOrder)
+	})
+
+	t.Run("Sort by newest without a query", func(t *testing.T) {
+		search := domain.ProductSearch{}
+		assert.NoError(t, search.Validate())
+		assert.Equal(t, domain.SortNewest, search.SortBy)
+		assert.Equal(t, "desc", search.SortOrder)
+	})
+
+	t.Run("Sort by price ascending by default", func(t *testing.T) {
+		search := domain.ProductSearch{SortBy: domain.SortPrice}
+		assert.NoError(t, search.Validate())
+		assert.Equal(t, "asc", search.SortOrder)
+	})
+
+	t.Run("Failed to sort by relevance without a query", func(t *testing.T) {
+		search := domain.ProductSearch{SortBy: domain.SortRelevance}
+		assert.Error(t, search.Validate())
+	})
+
+	t.Run("Failed with an unknown sort", func(t *testing.T) {
+		search := domain.ProductSearch{SortBy: "rating"}
+		assert.Error(t, search.Validate())
+	})
+
+	t.Run("Failed with an inverted price range", func(t *testing.T) {
+		min, max := 200000.0, 100000.0
+		search := domain.ProductSearch{MinPrice: &min, MaxPrice: &max}
+		assert.Error(t, search.Validate())
+	})
+}
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ProductStatus is the lifecycle state of a product, only active products are
+// listed to customers.
+type ProductStatus string
+
+const (
+	ProductDraft     ProductStatus = "draft"
+	ProductActive    ProductStatus = "active"
+	ProductScheduled ProductStatus = "scheduled"
+	// ProductArchived products are soft deleted, their variants stay so the
+	// orders referring to them keep their history.
+	ProductArchived ProductStatus = "archived"
+)
+
+func (status ProductStatus) Valid() bool {
+	switch status {
+	case ProductDraft, ProductActive, ProductScheduled, ProductArchived:
+		return true
+	}
+	return false
+}
+
+// ProductStatusChange moves a product through its lifecycle, a scheduled
+// product goes live at its publish time.
+type ProductStatusChange struct {
+	Status    ProductStatus `json:"status" binding:"required,oneof=draft active scheduled archived"`
+	PublishAt *time.Time    `json:"publish_at" example:"2026-12-01T08:00:00+07:00"`
+}
+
+// Apply validates the change and sets the status and publish time of the
+// product. Publishing keeps the time the product went live.
+func (change ProductStatusChange) Apply(product *Product, now time.Time) error {
+	if !change.Status.Valid() {
+		return errors.New("invalid product status")
+	}
+	if change.Status == ProductScheduled {
+		if change.PublishAt == nil || !change.PublishAt.After(now) {
+			return errors.New("scheduled products need a publish_at in the future")
+		}
+	} else if change.PublishAt != nil {
+		return errors.New("publish_at can only be set for scheduled products")
+	}
+
+	switch change.Status {
+	case ProductScheduled:
+		product.PublishAt = change.PublishAt
+	case ProductActive:
+		if product.Status != ProductActive || product.PublishAt == nil {
+			product.PublishAt = &now
+		}
+	case ProductDraft:
+		product.PublishAt = nil
+	}
+	product.Status = change.Status
+	return nil
+}
@@ -0,0 +1,50 @@
+package domain_test
+
+import (
+	"project/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductStatusChange(t *testing.T) {
+	now := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+
+	t.Run("Scheduled needs a future publish time", func(t *testing.T) {
+		product := domain.Product{Status: domain.ProductDraft}
+		past := now.Add(-time.Hour)
+		assert.Error(t, domain.ProductStatusChange{Status: domain.ProductScheduled}.Apply(&product, now))
+		assert.Error(t, domain.ProductStatusChange{Status: domain.ProductScheduled, PublishAt: &past}.Apply(&product, now))
+
+		future := now.Add(time.Hour)
+		assert.NoError(t, domain.ProductStatusChange{Status: domain.ProductScheduled, PublishAt: &future}.Apply(&product, now))
+		assert.Equal(t, domain.ProductScheduled, product.Status)
+		assert.Equal(t, future, *product.PublishAt)
+	})
+
+	t.Run("Publish time only for scheduled", func(t *testing.T) {
+		product := domain.Product{}
+		future := now.Add(time.Hour)
+		assert.Error(t, domain.ProductStatusChange{Status: domain.ProductActive, PublishAt: &future}.Apply(&product, now))
+	})
+
+	t.Run("Activating keeps the first publish time", func(t *testing.T) {
+		product := domain.Product{Status: domain.ProductDraft}
+		assert.NoError(t, domain.ProductStatusChange{Status: domain.ProductActive}.Apply(&product, now))
+		assert.Equal(t, now, *product.PublishAt)
+
+		assert.NoError(t, domain.ProductStatusChange{Status: domain.ProductActive}.Apply(&product, now.Add(time.Hour)))
+		assert.Equal(t, now, *product.PublishAt)
+	})
+
+	t.Run("Draft clears the publish time", func(t *testing.T) {
+		product := domain.Product{Status: domain.ProductActive, PublishAt: &now}
+		assert.NoError(t, domain.ProductStatusChange{Status: domain.ProductDraft}.Apply(&product, now))
+		assert.Nil(t, product.PublishAt)
+	})
+
+	t.Run("Unknown status", func(t *testing.T) {
+		assert.Error(t, domain.ProductStatusChange{Status: "sold"}.Apply(&domain.Product{}, now))
+	})
+}
@@ -1,5 +1,15 @@
 package domain
 
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+)
+
 type status string
 
 const (
@@ -14,6 +24,15 @@ const (
 	Discount Type = "Direct Discount"
 )
 
+// PromotionDiscount tells how a promotion takes its discount off the items it
+// applies to.
+type PromotionDiscount string
+
+const (
+	PercentageDiscount PromotionDiscount = "percentage"
+	FixedDiscount      PromotionDiscount = "fixed"
+)
+
 type Promotion struct {
 	ID          uint `gorm:"primaryKey"`
 	Name        string
@@ -25,50 +44,288 @@ type Promotion struct {
 	IsPublish   bool
 	VoucherCode string
 	Limit       int
+	// DiscountType is whether Percentage or Amount is taken off, promotions
+	// without one are percentages.
+	DiscountType PromotionDiscount `gorm:"type:varchar(20);not null;default:percentage"`
+	// Percentage is the discount applied to the eligible subtotal, e.g. 20 for 20%.
+	Percentage float64 `gorm:"type:float;default:0"`
+	// Amount is the discount taken off the eligible subtotal at once.
+	Amount float64 `gorm:"type:float;default:0"`
+	// MaxDiscount caps the discount, 0 leaves it uncapped.
+	MaxDiscount float64 `gorm:"type:float;default:0"`
+	// MinOrderTotal is the order subtotal the promotion starts applying from.
+	MinOrderTotal float64 `gorm:"type:float;default:0"`
+	// PerCustomerLimit is how many orders a customer may use the promotion on,
+	// 0 leaves it to Limit alone.
+	PerCustomerLimit int `gorm:"default:0"`
+	// ProductIDs, CategoryIDs and VariantIDs restrict the discount to the items
+	// matching any of them, the whole order is discounted when all are empty.
+	ProductIDs  []int  `gorm:"type:jsonb;serializer:json"`
+	CategoryIDs []uint `gorm:"type:jsonb;serializer:json"`
+	VariantIDs  []uint `gorm:"type:jsonb;serializer:json"`
+	// SegmentID restricts the promotion to the customers of a segment.
+	SegmentID *uint
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+type PromotionSegment struct {
+	SegmentID *uint `json:"segment_id"`
+}
+
+// PromotionUsage records a promotion taken on an order, a customer's usages
+// count towards the promotion's PerCustomerLimit.
+type PromotionUsage struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	PromotionID uint      `gorm:"index:idx_promotion_usage_customer" json:"promotion_id"`
+	CustomerID  uint      `gorm:"index:idx_promotion_usage_customer" json:"customer_id"`
+	OrderID     uint      `gorm:"uniqueIndex" json:"order_id"`
+	Discount    float64   `gorm:"type:float" json:"discount"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// CartLine is an item being priced with what promotions may target it by.
+type CartLine struct {
+	VariantID   uint    `json:"variant_id"`
+	ProductID   int     `json:"product_id"`
+	CategoryIDs []uint  `json:"-"`
+	Quantity    uint    `json:"quantity"`
+	UnitPrice   float64 `json:"unit_price"`
+}
+
+func (line CartLine) Total() float64 {
+	return line.UnitPrice * float64(line.Quantity)
+}
+
+type Cart struct {
+	CustomerID uint
+	Lines      []CartLine
+}
+
+func (cart Cart) Subtotal() float64 {
+	var subtotal float64
+	for _, line := range cart.Lines {
+		subtotal += line.Total()
+	}
+	return subtotal
+}
+
+// PromotionQuote asks which discount an order of the items would get, with the
+// voucher code or else the best direct discount.
+type PromotionQuote struct {
+	CustomerID  uint           `json:"customer_id" binding:"required"`
+	VoucherCode string         `json:"voucher_code"`
+	Items       []NewOrderItem `json:"items" binding:"required,min=1,dive"`
+}
+
+func (quote PromotionQuote) Lines() []NewOrderItem {
+	return mergeLines(quote.Items)
+}
+
+// PromotionEvaluation is the discount a cart gets, PromotionID is empty when
+// no promotion applies.
+type PromotionEvaluation struct {
+	PromotionID *uint      `json:"promotion_id"`
+	Name        string     `json:"name,omitempty"`
+	VoucherCode string     `json:"voucher_code,omitempty"`
+	Lines       []CartLine `json:"lines"`
+	Subtotal    float64    `json:"subtotal"`
+	Discount    float64    `json:"discount"`
+	Total       float64    `json:"total"`
+}
+
+func NewPromotionEvaluation(cart Cart, promotion Promotion, discount float64) PromotionEvaluation {
+	evaluation := PromotionEvaluation{Lines: cart.Lines, Subtotal: cart.Subtotal(), Discount: discount}
+	if promotion.ID != 0 {
+		evaluation.PromotionID = &promotion.ID
+		evaluation.Name = promotion.Name
+		evaluation.VoucherCode = promotion.VoucherCode
+	}
+	evaluation.Total = evaluation.Subtotal - evaluation.Discount
+	return evaluation
+}
+
+// Validate checks the discount rules of a new promotion.
+func (promotion Promotion) Validate() error {
+	switch promotion.DiscountType {
+	case "", PercentageDiscount:
+		if promotion.Percentage <= 0 || promotion.Percentage > 100 {
+			return errors.New("percentage must be between 0 and 100")
+		}
+	case FixedDiscount:
+		if promotion.Amount <= 0 {
+			return errors.New("amount must be greater than zero")
+		}
+	default:
+		return errors.New("discount type must be percentage or fixed")
+	}
+	if promotion.MaxDiscount < 0 || promotion.MinOrderTotal < 0 || promotion.PerCustomerLimit < 0 {
+		return errors.New("max discount, min order total and per customer limit can't be negative")
+	}
+	return nil
+}
+
+// ValidVoucher reports why the promotion can't be redeemed as a voucher code at the given time.
+func (promotion Promotion) ValidVoucher(now time.Time) error {
+	if promotion.Type != Voucher || promotion.Status != Active {
+		return errors.New("voucher is not active")
+	}
+	return promotion.available("voucher", now)
+}
+
+// ValidDiscount reports why the promotion can't be applied as a direct
+// discount at the given time.
+func (promotion Promotion) ValidDiscount(now time.Time) error {
+	if promotion.Type != Discount || promotion.Status != Active {
+		return errors.New("discount is not active")
+	}
+	return promotion.available("discount", now)
+}
+
+func (promotion Promotion) available(kind string, now time.Time) error {
+	today := now.Format("2006-01-02")
+	if today < dateOnly(promotion.StartDate) || today > dateOnly(promotion.EndDate) {
+		return fmt.Errorf("%s is not valid today", kind)
+	}
+	if promotion.Limit <= 0 {
+		return fmt.Errorf("%s has been fully used", kind)
+	}
+	return nil
+}
+
+// Targets reports whether the promotion applies to a cart line.
+func (promotion Promotion) Targets(line CartLine) bool {
+	if len(promotion.ProductIDs) == 0 && len(promotion.CategoryIDs) == 0 && len(promotion.VariantIDs) == 0 {
+		return true
+	}
+	if slices.Contains(promotion.VariantIDs, line.VariantID) || slices.Contains(promotion.ProductIDs, line.ProductID) {
+		return true
+	}
+	for _, categoryId := range line.CategoryIDs {
+		if slices.Contains(promotion.CategoryIDs, categoryId) {
+			return true
+		}
+	}
+	return false
+}
+
+// Evaluate computes the discount a cart gets from the promotion, used is how
+// many times the customer has already used it.
+func (promotion Promotion) Evaluate(cart Cart, used int, now time.Time) (float64, error) {
+	valid := promotion.ValidDiscount
+	if promotion.Type == Voucher {
+		valid = promotion.ValidVoucher
+	}
+	if err := valid(now); err != nil {
+		return 0, err
+	}
+	if promotion.PerCustomerLimit > 0 && used >= promotion.PerCustomerLimit {
+		return 0, errors.New("promotion has already been used the maximum number of times")
+	}
+	if subtotal := cart.Subtotal(); subtotal < promotion.MinOrderTotal {
+		return 0, fmt.Errorf("order subtotal must be at least %.0f", promotion.MinOrderTotal)
+	}
+
+	var eligible float64
+	for _, line := range cart.Lines {
+		if promotion.Targets(line) {
+			eligible += line.Total()
+		}
+	}
+	if eligible == 0 {
+		return 0, errors.New("promotion doesn't apply to any of the items")
+	}
+	return promotion.DiscountFor(eligible), nil
+}
+
+// dateOnly trims the time part postgres adds when a date column is scanned into a string.
+func dateOnly(date string) string {
+	if len(date) > 10 {
+		return date[:10]
+	}
+	return date
+}
+
+// DiscountFor computes the discount on the subtotal of the eligible items,
+// never more than the subtotal or MaxDiscount.
+func (promotion Promotion) DiscountFor(subtotal float64) float64 {
+	discount := math.Round(subtotal*promotion.Percentage) / 100
+	if promotion.DiscountType == FixedDiscount {
+		discount = math.Min(promotion.Amount, subtotal)
+	}
+	if promotion.MaxDiscount > 0 {
+		discount = math.Min(discount, promotion.MaxDiscount)
+	}
+	return discount
+}
+
+// BestPromotion picks the direct discount giving the cart the largest
+// discount, used counts the customer's usages by promotion. Vouchers are left
+// out as they need their code, a zero promotion is returned when none applies.
+func BestPromotion(promotions []Promotion, cart Cart, used map[uint]int, now time.Time) (Promotion, float64) {
+	var best Promotion
+	var bestDiscount float64
+	for _, promotion := range promotions {
+		if promotion.Type != Discount {
+			continue
+		}
+		discount, err := promotion.Evaluate(cart, used[promotion.ID], now)
+		if err == nil && discount > bestDiscount {
+			best, bestDiscount = promotion, discount
+		}
+	}
+	return best, bestDiscount
 }
 
 func SeedPromotions() []Promotion {
 	promotions := []Promotion{
 		{
-			Name:        "Promo Akhir Tahun",
-			Description: "Potongan 20'%' dengan pembelian di atas 100rb",
-			StartDate:   "2024-12-15",
-			EndDate:     "2024-12-22",
-			Type:        "Direct Discount",
-			Status:      "Active",
-			IsPublish:   false,
-			Limit:       20,
+			Name:          "Promo Akhir Tahun",
+			Description:   "Potongan 20'%' dengan pembelian di atas 100rb",
+			Percentage:    20,
+			MinOrderTotal: 100000,
+			StartDate:     "2024-12-15",
+			EndDate:       "2024-12-22",
+			Type:          "Direct Discount",
+			Status:        "Active",
+			IsPublish:     false,
+			Limit:         20,
 		},
 		{
-			Name:        "Cuci Gudang",
-			Description: "Potongan 30'%' dengan pembelian di atas 100rb",
-			StartDate:   "2024-12-15",
-			EndDate:     "2024-12-22",
-			Type:        "Voucher Code",
-			Status:      "Active",
-			IsPublish:   true,
-			VoucherCode: "CGDG",
-			Limit:       20,
+			Name:          "Cuci Gudang",
+			Description:   "Potongan 30'%' dengan pembelian di atas 100rb",
+			Percentage:    30,
+			MinOrderTotal: 100000,
+			StartDate:     "2024-12-15",
+			EndDate:       "2024-12-22",
+			Type:          "Voucher Code",
+			Status:        "Active",
+			IsPublish:     true,
+			VoucherCode:   "CGDG",
+			Limit:         20,
 		},
 		{
-			Name:        "Spesial Kemerdekaan",
-			Description: "Potongan 10'%' dengan pembelian di atas 100rb",
-			StartDate:   "2024-12-15",
-			EndDate:     "2024-12-22",
-			Type:        "Direct Discount",
-			Status:      "Inactive",
-			IsPublish:   false,
-			Limit:       20,
+			Name:          "Spesial Kemerdekaan",
+			Description:   "Potongan 10'%' dengan pembelian di atas 100rb",
+			Percentage:    10,
+			MinOrderTotal: 100000,
+			StartDate:     "2024-12-15",
+			EndDate:       "2024-12-22",
+			Type:          "Direct Discount",
+			Status:        "Inactive",
+			IsPublish:     false,
+			Limit:         20,
 		},
 		{
-			Name:        "Hari Kartini",
-			Description: "Potongan 15'%' dengan pembelian di atas 100rb",
-			StartDate:   "2024-12-15",
-			EndDate:     "2024-12-22",
-			Type:        "Direct Discount",
-			Status:      "Inactive",
-			IsPublish:   false,
-			Limit:       20,
+			Name:          "Hari Kartini",
+			Description:   "Potongan 15'%' dengan pembelian di atas 100rb",
+			Percentage:    15,
+			MinOrderTotal: 100000,
+			StartDate:     "2024-12-15",
+			EndDate:       "2024-12-22",
+			Type:          "Direct Discount",
+			Status:        "Inactive",
+			IsPublish:     false,
+			Limit:         20,
 		},
 	}
 
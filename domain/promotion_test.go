@@ -0,0 +1,107 @@
+package domain_test
+
+import (
+	"project/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPromotionEvaluate(t *testing.T) {
+	now := time.Date(2024, 12, 20, 10, 0, 0, 0, time.UTC)
+	cart := domain.Cart{CustomerID: 1, Lines: []domain.CartLine{
+		{VariantID: 1, ProductID: 1, CategoryIDs: []uint{3}, Quantity: 2, UnitPrice: 100000},
+		{VariantID: 2, ProductID: 2, CategoryIDs: []uint{4}, Quantity: 1, UnitPrice: 50000},
+	}}
+
+	t.Run("Successfully discount only the targeted items", func(t *testing.T) {
+		promotion := voucher()
+		promotion.CategoryIDs = []uint{4}
+
+		discount, err := promotion.Evaluate(cart, 0, now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, float64(15000), discount)
+	})
+
+	t.Run("Successfully cap a percentage discount", func(t *testing.T) {
+		promotion := voucher()
+		promotion.MaxDiscount = 50000
+
+		discount, err := promotion.Evaluate(cart, 0, now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, float64(50000), discount)
+	})
+
+	t.Run("Successfully take a fixed amount off no more than the eligible items", func(t *testing.T) {
+		promotion := voucher()
+		promotion.DiscountType = domain.FixedDiscount
+		promotion.Amount = 80000
+		promotion.VariantIDs = []uint{2}
+
+		discount, err := promotion.Evaluate(cart, 0, now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, float64(50000), discount)
+	})
+
+	t.Run("Failed below the minimum order total", func(t *testing.T) {
+		promotion := voucher()
+		promotion.MinOrderTotal = 300000
+
+		_, err := promotion.Evaluate(cart, 0, now)
+
+		assert.EqualError(t, err, "order subtotal must be at least 300000")
+	})
+
+	t.Run("Failed when no item is targeted", func(t *testing.T) {
+		promotion := voucher()
+		promotion.ProductIDs = []int{9}
+
+		_, err := promotion.Evaluate(cart, 0, now)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Failed once the customer used it up", func(t *testing.T) {
+		promotion := voucher()
+		promotion.PerCustomerLimit = 1
+
+		_, err := promotion.Evaluate(cart, 1, now)
+
+		assert.EqualError(t, err, "promotion has already been used the maximum number of times")
+	})
+}
+
+func TestBestPromotion(t *testing.T) {
+	now := time.Date(2024, 12, 20, 10, 0, 0, 0, time.UTC)
+	cart := domain.Cart{Lines: []domain.CartLine{{VariantID: 1, ProductID: 1, Quantity: 1, UnitPrice: 200000}}}
+	discount := func(id uint, percentage float64) domain.Promotion {
+		return domain.Promotion{ID: id, Type: domain.Discount, Status: domain.Active, StartDate: "2024-12-15", EndDate: "2024-12-22", Limit: 5, Percentage: percentage}
+	}
+	usedUp := discount(3, 50)
+	usedUp.PerCustomerLimit = 1
+
+	t.Run("Successfully pick the largest discount", func(t *testing.T) {
+		best, amount := domain.BestPromotion([]domain.Promotion{discount(1, 10), discount(2, 20), usedUp}, cart, map[uint]int{3: 1}, now)
+
+		assert.Equal(t, uint(2), best.ID)
+		assert.Equal(t, float64(40000), amount)
+	})
+
+	t.Run("Successfully pick nothing when no promotion applies", func(t *testing.T) {
+		best, amount := domain.BestPromotion([]domain.Promotion{voucher()}, cart, nil, now)
+
+		assert.Zero(t, best.ID)
+		assert.Zero(t, amount)
+	})
+}
+
+func TestPromotionValidate(t *testing.T) {
+	assert.NoError(t, voucher().Validate())
+	assert.Error(t, domain.Promotion{Percentage: 120}.Validate())
+	assert.Error(t, domain.Promotion{DiscountType: domain.FixedDiscount}.Validate())
+	assert.Error(t, domain.Promotion{DiscountType: "bogo", Amount: 10}.Validate())
+}
@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+type PurchaseOrderStatus string
+
+const (
+	PurchaseDraft             PurchaseOrderStatus = "draft"
+	PurchaseSent              PurchaseOrderStatus = "sent"
+	PurchasePartiallyReceived PurchaseOrderStatus = "partially_received"
+	PurchaseReceived          PurchaseOrderStatus = "received"
+	PurchaseClosed            PurchaseOrderStatus = "closed"
+)
+
+type PurchaseOrder struct {
+	ID         uint                `gorm:"primaryKey;autoIncrement" json:"id"`
+	SupplierID uint                `gorm:"not null" json:"supplier_id" binding:"required"`
+	Supplier   Supplier            `json:"supplier" binding:"-"`
+	Status     PurchaseOrderStatus `gorm:"type:varchar(20);default:draft" json:"status"`
+	Note       string              `json:"note"`
+	Items      []PurchaseOrderItem `gorm:"foreignKey:PurchaseOrderID" json:"items" binding:"required,min=1,dive"`
+	CreatedAt  time.Time           `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time           `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+type PurchaseOrderItem struct {
+	ID               uint           `gorm:"primaryKey;autoIncrement" json:"id"`
+	PurchaseOrderID  uint           `gorm:"not null" json:"-"`
+	VariantID        int            `gorm:"not null" json:"variant_id" binding:"required"`
+	Variant          ProductVariant `json:"-" binding:"-"`
+	Quantity         uint           `gorm:"not null" json:"quantity" binding:"required,min=1"`
+	ReceivedQuantity uint           `gorm:"default:0" json:"received_quantity"`
+	UnitCost         float64        `gorm:"type:float;not null" json:"unit_cost" binding:"min=0"`
+}
+
+type GoodsReceipt struct {
+	Items []GoodsReceiptItem `json:"items" binding:"required,min=1,dive"`
+}
+
+type GoodsReceiptItem struct {
+	ItemID   uint `json:"item_id" binding:"required"`
+	Quantity uint `json:"quantity" binding:"required,min=1"`
+}
+
+type OpenPurchaseOrder struct {
+	ID               uint                `json:"id"`
+	SupplierName     string              `json:"supplier_name"`
+	Status           PurchaseOrderStatus `json:"status"`
+	OrderedQuantity  int                 `json:"ordered_quantity"`
+	ReceivedQuantity int                 `json:"received_quantity"`
+	OutstandingValue float64             `json:"outstanding_value"`
+	CreatedAt        time.Time           `json:"created_at"`
+}
+
+func (item PurchaseOrderItem) Outstanding() uint {
+	return item.Quantity - item.ReceivedQuantity
+}
+
+func (po *PurchaseOrder) Send() error {
+	if po.Status != PurchaseDraft {
+		return errors.New("only draft purchase orders can be sent")
+	}
+	po.Status = PurchaseSent
+	return nil
+}
+
+func (po *PurchaseOrder) Close() error {
+	if po.Status == PurchaseDraft || po.Status == PurchaseClosed {
+		return fmt.Errorf("purchase order with status %s can't be closed", po.Status)
+	}
+	po.Status = PurchaseClosed
+	return nil
+}
+
+// Receive books the delivered quantities against the matching items and moves
+// the purchase order to partially received or received.
+func (po *PurchaseOrder) Receive(receipt GoodsReceipt) error {
+	if po.Status != PurchaseSent && po.Status != PurchasePartiallyReceived {
+		return fmt.Errorf("purchase order with status %s can't be received", po.Status)
+	}
+
+	for _, line := range receipt.Items {
+		item := po.Item(line.ItemID)
+		if item == nil {
+			return fmt.Errorf("item %d is not part of this purchase order", line.ItemID)
+		}
+		if line.Quantity > item.Outstanding() {
+			return fmt.Errorf("item %d only has %d outstanding", line.ItemID, item.Outstanding())
+		}
+		item.ReceivedQuantity += line.Quantity
+	}
+
+	po.Status = PurchaseReceived
+	for _, item := range po.Items {
+		if item.Outstanding() > 0 {
+			po.Status = PurchasePartiallyReceived
+			break
+		}
+	}
+	return nil
+}
+
+func (po *PurchaseOrder) Item(id uint) *PurchaseOrderItem {
+	for i := range po.Items {
+		if po.Items[i].ID == id {
+			return &po.Items[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package domain_test
+
+import (
+	"project/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func purchaseOrder(status domain.PurchaseOrderStatus) domain.PurchaseOrder {
+	return domain.PurchaseOrder{
+		ID:     1,
+		Status: status,
+		Items: []domain.PurchaseOrderItem{
+			{ID: 1, VariantID: 10, Quantity: 5},
+			{ID: 2, VariantID: 11, Quantity: 3},
+		},
+	}
+}
+
+func TestPurchaseOrderReceive(t *testing.T) {
+	t.Run("Partial delivery", func(t *testing.T) {
+		po := purchaseOrder(domain.PurchaseSent)
+
+		err := po.Receive(domain.GoodsReceipt{Items: []domain.GoodsReceiptItem{{ItemID: 1, Quantity: 5}}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.PurchasePartiallyReceived, po.Status)
+		assert.Equal(t, uint(5), po.Items[0].ReceivedQuantity)
+	})
+
+	t.Run("Full delivery after partial delivery", func(t *testing.T) {
+		po := purchaseOrder(domain.PurchasePartiallyReceived)
+		po.Items[0].ReceivedQuantity = 5
+
+		err := po.Receive(domain.GoodsReceipt{Items: []domain.GoodsReceiptItem{{ItemID: 2, Quantity: 3}}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.PurchaseReceived, po.Status)
+	})
+
+	t.Run("Failed to receive more than outstanding", func(t *testing.T) {
+		po := purchaseOrder(domain.PurchaseSent)
+
+		err := po.Receive(domain.GoodsReceipt{Items: []domain.GoodsReceiptItem{{ItemID: 2, Quantity: 4}}})
+
+		assert.EqualError(t, err, "item 2 only has 3 outstanding")
+		assert.Equal(t, domain.PurchaseSent, po.Status)
+	})
+
+	t.Run("Failed to receive unknown item", func(t *testing.T) {
+		po := purchaseOrder(domain.PurchaseSent)
+
+		err := po.Receive(domain.GoodsReceipt{Items: []domain.GoodsReceiptItem{{ItemID: 9, Quantity: 1}}})
+
+		assert.EqualError(t, err, "item 9 is not part of this purchase order")
+	})
+
+	t.Run("Failed to receive draft purchase order", func(t *testing.T) {
+		po := purchaseOrder(domain.PurchaseDraft)
+
+		err := po.Receive(domain.GoodsReceipt{Items: []domain.GoodsReceiptItem{{ItemID: 1, Quantity: 1}}})
+
+		assert.Error(t, err)
+	})
+}
+
+func TestPurchaseOrderTransitions(t *testing.T) {
+	po := purchaseOrder(domain.PurchaseDraft)
+
+	assert.Error(t, po.Close())
+	assert.NoError(t, po.Send())
+	assert.Equal(t, domain.PurchaseSent, po.Status)
+	assert.Error(t, po.Send())
+	assert.NoError(t, po.Close())
+	assert.Equal(t, domain.PurchaseClosed, po.Status)
+}
@@ -0,0 +1,172 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+type RefundStatus string
+
+const (
+	RefundRequested RefundStatus = "requested"
+	RefundApproved  RefundStatus = "approved"
+	RefundRejected  RefundStatus = "rejected"
+	RefundPaid      RefundStatus = "paid"
+)
+
+type Refund struct {
+	ID        uint         `gorm:"primaryKey;autoIncrement" json:"id"`
+	OrderID   uint         `gorm:"not null;index" json:"order_id"`
+	Amount    float64      `gorm:"type:float;not null" json:"amount"`
+	Reason    string       `gorm:"not null" json:"reason"`
+	Restock   bool         `gorm:"default:false" json:"restock"`
+	Status    RefundStatus `gorm:"type:varchar(20);default:requested" json:"status"`
+	Note      string       `json:"note,omitempty"`
+	Items     []RefundItem `gorm:"foreignKey:RefundID" json:"items"`
+	PaidAt    *time.Time   `json:"paid_at"`
+	CreatedAt time.Time    `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time    `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+type RefundItem struct {
+	ID          uint    `gorm:"primaryKey;autoIncrement" json:"id"`
+	RefundID    uint    `gorm:"not null" json:"-"`
+	OrderItemID uint    `gorm:"not null" json:"order_item_id"`
+	VariantID   uint    `gorm:"not null" json:"variant_id"`
+	Quantity    uint    `gorm:"not null" json:"quantity"`
+	Amount      float64 `gorm:"type:float" json:"amount"`
+}
+
+// NewRefund requests a refund of the whole order when Items is empty, in which
+// case Amount is required, or of the given order items otherwise.
+type NewRefund struct {
+	Reason  string          `json:"reason" binding:"required"`
+	Amount  float64         `json:"amount" binding:"min=0"`
+	Restock bool            `json:"restock"`
+	Items   []NewRefundItem `json:"items" binding:"dive"`
+}
+
+type NewRefundItem struct {
+	OrderItemID uint `json:"order_item_id" binding:"required"`
+	Quantity    uint `json:"quantity" binding:"required,min=1"`
+}
+
+type RefundDecision struct {
+	Note string `json:"note"`
+}
+
+// NewRefund builds the refund for an order. refunded holds the quantity per
+// order item that earlier refunds already returned, refundable is what the
+// customer paid minus earlier refunds. Returned items are valued at what was
+// paid for them after the order discount.
+func (order *Order) NewRefund(request NewRefund, refunded map[uint]uint, refundable float64) (Refund, error) {
+	refund := Refund{
+		OrderID: order.ID,
+		Amount:  request.Amount,
+		Reason:  request.Reason,
+		Restock: request.Restock,
+		Status:  RefundRequested,
+	}
+
+	if len(request.Items) == 0 {
+		if request.Restock {
+			return Refund{}, errors.New("restocking needs the returned items")
+		}
+		if request.Amount <= 0 {
+			return Refund{}, errors.New("amount is required when no items are given")
+		}
+	}
+
+	itemsAmount := 0.0
+	requested := map[uint]uint{}
+	for _, line := range request.Items {
+		item := order.Item(line.OrderItemID)
+		if item == nil {
+			return Refund{}, fmt.Errorf("item %d is not part of this order", line.OrderItemID)
+		}
+		left := item.Quantity - refunded[item.ID] - requested[item.ID]
+		if line.Quantity > left {
+			return Refund{}, fmt.Errorf("item %d only has %d left to refund", item.ID, left)
+		}
+		requested[item.ID] += line.Quantity
+
+		amount := order.paidPrice(*item, line.Quantity)
+		itemsAmount += amount
+		refund.Items = append(refund.Items, RefundItem{
+			OrderItemID: item.ID,
+			VariantID:   item.VariantID,
+			Quantity:    line.Quantity,
+			Amount:      amount,
+		})
+	}
+
+	if len(request.Items) > 0 {
+		if request.Amount == 0 {
+			refund.Amount = itemsAmount
+		}
+		if refund.Amount > itemsAmount {
+			return Refund{}, errors.New("amount exceeds the value of the returned items")
+		}
+	}
+
+	if refund.Amount > refundable {
+		return Refund{}, fmt.Errorf("only %.2f of this order can be refunded", refundable)
+	}
+	return refund, nil
+}
+
+// paidPrice is what the customer paid for quantity of an item, the order
+// discount is spread over the items in proportion to their value.
+func (order *Order) paidPrice(item OrderItem, quantity uint) float64 {
+	amount := float64(quantity) * item.UnitPrice
+	if order.Discount <= 0 {
+		return amount
+	}
+
+	subtotal := 0.0
+	for _, item := range order.Items {
+		subtotal += float64(item.Quantity) * item.UnitPrice
+	}
+	if subtotal <= order.Discount {
+		return 0
+	}
+	return math.Round(amount*(subtotal-order.Discount)/subtotal*100) / 100
+}
+
+func (order *Order) Item(id uint) *OrderItem {
+	for i := range order.Items {
+		if order.Items[i].ID == id {
+			return &order.Items[i]
+		}
+	}
+	return nil
+}
+
+func (refund *Refund) Approve(decision RefundDecision) error {
+	if refund.Status != RefundRequested {
+		return fmt.Errorf("refund with status %s can't be approved", refund.Status)
+	}
+	refund.Status = RefundApproved
+	refund.Note = decision.Note
+	return nil
+}
+
+func (refund *Refund) Reject(decision RefundDecision) error {
+	if refund.Status != RefundRequested {
+		return fmt.Errorf("refund with status %s can't be rejected", refund.Status)
+	}
+	refund.Status = RefundRejected
+	refund.Note = decision.Note
+	return nil
+}
+
+func (refund *Refund) Pay(at time.Time) error {
+	if refund.Status != RefundApproved {
+		return fmt.Errorf("refund with status %s can't be paid", refund.Status)
+	}
+	refund.Status = RefundPaid
+	refund.PaidAt = &at
+	return nil
+}
@@ -0,0 +1,84 @@
+package domain_test
+
+import (
+	"project/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderNewRefund(t *testing.T) {
+	order := domain.Order{ID: 1, Status: domain.Completed, Items: []domain.OrderItem{
+		{ID: 10, VariantID: 3, Quantity: 2, UnitPrice: 100000},
+		{ID: 11, VariantID: 4, Quantity: 1, UnitPrice: 50000},
+	}}
+
+	t.Run("Successfully refund returned items", func(t *testing.T) {
+		refund, err := order.NewRefund(domain.NewRefund{
+			Reason:  "damaged",
+			Restock: true,
+			Items:   []domain.NewRefundItem{{OrderItemID: 10, Quantity: 1}},
+		}, map[uint]uint{}, 250000)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.RefundRequested, refund.Status)
+		assert.Equal(t, float64(100000), refund.Amount)
+		assert.Equal(t, uint(3), refund.Items[0].VariantID)
+	})
+
+	t.Run("Successfully refund part of the order", func(t *testing.T) {
+		refund, err := order.NewRefund(domain.NewRefund{Reason: "late delivery", Amount: 25000}, map[uint]uint{}, 250000)
+
+		assert.NoError(t, err)
+		assert.Equal(t, float64(25000), refund.Amount)
+		assert.Empty(t, refund.Items)
+	})
+
+	t.Run("Successfully refund items less their share of the discount", func(t *testing.T) {
+		discounted := order
+		discounted.Discount = 25000
+
+		refund, err := discounted.NewRefund(domain.NewRefund{
+			Reason: "damaged",
+			Items:  []domain.NewRefundItem{{OrderItemID: 10, Quantity: 1}, {OrderItemID: 11, Quantity: 1}},
+		}, map[uint]uint{}, 225000)
+
+		assert.NoError(t, err)
+		assert.Equal(t, float64(90000), refund.Items[0].Amount)
+		assert.Equal(t, float64(45000), refund.Items[1].Amount)
+		assert.Equal(t, float64(135000), refund.Amount)
+	})
+
+	t.Run("Failed to refund more items than left", func(t *testing.T) {
+		_, err := order.NewRefund(domain.NewRefund{
+			Reason: "damaged",
+			Items:  []domain.NewRefundItem{{OrderItemID: 10, Quantity: 1}, {OrderItemID: 10, Quantity: 1}},
+		}, map[uint]uint{10: 1}, 250000)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Failed to refund more than was paid", func(t *testing.T) {
+		_, err := order.NewRefund(domain.NewRefund{Reason: "damaged", Amount: 300000}, map[uint]uint{}, 250000)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Failed to refund an amount without items or amount", func(t *testing.T) {
+		_, err := order.NewRefund(domain.NewRefund{Reason: "damaged"}, map[uint]uint{}, 250000)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestRefundWorkflow(t *testing.T) {
+	refund := domain.Refund{Status: domain.RefundRequested}
+
+	assert.Error(t, refund.Pay(time.Now()))
+	assert.NoError(t, refund.Approve(domain.RefundDecision{Note: "ok"}))
+	assert.Error(t, refund.Reject(domain.RefundDecision{}))
+	assert.NoError(t, refund.Pay(time.Now()))
+	assert.Equal(t, domain.RefundPaid, refund.Status)
+	assert.NotNil(t, refund.PaidAt)
+}
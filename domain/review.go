@@ -1,18 +1,124 @@
 package domain
 
-import "time"
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// ReviewStatus is the moderation state of a review, only approved reviews are
+// shown to customers and count towards the product rating.
+type ReviewStatus string
+
+const (
+	ReviewPending  ReviewStatus = "pending"
+	ReviewApproved ReviewStatus = "approved"
+	ReviewHidden   ReviewStatus = "hidden"
+)
+
+func (status ReviewStatus) Valid() bool {
+	return status == ReviewPending || status == ReviewApproved || status == ReviewHidden
+}
 
 type Review struct {
-	ID          uint `gorm:"primaryKey"`
-	OrderItemID uint
-	OrderItem   OrderItem
-	Rating      float32
-	Comment     string
-	CreatedAt   time.Time
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	OrderItemID uint      `gorm:"uniqueIndex" json:"order_item_id"`
+	OrderItem   OrderItem `json:"-"`
+	// ProductID is the product of the reviewed variant, it's only read.
+	ProductID int          `gorm:"->;-:migration" json:"product_id"`
+	Rating    float32      `json:"rating"`
+	Comment   string       `json:"comment"`
+	Status    ReviewStatus `gorm:"type:varchar(20);not null;default:pending;index" json:"status"`
+	// Sentiment is the analyzer's score of the comment from -1 to 1, it's
+	// empty until the comment is analyzed.
+	Sentiment *float64   `gorm:"type:float" json:"sentiment"`
+	Keywords  []string   `gorm:"type:jsonb;serializer:json" json:"keywords,omitempty"`
+	Reply     string     `json:"reply,omitempty"`
+	RepliedAt *time.Time `json:"replied_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// NewReview is a customer's review of an item they ordered, it waits for
+// moderation before it's shown.
+type NewReview struct {
+	OrderItemID uint   `json:"order_item_id" binding:"required"`
+	Rating      int    `json:"rating" binding:"required,min=1,max=5"`
+	Comment     string `json:"comment" binding:"max=2000"`
+}
+
+func (review NewReview) Review() Review {
+	return Review{
+		OrderItemID: review.OrderItemID,
+		Rating:      float32(review.Rating),
+		Comment:     review.Comment,
+		Status:      ReviewPending,
+	}
+}
+
+type ReviewModeration struct {
+	Status ReviewStatus `json:"status" binding:"required,oneof=pending approved hidden"`
+}
+
+type ReviewReply struct {
+	Reply string `json:"reply" binding:"required,max=2000"`
+}
+
+type ReviewFilter struct {
+	ProductID *int         `form:"product_id" json:"product_id,omitempty"`
+	Rating    *int         `form:"rating" json:"rating,omitempty" binding:"omitempty,min=1,max=5"`
+	Status    ReviewStatus `form:"status" json:"status,omitempty"`
+	DateFrom  string       `form:"date_from" json:"date_from,omitempty"`
+	DateTo    string       `form:"date_to" json:"date_to,omitempty"`
+}
+
+// Validate checks the filter values, customers only see approved reviews so
+// that is the default status.
+func (filter *ReviewFilter) Validate() error {
+	if filter.Status == "" {
+		filter.Status = ReviewApproved
+	}
+	if !filter.Status.Valid() {
+		return errors.New("status must be pending, approved or hidden")
+	}
+	for _, date := range []string{filter.DateFrom, filter.DateTo} {
+		if _, err := time.Parse("2006-01-02", date); date != "" && err != nil {
+			return errors.New("dates must use the yyyy-mm-dd format")
+		}
+	}
+	return nil
+}
+
+// ProductRating sums up the approved reviews of a product, Distribution counts
+// the reviews for every star from 1 to 5.
+type ProductRating struct {
+	Average      float64     `json:"average"`
+	Count        int         `json:"count"`
+	Distribution map[int]int `json:"distribution"`
+}
+
+// RatingCount is the number of reviews giving a product a number of stars.
+type RatingCount struct {
+	Rating int
+	Count  int
+}
+
+func NewProductRating(counts []RatingCount) ProductRating {
+	rating := ProductRating{Distribution: map[int]int{1: 0, 2: 0, 3: 0, 4: 0, 5: 0}}
+	total := 0
+	for _, count := range counts {
+		rating.Distribution[count.Rating] += count.Count
+		rating.Count += count.Count
+		total += count.Rating * count.Count
+	}
+	if rating.Count > 0 {
+		rating.Average = math.Round(float64(total)/float64(rating.Count)*100) / 100
+	}
+	return rating
 }
 
 func ReviewSeed() []Review {
-	return []Review{
+	reviews := []Review{
 		{OrderItemID: 386, Rating: 1, Comment: "Nullam in magna et orci tincidunt varius vel id odio. Neque porro quisquam est, qui dolorem ipsum quia dolor sit amet."},
 		{OrderItemID: 463, Rating: 5, Comment: "Vestibulum ante ipsum primis in faucibus orci luctus et ultrices posuere cubilia. Neque porro quisquam est, qui dolorem ipsum quia dolor sit amet."},
 		{OrderItemID: 282, Rating: 1, Comment: "Lorem ipsum dolor sit amet, consectetur adipiscing elit. Ut enim ad minima veniam, quis nostrum exercitationem ullam corporis suscipit."},
@@ -389,4 +495,9 @@ func ReviewSeed() []Review {
 		{OrderItemID: 101, Rating: 1, Comment: "Ut enim ad minima veniam, quis nostrum exercitationem ullam corporis suscipit. Vestibulum ante ipsum primis in faucibus orci luctus et ultrices posuere cubilia."},
 		{OrderItemID: 397, Rating: 1, Comment: "Nullam in magna et orci tincidunt varius vel id odio. Etiam eu arcu convallis, volutpat arcu ut, ullamcorper augue."},
 	}
+
+	for i := range reviews {
+		reviews[i].Status = ReviewApproved
+	}
+	return reviews
 }
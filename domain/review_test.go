@@ -0,0 +1,61 @@
+package domain_test
+
+import (
+	"project/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewProductRating(t *testing.T) {
+	t.Run("Successfully sum up the ratings", func(t *testing.T) {
+		rating := domain.NewProductRating([]domain.RatingCount{{Rating: 5, Count: 2}, {Rating: 4, Count: 1}})
+
+		assert.Equal(t, 3, rating.Count)
+		assert.Equal(t, 4.67, rating.Average)
+		assert.Equal(t, map[int]int{1: 0, 2: 0, 3: 0, 4: 1, 5: 2}, rating.Distribution)
+	})
+
+	t.Run("Successfully sum up a product without reviews", func(t *testing.T) {
+		rating := domain.NewProductRating(nil)
+
+		assert.Equal(t, 0, rating.Count)
+		assert.Equal(t, 0.0, rating.Average)
+		assert.Len(t, rating.Distribution, 5)
+	})
+}
+
+func TestReviewFilterValidate(t *testing.T) {
+	t.Run("Successfully default to approved reviews", func(t *testing.T) {
+		filter := domain.ReviewFilter{DateFrom: "2024-01-01"}
+
+		assert.NoError(t, filter.Validate())
+		assert.Equal(t, domain.ReviewApproved, filter.Status)
+	})
+
+	t.Run("Failed with an unknown status", func(t *testing.T) {
+		filter := domain.ReviewFilter{Status: "deleted"}
+
+		assert.Error(t, filter.Validate())
+	})
+
+	t.Run("Failed with a malformed date", func(t *testing.T) {
+		filter := domain.ReviewFilter{DateTo: "01/02/2024"}
+
+		assert.Error(t, filter.Validate())
+	})
+}
+
+func TestNewReview(t *testing.T) {
+	review := domain.NewReview{OrderItemID: 7, Rating: 4, Comment: "fits well"}.Review()
+
+	assert.Equal(t, uint(7), review.OrderItemID)
+	assert.Equal(t, float32(4), review.Rating)
+	assert.Equal(t, domain.ReviewPending, review.Status)
+}
+
+func TestReviewSeed(t *testing.T) {
+	for _, review := range domain.ReviewSeed() {
+		assert.Equal(t, domain.ReviewApproved, review.Status)
+	}
+}
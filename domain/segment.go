@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// CustomerRFM holds the recency, frequency and monetary value of a customer's
+// completed orders with a 1 to 5 score for each, 5 being the best fifth of
+// all customers.
+type CustomerRFM struct {
+	CustomerID  uint       `gorm:"primaryKey;autoIncrement:false" json:"customer_id"`
+	RecencyDays *int       `json:"recency_days"`
+	Frequency   int        `json:"frequency"`
+	Monetary    float64    `gorm:"type:float" json:"monetary"`
+	RScore      int        `json:"r_score"`
+	FScore      int        `json:"f_score"`
+	MScore      int        `json:"m_score"`
+	Score       string     `gorm:"type:varchar(3)" json:"score"`
+	LastOrderAt *time.Time `json:"last_order_at"`
+	ComputedAt  time.Time  `json:"computed_at"`
+}
+
+type CustomerSegment struct {
+	ID          uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name        string    `gorm:"type:varchar(100);not null" json:"name" binding:"required"`
+	Description string    `json:"description"`
+	Rule        string    `gorm:"not null" json:"rule" binding:"required" example:"spent > 1,000,000 and last_order_days < 30"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+type SegmentMember struct {
+	CustomerID  uint       `json:"customer_id"`
+	Name        string     `json:"name"`
+	Email       string     `json:"email"`
+	Phone       string     `json:"phone"`
+	Tags        []string   `gorm:"serializer:json" json:"tags"`
+	RecencyDays *int       `json:"recency_days"`
+	Frequency   int        `json:"frequency"`
+	Monetary    float64    `json:"monetary"`
+	Score       string     `json:"score"`
+	LastOrderAt *time.Time `json:"last_order_at"`
+}
+
+// ScoreRFM fills in the recency in days and the quintile scores of every row.
+// Customers without completed orders always score 1 on recency.
+func ScoreRFM(rows []CustomerRFM, now time.Time) {
+	recency := make([]float64, len(rows))
+	frequency := make([]float64, len(rows))
+	monetary := make([]float64, len(rows))
+	for i := range rows {
+		rows[i].RecencyDays = nil
+		recency[i] = math.Inf(-1)
+		if rows[i].LastOrderAt != nil {
+			days := int(now.Sub(*rows[i].LastOrderAt).Hours() / 24)
+			rows[i].RecencyDays = &days
+			// fewer days since the last order is better
+			recency[i] = -float64(days)
+		}
+		frequency[i] = float64(rows[i].Frequency)
+		monetary[i] = rows[i].Monetary
+	}
+
+	rScores, fScores, mScores := quintiles(recency), quintiles(frequency), quintiles(monetary)
+	for i := range rows {
+		rows[i].RScore, rows[i].FScore, rows[i].MScore = rScores[i], fScores[i], mScores[i]
+		rows[i].Score = fmt.Sprintf("%d%d%d", rows[i].RScore, rows[i].FScore, rows[i].MScore)
+		rows[i].ComputedAt = now
+	}
+}
+
+// quintiles scores every value 1 to 5 by how many values are lower, equal
+// values share a score.
+func quintiles(values []float64) []int {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	scores := make([]int, len(values))
+	for i, value := range values {
+		lower := sort.SearchFloat64s(sorted, value)
+		scores[i] = 1 + lower*5/len(values)
+	}
+	return scores
+}
@@ -0,0 +1,62 @@
+package domain_test
+
+import (
+	"project/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSegmentRule(t *testing.T) {
+	t.Run("Successfully parse a rule into SQL", func(t *testing.T) {
+		rule, err := domain.ParseSegmentRule("spent > 1,000,000 and (last_order_days < 30 or orders >= 5)")
+		assert.NoError(t, err)
+
+		condition, args := rule.SQL("r")
+		assert.Equal(t, "(r.monetary > ? AND (r.recency_days < ? OR r.frequency >= ?))", condition)
+		assert.Equal(t, []interface{}{float64(1000000), float64(30), float64(5)}, args)
+	})
+
+	t.Run("Successfully match a customer", func(t *testing.T) {
+		rule, err := domain.ParseSegmentRule("spent > 1000000 AND last_order_days < 30")
+		assert.NoError(t, err)
+
+		days := 12
+		assert.True(t, rule.Match(domain.CustomerRFM{Monetary: 1500000, RecencyDays: &days}))
+		assert.False(t, rule.Match(domain.CustomerRFM{Monetary: 500000, RecencyDays: &days}))
+		assert.False(t, rule.Match(domain.CustomerRFM{Monetary: 1500000}))
+	})
+
+	for _, rule := range []string{"", "spent >", "age > 30", "spent 30", "(spent > 1", "spent > 1 and", "spent > 1; drop table"} {
+		t.Run("Failed to parse "+rule, func(t *testing.T) {
+			_, err := domain.ParseSegmentRule(rule)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestScoreRFM(t *testing.T) {
+	now := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	at := func(days int) *time.Time {
+		date := now.AddDate(0, 0, -days)
+		return &date
+	}
+
+	rows := []domain.CustomerRFM{
+		{CustomerID: 1, LastOrderAt: at(3), Frequency: 10, Monetary: 5000000},
+		{CustomerID: 2, LastOrderAt: at(40), Frequency: 4, Monetary: 1000000},
+		{CustomerID: 3, LastOrderAt: at(100), Frequency: 2, Monetary: 300000},
+		{CustomerID: 4, LastOrderAt: at(200), Frequency: 1, Monetary: 100000},
+		{CustomerID: 5},
+	}
+	domain.ScoreRFM(rows, now)
+
+	assert.Equal(t, "555", rows[0].Score)
+	assert.Equal(t, 3, *rows[0].RecencyDays)
+	assert.Equal(t, "444", rows[1].Score)
+	assert.Equal(t, "222", rows[3].Score)
+	assert.Equal(t, "111", rows[4].Score)
+	assert.Nil(t, rows[4].RecencyDays)
+	assert.Equal(t, now, rows[4].ComputedAt)
+}
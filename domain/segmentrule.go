@@ -0,0 +1,249 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// segmentFields maps the names usable in a segment rule to the columns of
+// customer_rfms.
+var segmentFields = map[string]string{
+	"spent":           "monetary",
+	"orders":          "frequency",
+	"last_order_days": "recency_days",
+	"r_score":         "r_score",
+	"f_score":         "f_score",
+	"m_score":         "m_score",
+}
+
+var segmentOperators = map[string]bool{"=": true, "!=": true, ">": true, ">=": true, "<": true, "<=": true}
+
+// SegmentRule is a parsed rule expression such as
+// "spent > 1,000,000 and last_order_days < 30". Comparisons on spent, orders,
+// last_order_days, r_score, f_score and m_score can be combined with and, or
+// and parentheses.
+type SegmentRule struct {
+	root segmentNode
+}
+
+type segmentNode struct {
+	// comparison
+	field    string
+	operator string
+	value    float64
+	// and/or of children
+	connective string
+	children   []segmentNode
+}
+
+func ParseSegmentRule(rule string) (SegmentRule, error) {
+	tokens, err := tokenizeSegmentRule(rule)
+	if err != nil {
+		return SegmentRule{}, err
+	}
+	parser := segmentParser{tokens: tokens}
+	root, err := parser.or()
+	if err != nil {
+		return SegmentRule{}, err
+	}
+	if parser.pos < len(parser.tokens) {
+		return SegmentRule{}, fmt.Errorf("unexpected %q in rule", parser.tokens[parser.pos])
+	}
+	return SegmentRule{root: root}, nil
+}
+
+// SQL renders the rule as a condition on the customer_rfms columns, prefixed
+// with the given table alias.
+func (rule SegmentRule) SQL(alias string) (string, []interface{}) {
+	var args []interface{}
+	return rule.root.sql(alias, &args), args
+}
+
+// Match evaluates the rule against the RFM values of a customer.
+func (rule SegmentRule) Match(rfm CustomerRFM) bool {
+	return rule.root.match(rfm)
+}
+
+func (node segmentNode) sql(alias string, args *[]interface{}) string {
+	if node.connective == "" {
+		*args = append(*args, node.value)
+		return fmt.Sprintf("%s.%s %s ?", alias, segmentFields[node.field], node.operator)
+	}
+	parts := make([]string, len(node.children))
+	for i, child := range node.children {
+		parts[i] = child.sql(alias, args)
+	}
+	return "(" + strings.Join(parts, " "+strings.ToUpper(node.connective)+" ") + ")"
+}
+
+func (node segmentNode) match(rfm CustomerRFM) bool {
+	switch node.connective {
+	case "and":
+		for _, child := range node.children {
+			if !child.match(rfm) {
+				return false
+			}
+		}
+		return true
+	case "or":
+		for _, child := range node.children {
+			if child.match(rfm) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var value float64
+	switch node.field {
+	case "spent":
+		value = rfm.Monetary
+	case "orders":
+		value = float64(rfm.Frequency)
+	case "last_order_days":
+		// like NULL in SQL, customers without orders never match
+		if rfm.RecencyDays == nil {
+			return false
+		}
+		value = float64(*rfm.RecencyDays)
+	case "r_score":
+		value = float64(rfm.RScore)
+	case "f_score":
+		value = float64(rfm.FScore)
+	case "m_score":
+		value = float64(rfm.MScore)
+	}
+
+	switch node.operator {
+	case "=":
+		return value == node.value
+	case "!=":
+		return value != node.value
+	case ">":
+		return value > node.value
+	case ">=":
+		return value >= node.value
+	case "<":
+		return value < node.value
+	}
+	return value <= node.value
+}
+
+func tokenizeSegmentRule(rule string) ([]string, error) {
+	var tokens []string
+	runes := []rune(rule)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')':
+			tokens = append(tokens, string(r))
+			i++
+		case strings.ContainsRune("<>=!", r):
+			j := i + 1
+			if j < len(runes) && runes[j] == '=' {
+				j++
+			}
+			tokens = append(tokens, string(runes[i:j]))
+			i = j
+		case unicode.IsDigit(r):
+			j := i
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || strings.ContainsRune("._,", runes[j])) {
+				j++
+			}
+			tokens = append(tokens, string(runes[i:j]))
+			i = j
+		case unicode.IsLetter(r) || r == '_':
+			j := i
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_') {
+				j++
+			}
+			tokens = append(tokens, strings.ToLower(string(runes[i:j])))
+			i = j
+		default:
+			return nil, fmt.Errorf("unexpected %q in rule", r)
+		}
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("rule is empty")
+	}
+	return tokens, nil
+}
+
+type segmentParser struct {
+	tokens []string
+	pos    int
+}
+
+func (parser *segmentParser) peek() string {
+	if parser.pos < len(parser.tokens) {
+		return parser.tokens[parser.pos]
+	}
+	return ""
+}
+
+func (parser *segmentParser) next() string {
+	token := parser.peek()
+	parser.pos++
+	return token
+}
+
+func (parser *segmentParser) or() (segmentNode, error) {
+	return parser.connected("or", parser.and)
+}
+
+func (parser *segmentParser) and() (segmentNode, error) {
+	return parser.connected("and", parser.factor)
+}
+
+func (parser *segmentParser) connected(connective string, operand func() (segmentNode, error)) (segmentNode, error) {
+	first, err := operand()
+	if err != nil {
+		return segmentNode{}, err
+	}
+	children := []segmentNode{first}
+	for parser.peek() == connective {
+		parser.next()
+		child, err := operand()
+		if err != nil {
+			return segmentNode{}, err
+		}
+		children = append(children, child)
+	}
+	if len(children) == 1 {
+		return first, nil
+	}
+	return segmentNode{connective: connective, children: children}, nil
+}
+
+func (parser *segmentParser) factor() (segmentNode, error) {
+	if parser.peek() == "(" {
+		parser.next()
+		node, err := parser.or()
+		if err != nil {
+			return segmentNode{}, err
+		}
+		if parser.next() != ")" {
+			return segmentNode{}, fmt.Errorf("missing closing parenthesis in rule")
+		}
+		return node, nil
+	}
+
+	field := parser.next()
+	if _, ok := segmentFields[field]; !ok {
+		return segmentNode{}, fmt.Errorf("unknown field %q, use spent, orders, last_order_days, r_score, f_score or m_score", field)
+	}
+	operator := parser.next()
+	if !segmentOperators[operator] {
+		return segmentNode{}, fmt.Errorf("expected a comparison after %s", field)
+	}
+	number := parser.next()
+	value, err := strconv.ParseFloat(strings.NewReplacer(",", "", "_", "").Replace(number), 64)
+	if err != nil {
+		return segmentNode{}, fmt.Errorf("expected a number after %s %s", field, operator)
+	}
+	return segmentNode{field: field, operator: operator, value: value}, nil
+}
@@ -0,0 +1,146 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+)
+
+// TopKeywords is how many keywords a product's sentiment summary keeps.
+const TopKeywords = 10
+
+// ScoredReview is the part of an analyzed review sentiment summaries are made of.
+type ScoredReview struct {
+	ProductID int
+	Sentiment float64
+	Keywords  []string `gorm:"serializer:json"`
+	CreatedAt time.Time
+}
+
+type KeywordCount struct {
+	Keyword string `json:"keyword"`
+	Count   int    `json:"count"`
+}
+
+// ProductSentiment sums up the reviews of a product over the latest window
+// against the window before it. Change is how much the average sentiment moved
+// between the two, it's empty when there were no reviews in the window before.
+type ProductSentiment struct {
+	ProductID         int            `gorm:"primaryKey;autoIncrement:false" json:"product_id"`
+	Name              string         `gorm:"->;-:migration" json:"name"`
+	Reviews           int            `json:"reviews"`
+	Sentiment         float64        `gorm:"type:float" json:"sentiment"`
+	Positive          int            `json:"positive"`
+	Negative          int            `json:"negative"`
+	PreviousReviews   int            `json:"previous_reviews"`
+	PreviousSentiment *float64       `gorm:"type:float" json:"previous_sentiment"`
+	Change            *float64       `gorm:"type:float;index" json:"change"`
+	Keywords          []KeywordCount `gorm:"type:jsonb;serializer:json" json:"keywords"`
+	ComputedAt        time.Time      `json:"computed_at"`
+}
+
+// neutralSentiment is the score below which a review is neither positive nor
+// negative.
+const neutralSentiment = 0.05
+
+// SummarizeSentiment sums up the reviews per product, the ones created from
+// split on make up the latest window and the earlier ones the window before.
+// Products without reviews in the latest window are left out.
+func SummarizeSentiment(reviews []ScoredReview, split, now time.Time) []ProductSentiment {
+	type totals struct {
+		current, previous float64
+		keywords          map[string]int
+	}
+	summaries := map[int]*ProductSentiment{}
+	sums := map[int]*totals{}
+	for _, review := range reviews {
+		summary, ok := summaries[review.ProductID]
+		if !ok {
+			summary = &ProductSentiment{ProductID: review.ProductID, ComputedAt: now}
+			summaries[review.ProductID] = summary
+			sums[review.ProductID] = &totals{keywords: map[string]int{}}
+		}
+		sum := sums[review.ProductID]
+
+		if review.CreatedAt.Before(split) {
+			summary.PreviousReviews++
+			sum.previous += review.Sentiment
+			continue
+		}
+		summary.Reviews++
+		sum.current += review.Sentiment
+		if review.Sentiment >= neutralSentiment {
+			summary.Positive++
+		} else if review.Sentiment <= -neutralSentiment {
+			summary.Negative++
+		}
+		for _, keyword := range review.Keywords {
+			sum.keywords[keyword]++
+		}
+	}
+
+	result := []ProductSentiment{}
+	for productId, summary := range summaries {
+		if summary.Reviews == 0 {
+			continue
+		}
+		sum := sums[productId]
+		summary.Sentiment = roundSentiment(sum.current / float64(summary.Reviews))
+		if summary.PreviousReviews > 0 {
+			previous := roundSentiment(sum.previous / float64(summary.PreviousReviews))
+			change := roundSentiment(summary.Sentiment - previous)
+			summary.PreviousSentiment = &previous
+			summary.Change = &change
+		}
+		summary.Keywords = topKeywords(sum.keywords)
+		result = append(result, *summary)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ProductID < result[j].ProductID })
+	return result
+}
+
+func topKeywords(counts map[string]int) []KeywordCount {
+	keywords := make([]KeywordCount, 0, len(counts))
+	for keyword, count := range counts {
+		keywords = append(keywords, KeywordCount{keyword, count})
+	}
+	sort.Slice(keywords, func(i, j int) bool {
+		if keywords[i].Count != keywords[j].Count {
+			return keywords[i].Count > keywords[j].Count
+		}
+		return keywords[i].Keyword < keywords[j].Keyword
+	})
+	if len(keywords) > TopKeywords {
+		keywords = keywords[:TopKeywords]
+	}
+	return keywords
+}
+
+func roundSentiment(score float64) float64 {
+	return math.Round(score*1000) / 1000
+}
+
+// SentimentDeclineFilter picks the products whose sentiment dropped by at
+// least MinDrop, with MinReviews reviews in both windows so a single review
+// doesn't make a trend.
+type SentimentDeclineFilter struct {
+	MinDrop    float64 `form:"min_drop" json:"min_drop"`
+	MinReviews int     `form:"min_reviews" json:"min_reviews"`
+}
+
+func (filter *SentimentDeclineFilter) Validate() error {
+	if filter.MinDrop == 0 {
+		filter.MinDrop = 0.1
+	}
+	if filter.MinReviews == 0 {
+		filter.MinReviews = 3
+	}
+	if filter.MinDrop < 0 || filter.MinDrop > 2 {
+		return errors.New("min_drop must be between 0 and 2")
+	}
+	if filter.MinReviews < 0 {
+		return errors.New("min_reviews must be positive")
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package domain_test
+
+import (
+	"project/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummarizeSentiment(t *testing.T) {
+	now := time.Date(2024, 6, 30, 4, 0, 0, 0, time.UTC)
+	split := now.AddDate(0, 0, -30)
+	before := split.AddDate(0, 0, -1)
+	after := split.AddDate(0, 0, 1)
+
+	reviews := []domain.ScoredReview{
+		{ProductID: 2, Sentiment: 0.8, CreatedAt: before},
+		{ProductID: 2, Sentiment: 0.6, CreatedAt: before},
+		{ProductID: 2, Sentiment: -0.6, Keywords: []string{"sobek", "jahitan"}, CreatedAt: after},
+		{ProductID: 2, Sentiment: 0.2, Keywords: []string{"jahitan", "bagus"}, CreatedAt: after},
+		{ProductID: 2, Sentiment: 0, Keywords: []string{"warna"}, CreatedAt: after},
+		{ProductID: 1, Sentiment: 0.9, Keywords: []string{"mantap"}, CreatedAt: after},
+		{ProductID: 3, Sentiment: 0.5, CreatedAt: before},
+	}
+
+	t.Run("Successfully compare the latest window with the one before", func(t *testing.T) {
+		summaries := domain.SummarizeSentiment(reviews, split, now)
+
+		assert.Len(t, summaries, 2)
+		assert.Equal(t, 1, summaries[0].ProductID)
+		assert.Nil(t, summaries[0].Change)
+
+		declining := summaries[1]
+		assert.Equal(t, 2, declining.ProductID)
+		assert.Equal(t, 3, declining.Reviews)
+		assert.Equal(t, -0.133, declining.Sentiment)
+		assert.Equal(t, 1, declining.Positive)
+		assert.Equal(t, 1, declining.Negative)
+		assert.Equal(t, 2, declining.PreviousReviews)
+		assert.Equal(t, 0.7, *declining.PreviousSentiment)
+		assert.Equal(t, -0.833, *declining.Change)
+		assert.Equal(t, []domain.KeywordCount{{Keyword: "jahitan", Count: 2}, {Keyword: "bagus", Count: 1}, {Keyword: "sobek", Count: 1}, {Keyword: "warna", Count: 1}}, declining.Keywords)
+		assert.Equal(t, now, declining.ComputedAt)
+	})
+
+	t.Run("Successfully summarize no reviews", func(t *testing.T) {
+		assert.Empty(t, domain.SummarizeSentiment(nil, split, now))
+	})
+}
+
+func TestSentimentDeclineFilterValidate(t *testing.T) {
+	t.Run("Successfully default the thresholds", func(t *testing.T) {
+		filter := domain.SentimentDeclineFilter{}
+
+		assert.NoError(t, filter.Validate())
+		assert.Equal(t, 0.1, filter.MinDrop)
+		assert.Equal(t, 3, filter.MinReviews)
+	})
+
+	t.Run("Failed with a negative drop", func(t *testing.T) {
+		filter := domain.SentimentDeclineFilter{MinDrop: -0.2}
+
+		assert.Error(t, filter.Validate())
+	})
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+type ShipmentStatus string
+
+const (
+	ShipmentInTransit ShipmentStatus = "in_transit"
+	ShipmentDelivered ShipmentStatus = "delivered"
+)
+
+type Shipment struct {
+	ID             uint           `gorm:"primaryKey;autoIncrement" json:"id"`
+	OrderID        uint           `gorm:"not null;index" json:"order_id"`
+	Carrier        string         `gorm:"type:varchar(50);not null" json:"carrier"`
+	Service        string         `gorm:"type:varchar(50)" json:"service"`
+	TrackingNumber string         `gorm:"type:varchar(100);not null" json:"tracking_number"`
+	Weight         float64        `gorm:"type:float" json:"weight"`
+	Cost           float64        `gorm:"type:float" json:"cost"`
+	Status         ShipmentStatus `gorm:"type:varchar(20);default:in_transit" json:"status"`
+	ShippedAt      time.Time      `json:"shipped_at"`
+	DeliveredAt    *time.Time     `json:"delivered_at"`
+	CreatedAt      time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+type NewShipment struct {
+	Carrier string  `json:"carrier" binding:"required"`
+	Service string  `json:"service"`
+	Weight  float64 `json:"weight" binding:"required,gt=0"`
+}
+
+// CanShip reports whether a shipment can be created for an order with the
+// given status: processed orders get their first parcel, completed orders
+// may get additional ones.
+func CanShip(status Status) error {
+	if status != Processed && status != Completed {
+		return fmt.Errorf("order with status %s can't be shipped", status)
+	}
+	return nil
+}
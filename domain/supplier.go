@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+type Supplier struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `gorm:"type:varchar(100);not null" json:"name" binding:"required"`
+	Email     string    `gorm:"type:varchar(100)" json:"email"`
+	Phone     string    `gorm:"type:varchar(30)" json:"phone"`
+	Address   string    `json:"address"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func SupplierSeed() []Supplier {
+	return []Supplier{
+		{Name: "Supplier Satu", Email: "satu@supplier.com", Phone: "081200000001", Address: "Alamat Supplier Satu"},
+		{Name: "Supplier Dua", Email: "dua@supplier.com", Phone: "081200000002", Address: "Alamat Supplier Dua"},
+		{Name: "Supplier Tiga", Email: "tiga@supplier.com", Phone: "081200000003", Address: "Alamat Supplier Tiga"},
+	}
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// TrashEntity names a catalog entity whose deleted rows are kept in the trash
+// and can be restored until they are purged.
+type TrashEntity string
+
+const (
+	TrashProducts   TrashEntity = "products"
+	TrashVariants   TrashEntity = "variants"
+	TrashImages     TrashEntity = "images"
+	TrashBanners    TrashEntity = "banners"
+	TrashCategories TrashEntity = "categories"
+	TrashPromotions TrashEntity = "promotions"
+)
+
+var TrashEntities = []TrashEntity{TrashProducts, TrashVariants, TrashImages, TrashBanners, TrashCategories, TrashPromotions}
+
+// ErrStillReferenced is returned when purging a row orders or purchase orders
+// still refer to, it stays in the trash to keep their history.
+var ErrStillReferenced = errors.New("still referenced by orders, it can't be purged")
+
+func (entity TrashEntity) Valid() bool {
+	for _, known := range TrashEntities {
+		if entity == known {
+			return true
+		}
+	}
+	return false
+}
+
+// TrashItem is a deleted row as listed in the trash.
+type TrashItem struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// TrashPurge reports how many rows of an entity were purged, rows still
+// referenced by orders are skipped and rows that couldn't be purged failed.
+type TrashPurge struct {
+	Entity  TrashEntity `json:"entity"`
+	Purged  int         `json:"purged"`
+	Skipped int         `json:"skipped"`
+	Failed  int         `json:"failed"`
+}
@@ -0,0 +1,16 @@
+package domain_test
+
+import (
+	"project/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrashEntity(t *testing.T) {
+	for _, entity := range domain.TrashEntities {
+		assert.True(t, entity.Valid(), entity)
+	}
+	assert.False(t, domain.TrashEntity("orders").Valid())
+	assert.False(t, domain.TrashEntity("").Valid())
+}
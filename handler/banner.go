@@ -1,13 +1,12 @@
 package handler
 
 import (
-	"log"
 	"net/http"
 	"project/domain"
 	"project/helper"
+	"project/imaging"
 	"project/service"
 	"strings"
-	"sync"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -15,11 +14,12 @@ import (
 
 type ControllerBanner struct {
 	service service.ServiceBanner
+	images  *imaging.Pipeline
 	logger  *zap.Logger
 }
 
-func NewControllerBanner(service service.ServiceBanner, logger *zap.Logger) *ControllerBanner {
-	return &ControllerBanner{service: service, logger: logger}
+func NewControllerBanner(service service.ServiceBanner, images *imaging.Pipeline, logger *zap.Logger) *ControllerBanner {
+	return &ControllerBanner{service: service, images: images, logger: logger}
 }
 
 // @Summary Get All Banner
@@ -80,54 +80,19 @@ func (ctrl *ControllerBanner) GetById(c *gin.Context) {
 // @Failure 500 {object} handler.Response "Internal Server Error"
 // @Router /banner [post]
 func (ctrl *ControllerBanner) Create(c *gin.Context) {
-	doUpload := true
-	var respThirdParty []domain.CdnResponse
-	_, err := c.FormFile("images")
+	image, err := ctrl.uploadImage(c)
 	if err != nil {
-		doUpload = false
-		respThirdParty = append(respThirdParty, domain.CdnResponse{Data: struct {
-			FileId      string "json:\"fileId\""
-			Name        string "json:\"name\""
-			Size        int    "json:\"size\""
-			VersionInfo struct {
-				Id   string "json:\"id\""
-				Name string "json:\"name\""
-			} "json:\"versionInfo\""
-			FilePath     string      "json:\"filePath\""
-			Url          string      "json:\"url\""
-			FileType     string      "json:\"fileType\""
-			Height       int         "json:\"height\""
-			Width        int         "json:\"width\""
-			ThumbnailUrl string      "json:\"thumbnailUrl\""
-			AITags       interface{} "json:\"AITags\""
-		}{Url: ""}})
-	}
-	if doUpload {
-		form, err := c.MultipartForm()
-		if err != nil {
-			log.Println("Error reading form data:", err)
-			BadResponse(c, "Invalid form data: "+err.Error(), http.StatusBadRequest)
-			return
-		}
-		files := form.File["images"]
-		for _, file := range files {
-			log.Println("File size:", file.Size)
-		}
-
-		var wg sync.WaitGroup
-		respThirdParty, err = helper.Upload(&wg, files)
-		if err != nil {
-			BadResponse(c, "Failed to upload images: "+err.Error(), http.StatusInternalServerError)
-			return
-		}
+		BadResponse(c, "Failed to upload images: "+err.Error(), uploadStatus(err))
+		return
 	}
 	banner := domain.Banner{
-		Title:     c.PostForm("title"),
-		PathPage:  c.PostForm("pathPage"),
-		StartDate: c.PostForm("startDate"),
-		EndDate:   c.PostForm("endDate"),
-		IsPublish: false,
-		ImageUrl:  respThirdParty[0].Data.Url,
+		Title:           c.PostForm("title"),
+		PathPage:        c.PostForm("pathPage"),
+		StartDate:       c.PostForm("startDate"),
+		EndDate:         c.PostForm("endDate"),
+		IsPublish:       false,
+		ImageUrl:        image.URL,
+		ImageRenditions: image.Renditions,
 	}
 	err = ctrl.service.Create(&banner)
 	if err != nil {
@@ -157,55 +122,20 @@ func (ctrl *ControllerBanner) Edit(c *gin.Context) {
 		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
 		return
 	}
-	doUpload := true
-	var respThirdParty []domain.CdnResponse
-	_, err = c.FormFile("images")
+	image, err := ctrl.uploadImage(c)
 	if err != nil {
-		doUpload = false
-		respThirdParty = append(respThirdParty, domain.CdnResponse{Data: struct {
-			FileId      string "json:\"fileId\""
-			Name        string "json:\"name\""
-			Size        int    "json:\"size\""
-			VersionInfo struct {
-				Id   string "json:\"id\""
-				Name string "json:\"name\""
-			} "json:\"versionInfo\""
-			FilePath     string      "json:\"filePath\""
-			Url          string      "json:\"url\""
-			FileType     string      "json:\"fileType\""
-			Height       int         "json:\"height\""
-			Width        int         "json:\"width\""
-			ThumbnailUrl string      "json:\"thumbnailUrl\""
-			AITags       interface{} "json:\"AITags\""
-		}{Url: ""}})
-	}
-	if doUpload {
-		form, err := c.MultipartForm()
-		if err != nil {
-			log.Println("Error reading form data:", err)
-			BadResponse(c, "Invalid form data: "+err.Error(), http.StatusBadRequest)
-			return
-		}
-		files := form.File["images"]
-		for _, file := range files {
-			log.Println("File size:", file.Size)
-		}
-
-		var wg sync.WaitGroup
-		respThirdParty, err = helper.Upload(&wg, files)
-		if err != nil {
-			BadResponse(c, "Failed to upload images: "+err.Error(), http.StatusInternalServerError)
-			return
-		}
+		BadResponse(c, "Failed to upload images: "+err.Error(), uploadStatus(err))
+		return
 	}
 	banner := domain.Banner{
-		ID:        id,
-		Title:     c.PostForm("title"),
-		PathPage:  c.PostForm("pathPage"),
-		StartDate: c.PostForm("startDate"),
-		EndDate:   c.PostForm("endDate"),
-		IsPublish: strings.ToLower(c.PostForm("isPublish")) == "true",
-		ImageUrl:  respThirdParty[0].Data.Url,
+		ID:              id,
+		Title:           c.PostForm("title"),
+		PathPage:        c.PostForm("pathPage"),
+		StartDate:       c.PostForm("startDate"),
+		EndDate:         c.PostForm("endDate"),
+		IsPublish:       strings.ToLower(c.PostForm("isPublish")) == "true",
+		ImageUrl:        image.URL,
+		ImageRenditions: image.Renditions,
 	}
 	err = ctrl.service.Edit(&banner)
 	if err != nil {
@@ -240,3 +170,21 @@ func (ctrl *ControllerBanner) Delete(c *gin.Context) {
 	}
 	GoodResponseWithData(c, "This Banner was successfully deleted", http.StatusCreated, banner)
 }
+
+// uploadImage stores the banner image of the form with its renditions, the
+// upload is empty when the form has none.
+func (ctrl *ControllerBanner) uploadImage(c *gin.Context) (imaging.Upload, error) {
+	if _, err := c.FormFile("images"); err != nil {
+		return imaging.Upload{}, nil
+	}
+	form, err := c.MultipartForm()
+	if err != nil {
+		return imaging.Upload{}, err
+	}
+	uploads, err := ctrl.images.UploadAll(c.Request.Context(), form.File["images"][:1])
+	if err != nil {
+		ctrl.logger.Error("Failed to upload banner image", zap.Error(err))
+		return imaging.Upload{}, err
+	}
+	return uploads[0], nil
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"net/http"
+	"project/domain"
+	"project/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type ControllerBulkOrder struct {
+	service service.ServiceBulkOrder
+	logger  *zap.Logger
+}
+
+func NewControllerBulkOrder(service service.ServiceBulkOrder, logger *zap.Logger) *ControllerBulkOrder {
+	return &ControllerBulkOrder{service: service, logger: logger}
+}
+
+// @Summary Bulk order action
+// @Description Process, cancel or ship many orders at once with a result per order. With async the job runs in the background and its progress is polled at /orders/bulk/{id}
+// @Tags Order
+// @Accept  json
+// @Produce  json
+// @Param request body domain.BulkOrderRequest true "Order IDs, action and tracking numbers per order ID for shipping"
+// @Success 200 {object} handler.Response{data=domain.BulkJob} "bulk action finished"
+// @Success 202 {object} handler.Response{data=domain.BulkJob} "bulk action started"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Router  /orders/bulk [post]
+func (ctrl *ControllerBulkOrder) Run(c *gin.Context) {
+	var request domain.BulkOrderRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		BadResponse(c, "Bad Request (Body)", http.StatusBadRequest)
+		return
+	}
+	job, err := ctrl.service.Run(request)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if request.Async {
+		GoodResponseWithData(c, "bulk action started", http.StatusAccepted, job)
+		return
+	}
+	GoodResponseWithData(c, "bulk action finished", http.StatusOK, job)
+}
+
+// @Summary Bulk order job progress
+// @Description Get the progress and per-order results of an asynchronous bulk order action
+// @Tags Order
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Bulk job ID"
+// @Success 200 {object} handler.Response{data=domain.BulkJob} "bulk job retrieved"
+// @Failure 404 {object} handler.Response "bulk job not found"
+// @Router  /orders/bulk/{id} [get]
+func (ctrl *ControllerBulkOrder) GetJob(c *gin.Context) {
+	job, err := ctrl.service.GetJob(c.Param("id"))
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusNotFound)
+		return
+	}
+	GoodResponseWithData(c, "bulk job retrieved", http.StatusOK, job)
+}
@@ -1,13 +1,13 @@
 package handler
 
 import (
-	"mime/multipart"
+	"errors"
 	"net/http"
 	"project/domain"
 	"project/helper"
+	"project/imaging"
 	"project/service"
 	"strconv"
-	"sync"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -19,6 +19,9 @@ type CategoryHandler interface {
 	GetCategoryByID(c *gin.Context)
 	CreateCategory(c *gin.Context)
 	UpdateCategory(c *gin.Context)
+	ShowCategoryProducts(c *gin.Context)
+	ShowCategoryTree(c *gin.Context)
+	MoveCategory(c *gin.Context)
 }
 
 type categoryHandler struct {
@@ -64,20 +67,38 @@ func (ch *categoryHandler) ShowAllCategory(c *gin.Context) {
 }
 
 // @Summary Delete a category
-// @Description Deletes a category by its ID
+// @Description Deletes a category by its ID. A category that still has products is only deleted with force=true, which moves them to reassign_to when given
 // @Tags Category
 // @Accept json
 // @Produce json
 // @Param id path int true "Category ID"
+// @Param force query bool false "Delete even when the category has products"
+// @Param reassign_to query int false "Category receiving the products"
 // @Success 200 {object} handler.Response{data=domain.Category} "Successfully deleted category"
+// @Failure 400 {object} handler.Response "Invalid delete options"
 // @Failure 404 {object} handler.Response "Failed to delete category"
+// @Failure 409 {object} handler.Response "Category still has products"
 // @Router /category/{id} [delete]
 func (ch *categoryHandler) DeleteCategory(c *gin.Context) {
 
 	id, _ := strconv.Atoi(c.Param("id"))
 
-	if err := ch.service.Category.DeleteCategory(id); err != nil {
-		BadResponse(c, "Failed to deleted categoriy: "+err.Error(), http.StatusNotFound)
+	var options domain.CategoryDelete
+	if err := c.ShouldBindQuery(&options); err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	if err := options.Validate(uint(id)); err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := ch.service.Category.DeleteCategory(id, options); err != nil {
+		status := http.StatusNotFound
+		if errors.Is(err, domain.ErrCategoryHasProducts) {
+			status = http.StatusConflict
+		}
+		BadResponse(c, "Failed to deleted categoriy: "+err.Error(), status)
 		return
 	}
 
@@ -86,7 +107,7 @@ func (ch *categoryHandler) DeleteCategory(c *gin.Context) {
 }
 
 // @Summary Get a category by ID
-// @Description Retrieves a category by its ID
+// @Description Retrieves a category by its ID with the breadcrumbs from its root category
 // @Tags Category
 // @Accept json
 // @Produce json
@@ -113,6 +134,7 @@ func (ch *categoryHandler) GetCategoryByID(c *gin.Context) {
 // @Accept multipart/form-data
 // @Produce json
 // @Param name formData string true "Category Name"
+// @Param parent_id formData int false "Parent Category ID"
 // @Param images formData file true "Category Image"
 // @Success 201 {object} handler.Response{data=domain.Category} "Category created successfully"
 // @Failure 400 {object} handler.Response "Bad request, invalid data"
@@ -131,11 +153,9 @@ func (ch *categoryHandler) CreateCategory(c *gin.Context) {
 		return
 	}
 
-	var wg sync.WaitGroup
-
-	responses, err := helper.Upload(&wg, []*multipart.FileHeader{files[0]})
-	if err != nil || len(responses) == 0 {
-		BadResponse(c, "Failed to upload image: "+err.Error(), http.StatusInternalServerError)
+	uploads, err := ch.service.Images.UploadAll(c.Request.Context(), files[:1])
+	if err != nil {
+		BadResponse(c, "Failed to upload image: "+err.Error(), uploadStatus(err))
 		return
 	}
 
@@ -145,12 +165,22 @@ func (ch *categoryHandler) CreateCategory(c *gin.Context) {
 		return
 	}
 
-	imageURL := responses[0].Data.Url
+	var parentID *uint
+	if value := c.PostForm("parent_id"); value != "" {
+		id, err := helper.Uint(value)
+		if err != nil {
+			BadResponse(c, "Invalid parent category", http.StatusBadRequest)
+			return
+		}
+		parentID = &id
+	}
 
 	// Buat entitas kategori baru
 	category := domain.Category{
-		Name: name,
-		Icon: imageURL,
+		Name:           name,
+		Icon:           uploads[0].URL,
+		IconRenditions: uploads[0].Renditions,
+		ParentID:       parentID,
 	}
 
 	// Simpan kategori menggunakan service
@@ -184,16 +214,15 @@ func (ch *categoryHandler) UpdateCategory(c *gin.Context) {
 
 	form, _ := c.MultipartForm()
 
-	var imageURL string
+	var upload imaging.Upload
 	files := form.File["images"]
 	if len(files) > 0 {
-		var wg sync.WaitGroup
-		responses, err := helper.Upload(&wg, []*multipart.FileHeader{files[0]})
-		if err != nil || len(responses) == 0 {
-			BadResponse(c, "Failed to upload image: "+err.Error(), http.StatusInternalServerError)
+		uploads, err := ch.service.Images.UploadAll(c.Request.Context(), files[:1])
+		if err != nil {
+			BadResponse(c, "Failed to upload image: "+err.Error(), uploadStatus(err))
 			return
 		}
-		imageURL = responses[0].Data.Url
+		upload = uploads[0]
 	}
 
 	name := c.PostForm("name")
@@ -202,9 +231,10 @@ func (ch *categoryHandler) UpdateCategory(c *gin.Context) {
 	}
 
 	category = domain.Category{
-		ID:   uint(id),
-		Name: name,
-		Icon: imageURL,
+		ID:             uint(id),
+		Name:           name,
+		Icon:           upload.URL,
+		IconRenditions: upload.Renditions,
 	}
 
 	err := ch.service.Category.UpdateCategory(id, &category)
@@ -215,3 +245,92 @@ func (ch *categoryHandler) UpdateCategory(c *gin.Context) {
 
 	GoodResponseWithData(c, "Category updated successfully", http.StatusOK, category)
 }
+
+// @Summary Show category products
+// @Description Retrieves the products of a category with pagination support
+// @Tags Category
+// @Accept json
+// @Produce json
+// @Param id path int true "Category ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Limit per page" default(10)
+// @Param subtree query bool false "Include the products of the subcategories"
+// @Success 200 {object} handler.Response{data=[]domain.Product} "Successfully retrieved category products"
+// @Failure 404 {object} handler.Response "Failed to retrieve category products"
+// @Router /category/{id}/products [get]
+func (ch *categoryHandler) ShowCategoryProducts(c *gin.Context) {
+
+	id, _ := strconv.Atoi(c.Param("id"))
+
+	page, _ := strconv.Atoi(c.Query("page"))
+	if page <= 0 {
+		page = 1
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	if limit < 10 {
+		limit = 10
+	}
+
+	subtree, _ := strconv.ParseBool(c.Query("subtree"))
+
+	products, count, totalPages, err := ch.service.Category.ShowCategoryProducts(id, page, limit, subtree)
+	if err != nil {
+		BadResponse(c, "Failed to retrived category products: "+err.Error(), http.StatusNotFound)
+		return
+	}
+
+	GoodResponseWithPage(c, "successfully retrived category products", http.StatusOK, count, totalPages, page, limit, products)
+}
+
+// @Summary Show the category tree
+// @Description Retrieves all categories nested under their parents, ordered by position
+// @Tags Category
+// @Accept json
+// @Produce json
+// @Success 200 {object} handler.Response{data=[]domain.Category} "Successfully retrieved category tree"
+// @Failure 500 {object} handler.Response "Failed to retrieve category tree"
+// @Router /category/tree [get]
+func (ch *categoryHandler) ShowCategoryTree(c *gin.Context) {
+
+	tree, err := ch.service.Category.ShowCategoryTree()
+	if err != nil {
+		BadResponse(c, "Failed to retrived category tree: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	GoodResponseWithData(c, "successfully retrived category tree", http.StatusOK, tree)
+}
+
+// @Summary Move a category
+// @Description Moves a category under another parent, or to the root when parent_id is null, at a position among its siblings
+// @Tags Category
+// @Accept json
+// @Produce json
+// @Param id path int true "Category ID"
+// @Param move body domain.CategoryMove true "New parent and position"
+// @Success 200 {object} handler.Response{data=domain.Category} "Category moved successfully"
+// @Failure 400 {object} handler.Response "Failed to move category"
+// @Router /category/{id}/move [put]
+func (ch *categoryHandler) MoveCategory(c *gin.Context) {
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+
+	var move domain.CategoryMove
+	if err := c.ShouldBindJSON(&move); err != nil {
+		BadResponse(c, "Bad Request (Body)", http.StatusBadRequest)
+		return
+	}
+
+	category, err := ch.service.Category.MoveCategory(id, move)
+	if err != nil {
+		BadResponse(c, "Failed to move category: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	GoodResponseWithData(c, "Category moved successfully", http.StatusOK, category)
+}
@@ -0,0 +1,204 @@
+package handler
+
+import (
+	"net/http"
+	"project/domain"
+	"project/helper"
+	"project/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type ControllerCustomer struct {
+	service service.ServiceCustomer
+	logger  *zap.Logger
+}
+
+func NewControllerCustomer(service service.ServiceCustomer, logger *zap.Logger) *ControllerCustomer {
+	return &ControllerCustomer{service: service, logger: logger}
+}
+
+// @Summary Get all customers
+// @Description Fetches a paginated list of customers, optionally searched by name, email or phone and filtered by tag
+// @Tags Customer
+// @Accept  json
+// @Produce  json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Number of items per page" default(10)
+// @Param search query string false "Part of the name, email or phone"
+// @Param tag query string false "Tag"
+// @Success 200 {object} handler.Response{data=[]domain.Customer} "customers retrieved"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Failure 500 {object} handler.Response "server error"
+// @Router  /customers [get]
+func (ctrl *ControllerCustomer) GetAll(c *gin.Context) {
+	page, _ := helper.Uint(c.Query("page"))
+	if page == 0 {
+		page = 1
+	}
+	limit, _ := helper.Uint(c.Query("limit"))
+	if limit == 0 {
+		limit = 10
+	}
+
+	var filter domain.CustomerFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		BadResponse(c, "Bad Request (Query)", http.StatusBadRequest)
+		return
+	}
+
+	total, pages, customers, err := ctrl.service.GetAll(page, limit, filter)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	GoodResponseWithPage(c, "customers retrieved", http.StatusOK, total, pages, int(page), int(limit), customers)
+}
+
+// @Summary Get a customer by ID
+// @Description Get a customer with its addresses and order metrics
+// @Tags Customer
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Customer ID"
+// @Success 200 {object} handler.Response{data=domain.CustomerDetail} "customer retrieved"
+// @Failure 404 {object} handler.Response "customer not found"
+// @Router  /customers/{id} [get]
+func (ctrl *ControllerCustomer) GetById(c *gin.Context) {
+	id, err := helper.Uint(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	customer, err := ctrl.service.GetById(id)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusNotFound)
+		return
+	}
+	GoodResponseWithData(c, "customer retrieved", http.StatusOK, customer)
+}
+
+// @Summary Create a customer
+// @Description Create a customer with contact details, addresses, tags and notes
+// @Tags Customer
+// @Accept  json
+// @Produce  json
+// @Param customer body domain.Customer true "Customer data"
+// @Success 201 {object} handler.Response{data=domain.Customer} "customer created"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Router  /customers [post]
+func (ctrl *ControllerCustomer) Create(c *gin.Context) {
+	var customer domain.Customer
+	if err := c.ShouldBindJSON(&customer); err != nil {
+		BadResponse(c, "Bad Request (Body)", http.StatusBadRequest)
+		return
+	}
+	customer.ID = 0
+	if err := ctrl.service.Create(&customer); err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+	GoodResponseWithData(c, "customer created", http.StatusCreated, customer)
+}
+
+// @Summary Edit a customer
+// @Description Replace the details and addresses of a customer
+// @Tags Customer
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Customer ID"
+// @Param customer body domain.Customer true "Customer data"
+// @Success 200 {object} handler.Response{data=domain.Customer} "customer updated"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Router  /customers/{id} [put]
+func (ctrl *ControllerCustomer) Edit(c *gin.Context) {
+	id, err := helper.Uint(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	var customer domain.Customer
+	if err := c.ShouldBindJSON(&customer); err != nil {
+		BadResponse(c, "Bad Request (Body)", http.StatusBadRequest)
+		return
+	}
+	customer.ID = id
+	if err := ctrl.service.Edit(&customer); err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+	GoodResponseWithData(c, "customer updated", http.StatusOK, customer)
+}
+
+// @Summary Delete a customer
+// @Description Delete a customer that has no orders
+// @Tags Customer
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Customer ID"
+// @Success 200 {object} handler.Response "customer deleted"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Router  /customers/{id} [delete]
+func (ctrl *ControllerCustomer) Delete(c *gin.Context) {
+	id, err := helper.Uint(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	if err := ctrl.service.Delete(id); err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+	GoodResponseWithData(c, "customer deleted", http.StatusOK, nil)
+}
+
+// @Summary Customer orders
+// @Description Get the order history of a customer, with the same filters and sorting as /orders
+// @Tags Customer
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Customer ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Number of items per page" default(10)
+// @Param status query string false "created, processed, canceled, completed or delivered"
+// @Param sort_by query string false "created_at or total" default(created_at)
+// @Param sort_order query string false "asc or desc" default(desc)
+// @Success 200 {object} handler.Response{data=[]domain.OrderTotal} "orders retrieved"
+// @Failure 400 {object} handler.Response "invalid filter"
+// @Failure 404 {object} handler.Response "customer not found"
+// @Router  /customers/{id}/orders [get]
+func (ctrl *ControllerCustomer) GetOrders(c *gin.Context) {
+	id, err := helper.Uint(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	page, _ := helper.Uint(c.Query("page"))
+	if page == 0 {
+		page = 1
+	}
+	limit, _ := helper.Uint(c.Query("limit"))
+	if limit == 0 {
+		limit = 10
+	}
+
+	var filter domain.OrderFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		BadResponse(c, "invalid filter", http.StatusBadRequest)
+		return
+	}
+	if err := filter.Validate(); err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	total, pages, orders, err := ctrl.service.GetOrders(id, page, limit, filter)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	GoodResponseWithPage(c, "orders retrieved", http.StatusOK, total, pages, int(page), int(limit), orders)
+}
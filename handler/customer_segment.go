@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"net/http"
+	"project/domain"
+	"project/helper"
+	"project/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type ControllerSegment struct {
+	service service.ServiceSegment
+	logger  *zap.Logger
+}
+
+func NewControllerSegment(service service.ServiceSegment, logger *zap.Logger) *ControllerSegment {
+	return &ControllerSegment{service: service, logger: logger}
+}
+
+// @Summary Get all segments
+// @Description Fetches the saved customer segments
+// @Tags Customer
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} handler.Response{data=[]domain.CustomerSegment} "segments retrieved"
+// @Failure 500 {object} handler.Response "server error"
+// @Router  /customers/segments [get]
+func (ctrl *ControllerSegment) GetAll(c *gin.Context) {
+	segments, err := ctrl.service.GetAll()
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	GoodResponseWithData(c, "segments retrieved", http.StatusOK, segments)
+}
+
+// @Summary Get a segment by ID
+// @Description Get a customer segment with its rule
+// @Tags Customer
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Segment ID"
+// @Success 200 {object} handler.Response{data=domain.CustomerSegment} "segment retrieved"
+// @Failure 404 {object} handler.Response "segment not found"
+// @Router  /customers/segments/{id} [get]
+func (ctrl *ControllerSegment) GetById(c *gin.Context) {
+	id, err := helper.Uint(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	segment, err := ctrl.service.GetById(id)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusNotFound)
+		return
+	}
+	GoodResponseWithData(c, "segment retrieved", http.StatusOK, segment)
+}
+
+// @Summary Create a segment
+// @Description Save a segment defined by a rule over spent, orders, last_order_days, r_score, f_score and m_score, combined with and, or and parentheses
+// @Tags Customer
+// @Accept  json
+// @Produce  json
+// @Param segment body domain.CustomerSegment true "Segment data"
+// @Success 201 {object} handler.Response{data=domain.CustomerSegment} "segment created"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Router  /customers/segments [post]
+func (ctrl *ControllerSegment) Create(c *gin.Context) {
+	var segment domain.CustomerSegment
+	if err := c.ShouldBindJSON(&segment); err != nil {
+		BadResponse(c, "Bad Request (Body)", http.StatusBadRequest)
+		return
+	}
+	segment.ID = 0
+	if err := ctrl.service.Create(&segment); err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+	GoodResponseWithData(c, "segment created", http.StatusCreated, segment)
+}
+
+// @Summary Edit a segment
+// @Description Change the name, description or rule of a segment
+// @Tags Customer
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Segment ID"
+// @Param segment body domain.CustomerSegment true "Segment data"
+// @Success 200 {object} handler.Response{data=domain.CustomerSegment} "segment updated"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Router  /customers/segments/{id} [put]
+func (ctrl *ControllerSegment) Edit(c *gin.Context) {
+	id, err := helper.Uint(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	var segment domain.CustomerSegment
+	if err := c.ShouldBindJSON(&segment); err != nil {
+		BadResponse(c, "Bad Request (Body)", http.StatusBadRequest)
+		return
+	}
+	segment.ID = id
+	if err := ctrl.service.Edit(&segment); err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+	GoodResponseWithData(c, "segment updated", http.StatusOK, segment)
+}
+
+// @Summary Delete a segment
+// @Description Delete a segment that no promotion is restricted to
+// @Tags Customer
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Segment ID"
+// @Success 200 {object} handler.Response "segment deleted"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Router  /customers/segments/{id} [delete]
+func (ctrl *ControllerSegment) Delete(c *gin.Context) {
+	id, err := helper.Uint(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	if err := ctrl.service.Delete(id); err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+	GoodResponseWithData(c, "segment deleted", http.StatusOK, nil)
+}
+
+// @Summary Segment members
+// @Description Get the customers matching the segment rule with their latest RFM values
+// @Tags Customer
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Segment ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Number of items per page" default(10)
+// @Success 200 {object} handler.Response{data=[]domain.SegmentMember} "members retrieved"
+// @Failure 404 {object} handler.Response "no data found"
+// @Router  /customers/segments/{id}/members [get]
+func (ctrl *ControllerSegment) GetMembers(c *gin.Context) {
+	id, err := helper.Uint(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	page, _ := helper.Uint(c.Query("page"))
+	if page == 0 {
+		page = 1
+	}
+	limit, _ := helper.Uint(c.Query("limit"))
+	if limit == 0 {
+		limit = 10
+	}
+
+	total, pages, members, err := ctrl.service.GetMembers(id, page, limit)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusNotFound)
+		return
+	}
+	GoodResponseWithPage(c, "members retrieved", http.StatusOK, total, pages, int(page), int(limit), members)
+}
@@ -83,7 +83,7 @@ func (dh *dashboardHandler) GetBestSeller(c *gin.Context) {
 }
 
 // @Summary Get monthly revenue
-// @Description Retrieves the monthly revenue
+// @Description Retrieves the monthly net revenue, with paid refunds deducted in the month they were paid
 // @Tags Dashboard
 // @Accept json
 // @Produce json
@@ -1,7 +1,10 @@
 package handler
 
 import (
+	"errors"
+	"net/http"
 	"project/domain"
+	"project/imaging"
 	"project/service"
 
 	"github.com/gin-gonic/gin"
@@ -19,6 +22,21 @@ type Handler struct {
 	Stock                ControllerStock
 	Promotion            ControllerPromotion
 	Banner               ControllerBanner
+	Supplier             ControllerSupplier
+	PurchaseOrder        ControllerPurchaseOrder
+	Shipment             ControllerShipment
+	Payment              ControllerPayment
+	Refund               ControllerRefund
+	BulkOrder            ControllerBulkOrder
+	OrderNote            ControllerOrderNote
+	Customer             ControllerCustomer
+	Segment              ControllerSegment
+	Variant              ControllerProductVariant
+	Option               ControllerOption
+	Image                ControllerProductImage
+	Trash                ControllerTrash
+	Price                ControllerPrice
+	Review               ControllerReview
 }
 
 func NewHandler(service service.Service, logger *zap.Logger) *Handler {
@@ -32,10 +50,37 @@ func NewHandler(service service.Service, logger *zap.Logger) *Handler {
 		OrderHandler:         *NewOrderController(service.Order, logger),
 		Stock:                *NewServiceStock(service.Stock, logger),
 		Promotion:            *NewControllerPromotion(service.Promotion, logger),
-		Banner:               *NewControllerBanner(service.Banner, logger),
+		Banner:               *NewControllerBanner(service.Banner, service.Images, logger),
+		Supplier:             *NewControllerSupplier(service.Supplier, logger),
+		PurchaseOrder:        *NewControllerPurchaseOrder(service.PurchaseOrder, logger),
+		Shipment:             *NewControllerShipment(service.Shipment, logger),
+		Payment:              *NewControllerPayment(service.Payment, logger),
+		Refund:               *NewControllerRefund(service.Refund, logger),
+		BulkOrder:            *NewControllerBulkOrder(service.BulkOrder, logger),
+		OrderNote:            *NewControllerOrderNote(service.OrderNote, logger),
+		Customer:             *NewControllerCustomer(service.Customer, logger),
+		Segment:              *NewControllerSegment(service.Segment, logger),
+		Variant:              *NewControllerProductVariant(service.Variant, logger),
+		Option:               *NewControllerOption(service.Option, logger),
+		Image:                *NewControllerProductImage(service.Image, service.Images, logger),
+		Trash:                *NewControllerTrash(service.Trash, logger),
+		Price:                *NewControllerPrice(service.Price, logger),
+		Review:               *NewControllerReview(service.Review, logger),
 	}
 }
 
+// Keys the authentication middleware stores the token data under.
+const (
+	ContextUserID = "user_id"
+	ContextRole   = "role"
+)
+
+// isAdmin reports whether the request carries an admin token, the Identify or
+// Authentication middleware has to run first.
+func isAdmin(c *gin.Context) bool {
+	return c.GetString(ContextRole) == "admin"
+}
+
 type Response struct {
 	Status  bool   `json:"status"`
 	Message string `json:"message"`
@@ -68,3 +113,39 @@ func GoodResponseWithPage(c *gin.Context, message string, statusCode, total, tot
 		Data:        data,
 	})
 }
+
+func GoodResponseWithFilters(c *gin.Context, message string, statusCode, total, totalPages, page, Limit int, filters, data interface{}) {
+	c.JSON(statusCode, domain.DataPage{
+		Status:      true,
+		Message:     message,
+		Total:       int64(total),
+		Pages:       totalPages,
+		CurrentPage: uint(page),
+		Limit:       uint(Limit),
+		Filters:     filters,
+		Data:        data,
+	})
+}
+
+func GoodResponseWithFacets(c *gin.Context, message string, statusCode, total, totalPages, page, Limit int, filters, facets, data interface{}) {
+	c.JSON(statusCode, domain.DataPage{
+		Status:      true,
+		Message:     message,
+		Total:       int64(total),
+		Pages:       totalPages,
+		CurrentPage: uint(page),
+		Limit:       uint(Limit),
+		Filters:     filters,
+		Facets:      facets,
+		Data:        data,
+	})
+}
+
+// uploadStatus tells a rejected image, which is the client's fault, from a
+// failing storage.
+func uploadStatus(err error) int {
+	if errors.Is(err, imaging.ErrInvalidImage) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"net/http"
+	"project/domain"
+	"project/helper"
+	"project/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type ControllerOption struct {
+	service service.ServiceOption
+	logger  *zap.Logger
+}
+
+func NewControllerOption(service service.ServiceOption, logger *zap.Logger) *ControllerOption {
+	return &ControllerOption{service: service, logger: logger}
+}
+
+// @Summary Category option types
+// @Description Get the variant option types available to the products of a category, including the global ones and those of its parent categories
+// @Tags Category
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Category ID"
+// @Success 200 {object} handler.Response{data=[]domain.OptionType} "option types retrieved"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Failure 404 {object} handler.Response "category not found"
+// @Router  /category/{id}/options [get]
+func (ctrl *ControllerOption) GetByCategory(c *gin.Context) {
+	id, err := helper.Uint(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	optionTypes, err := ctrl.service.GetByCategory(id)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusNotFound)
+		return
+	}
+	GoodResponseWithData(c, "option types retrieved", http.StatusOK, optionTypes)
+}
+
+// @Summary Create an option type
+// @Description Create a variant option type like weight or breed with its values for a category
+// @Tags Category
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Category ID"
+// @Param option body domain.NewOptionType true "Name and values"
+// @Success 201 {object} handler.Response{data=domain.OptionType} "option type created"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Router  /category/{id}/options [post]
+func (ctrl *ControllerOption) Create(c *gin.Context) {
+	id, err := helper.Uint(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	var newType domain.NewOptionType
+	if err := c.ShouldBindJSON(&newType); err != nil {
+		BadResponse(c, "Bad Request (Body)", http.StatusBadRequest)
+		return
+	}
+	optionType, err := ctrl.service.Create(id, newType)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+	GoodResponseWithData(c, "option type created", http.StatusCreated, optionType)
+}
+
+// @Summary Add an option value
+// @Description Add a value to a variant option type
+// @Tags Category
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Option type ID"
+// @Param value body domain.NewOptionValue true "Value"
+// @Success 201 {object} handler.Response{data=domain.OptionValue} "option value created"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Router  /options/{id}/values [post]
+func (ctrl *ControllerOption) AddValue(c *gin.Context) {
+	id, err := helper.Uint(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	var newValue domain.NewOptionValue
+	if err := c.ShouldBindJSON(&newValue); err != nil {
+		BadResponse(c, "Bad Request (Body)", http.StatusBadRequest)
+		return
+	}
+	value, err := ctrl.service.AddValue(id, newValue)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+	GoodResponseWithData(c, "option value created", http.StatusCreated, value)
+}
+
+// @Summary Delete an option type
+// @Description Delete a variant option type with its values when no variant uses them
+// @Tags Category
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Option type ID"
+// @Success 200 {object} handler.Response "option type deleted"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Router  /options/{id} [delete]
+func (ctrl *ControllerOption) Delete(c *gin.Context) {
+	id, err := helper.Uint(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	if err := ctrl.service.Delete(id); err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+	GoodResponseWithData(c, "option type deleted", http.StatusOK, nil)
+}
+
+// @Summary Delete an option value
+// @Description Delete a variant option value when no variant uses it
+// @Tags Category
+// @Accept  json
+// @Produce  json
+// @Param valueId path int true "Option value ID"
+// @Success 200 {object} handler.Response "option value deleted"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Router  /options/values/{valueId} [delete]
+func (ctrl *ControllerOption) DeleteValue(c *gin.Context) {
+	id, err := helper.Uint(c.Param("valueId"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	if err := ctrl.service.DeleteValue(id); err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+	GoodResponseWithData(c, "option value deleted", http.StatusOK, nil)
+}
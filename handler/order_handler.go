@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 	"net/http"
@@ -20,12 +22,25 @@ func NewOrderController(service service.OrderService, logger *zap.Logger) *Order
 
 // Order endpoint
 // @Summary Customer orders
-// @Description Get customer orders
+// @Description Get customer orders filtered, searched and sorted by the query parameters
 // @Tags Order
 // @Accept  json
 // @Produce  json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Number of items per page" default(10)
+// @Param status query string false "created, processed, canceled, completed or delivered"
+// @Param payment_method query string false "Payment method"
+// @Param customer_id query int false "Customer ID"
+// @Param customer_name query string false "Part of the customer name"
+// @Param date_from query string false "Created on or after (yyyy-mm-dd)"
+// @Param date_to query string false "Created on or before (yyyy-mm-dd)"
+// @Param min_total query number false "Minimum order total"
+// @Param max_total query number false "Maximum order total"
+// @Param search query string false "Search customer name, address and tracking number"
+// @Param sort_by query string false "created_at or total" default(created_at)
+// @Param sort_order query string false "asc or desc" default(desc)
 // @Success 200 {object} handler.Response "orders retrieved"
-// @Failure 404 {object} handler.Response "no data found"
+// @Failure 400 {object} handler.Response "invalid filter"
 // @Failure 500 {object} handler.Response "server error"
 // @Router  /orders [get]
 func (ctrl *OrderController) All(c *gin.Context) {
@@ -38,13 +53,49 @@ func (ctrl *OrderController) All(c *gin.Context) {
 		limit = 10
 	}
 
-	total, pages, orders, err := ctrl.service.All(page, limit)
+	var filter domain.OrderFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		BadResponse(c, "invalid filter", http.StatusBadRequest)
+		return
+	}
+	if err := filter.Validate(); err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	total, pages, orders, err := ctrl.service.All(page, limit, filter)
 	if err != nil {
 		BadResponse(c, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	GoodResponseWithPage(c, "orders retrieved", http.StatusOK, total, pages, int(page), int(limit), orders)
+	GoodResponseWithFilters(c, "orders retrieved", http.StatusOK, total, pages, int(page), int(limit), filter, orders)
+}
+
+// Order endpoint
+// @Summary Create order
+// @Description Create a customer order with the current product prices, validated stock and an optional voucher code
+// @Tags Order
+// @Accept  json
+// @Produce  json
+// @Param order body domain.NewOrder true "Customer, payment method, voucher code and variant lines"
+// @Success 201 {object} handler.Response{data=domain.OrderTotal} "order created"
+// @Failure 400 {object} handler.Response "invalid input"
+// @Router  /orders [post]
+func (ctrl *OrderController) Create(c *gin.Context) {
+	var newOrder domain.NewOrder
+	if err := c.ShouldBindJSON(&newOrder); err != nil {
+		BadResponse(c, "invalid input", http.StatusBadRequest)
+		return
+	}
+
+	order, err := ctrl.service.Create(newOrder)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	GoodResponseWithData(c, "order created", http.StatusCreated, order)
 }
 
 // Order endpoint
@@ -82,7 +133,7 @@ func (ctrl *OrderController) Update(c *gin.Context) {
 
 // Order endpoint
 // @Summary Customer order
-// @Description Get customer order
+// @Description Get customer order with its items and notes, internal notes are only shown to admins
 // @Tags Order
 // @Accept  json
 // @Produce  json
@@ -105,6 +156,92 @@ func (ctrl *OrderController) Get(c *gin.Context) {
 		BadResponse(c, "no data found", http.StatusNotFound)
 		return
 	}
+	if !isAdmin(c) {
+		order.HideInternalNotes()
+	}
 
 	GoodResponseWithData(c, "order retrieved", http.StatusOK, order)
 }
+
+// Order endpoint
+// @Summary Order invoice
+// @Description Render the invoice of an order as PDF, assigning the next invoice number on first print
+// @Tags Order
+// @Produce  application/pdf
+// @Param id path uint true "Order ID"
+// @Success 200 {file} file "invoice"
+// @Failure 422 {object} handler.Response "invalid input"
+// @Failure 404 {object} handler.Response "no data found"
+// @Failure 409 {object} handler.Response "order not processed yet"
+// @Router  /orders/{id}/invoice.pdf [get]
+func (ctrl *OrderController) Invoice(c *gin.Context) {
+	ctrl.document(c, domain.Invoice)
+}
+
+// Order endpoint
+// @Summary Order packing slip
+// @Description Render the packing slip of an order as PDF
+// @Tags Order
+// @Produce  application/pdf
+// @Param id path uint true "Order ID"
+// @Success 200 {file} file "packing slip"
+// @Failure 422 {object} handler.Response "invalid input"
+// @Failure 404 {object} handler.Response "no data found"
+// @Router  /orders/{id}/packing-slip.pdf [get]
+func (ctrl *OrderController) PackingSlip(c *gin.Context) {
+	ctrl.document(c, domain.PackingSlip)
+}
+
+// Order endpoint
+// @Summary Bulk order documents
+// @Description Render invoices or packing slips of several orders into a single PDF, one page per order
+// @Tags Order
+// @Accept  json
+// @Produce  application/pdf
+// @Param request body domain.OrderDocumentRequest true "Order IDs and document type"
+// @Success 200 {file} file "documents"
+// @Failure 400 {object} handler.Response "invalid input"
+// @Failure 404 {object} handler.Response "no data found"
+// @Failure 409 {object} handler.Response "order not processed yet"
+// @Router  /orders/documents [post]
+func (ctrl *OrderController) Documents(c *gin.Context) {
+	var request domain.OrderDocumentRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		BadResponse(c, "invalid input", http.StatusBadRequest)
+		return
+	}
+
+	pdf, err := ctrl.service.Documents(request.Document, request.OrderIDs)
+	if errors.Is(err, domain.ErrNotInvoiceable) {
+		BadResponse(c, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%ss.pdf", request.Document))
+	c.Data(http.StatusOK, "application/pdf", pdf)
+}
+
+func (ctrl *OrderController) document(c *gin.Context, document domain.OrderDocument) {
+	orderId, err := helper.Uint(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "invalid input", http.StatusUnprocessableEntity)
+		return
+	}
+
+	pdf, err := ctrl.service.Documents(document, []uint{orderId})
+	if errors.Is(err, domain.ErrNotInvoiceable) {
+		BadResponse(c, domain.ErrNotInvoiceable.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		BadResponse(c, "no data found", http.StatusNotFound)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%s-%d.pdf", document, orderId))
+	c.Data(http.StatusOK, "application/pdf", pdf)
+}
@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"project/domain"
+	"project/helper"
+	"project/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type ControllerOrderNote struct {
+	service service.ServiceOrderNote
+	logger  *zap.Logger
+}
+
+func NewControllerOrderNote(service service.ServiceOrderNote, logger *zap.Logger) *ControllerOrderNote {
+	return &ControllerOrderNote{service: service, logger: logger}
+}
+
+// @Summary Order notes
+// @Description Get the notes of an order, optionally only the internal or the customer-visible ones. Only admins see internal notes
+// @Tags Order
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Order ID"
+// @Param visibility query string false "internal or customer"
+// @Success 200 {object} handler.Response{data=[]domain.OrderNote} "notes retrieved"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Failure 404 {object} handler.Response "order not found"
+// @Router  /orders/{id}/notes [get]
+// @Security token
+func (ctrl *ControllerOrderNote) GetByOrder(c *gin.Context) {
+	orderId, err := helper.Uint(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	visibility := domain.NoteVisibility(c.Query("visibility"))
+	if visibility != "" && !visibility.Valid() {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	if !isAdmin(c) {
+		visibility = domain.NoteCustomer
+	}
+
+	notes, err := ctrl.service.GetByOrder(orderId, visibility)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusNotFound)
+		return
+	}
+	GoodResponseWithData(c, "notes retrieved", http.StatusOK, notes)
+}
+
+// @Summary Add an order note
+// @Description Add an internal or customer-visible note to an order, written by the logged in user
+// @Tags Order
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Order ID"
+// @Param note body domain.NewOrderNote true "Note content and visibility (internal by default)"
+// @Success 201 {object} handler.Response{data=domain.OrderNote} "note created"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Router  /orders/{id}/notes [post]
+// @Security token
+func (ctrl *ControllerOrderNote) Create(c *gin.Context) {
+	orderId, err := helper.Uint(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	var newNote domain.NewOrderNote
+	if err := c.ShouldBindJSON(&newNote); err != nil {
+		BadResponse(c, "Bad Request (Body)", http.StatusBadRequest)
+		return
+	}
+	note, err := ctrl.service.Create(orderId, c.GetUint(ContextUserID), newNote)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+	GoodResponseWithData(c, "note created", http.StatusCreated, note)
+}
+
+// @Summary Edit an order note
+// @Description Edit a note, only its author can do this
+// @Tags Order
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Order ID"
+// @Param noteId path int true "Note ID"
+// @Param note body domain.NewOrderNote true "Note content and visibility"
+// @Success 200 {object} handler.Response{data=domain.OrderNote} "note updated"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Failure 403 {object} handler.Response "not the author"
+// @Router  /orders/{id}/notes/{noteId} [put]
+// @Security token
+func (ctrl *ControllerOrderNote) Edit(c *gin.Context) {
+	orderId, err := helper.Uint(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	noteId, err := helper.Uint(c.Param("noteId"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	var edit domain.NewOrderNote
+	if err := c.ShouldBindJSON(&edit); err != nil {
+		BadResponse(c, "Bad Request (Body)", http.StatusBadRequest)
+		return
+	}
+	note, err := ctrl.service.Edit(orderId, noteId, c.GetUint(ContextUserID), edit)
+	if errors.Is(err, domain.ErrNotNoteAuthor) {
+		BadResponse(c, err.Error(), http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+	GoodResponseWithData(c, "note updated", http.StatusOK, note)
+}
+
+// @Summary Delete an order note
+// @Description Delete a note, only its author can do this
+// @Tags Order
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Order ID"
+// @Param noteId path int true "Note ID"
+// @Success 200 {object} handler.Response "note deleted"
+// @Failure 403 {object} handler.Response "not the author"
+// @Failure 404 {object} handler.Response "note not found"
+// @Router  /orders/{id}/notes/{noteId} [delete]
+// @Security token
+func (ctrl *ControllerOrderNote) Delete(c *gin.Context) {
+	orderId, err := helper.Uint(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	noteId, err := helper.Uint(c.Param("noteId"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	err = ctrl.service.Delete(orderId, noteId, c.GetUint(ContextUserID))
+	if errors.Is(err, domain.ErrNotNoteAuthor) {
+		BadResponse(c, err.Error(), http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusNotFound)
+		return
+	}
+	GoodResponseWithData(c, "note deleted", http.StatusOK, nil)
+}
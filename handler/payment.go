@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"project/domain"
+	"project/helper"
+	"project/payment"
+	"project/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type ControllerPayment struct {
+	service service.ServicePayment
+	logger  *zap.Logger
+}
+
+func NewControllerPayment(service service.ServicePayment, logger *zap.Logger) *ControllerPayment {
+	return &ControllerPayment{service: service, logger: logger}
+}
+
+// @Summary Order payments
+// @Description Get the payments recorded for an order
+// @Tags Payment
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Order ID"
+// @Success 200 {object} handler.Response{data=[]domain.Payment} "payments retrieved"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Failure 404 {object} handler.Response "order not found"
+// @Router  /orders/{id}/payments [get]
+func (ctrl *ControllerPayment) GetByOrder(c *gin.Context) {
+	id, err := helper.Uint(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	payments, err := ctrl.service.GetByOrder(id)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusNotFound)
+		return
+	}
+	GoodResponseWithData(c, "payments retrieved", http.StatusOK, payments)
+}
+
+// @Summary Create a payment
+// @Description Create a pending payment for the outstanding amount of an order with a payment provider
+// @Tags Payment
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Order ID"
+// @Param payment body domain.NewPayment true "Payment provider and method"
+// @Success 201 {object} handler.Response{data=domain.Payment} "payment created"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Router  /orders/{id}/payments [post]
+func (ctrl *ControllerPayment) Create(c *gin.Context) {
+	id, err := helper.Uint(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	var newPayment domain.NewPayment
+	if err := c.ShouldBindJSON(&newPayment); err != nil {
+		BadResponse(c, "Bad Request (Body)", http.StatusBadRequest)
+		return
+	}
+	record, err := ctrl.service.Create(id, newPayment)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+	GoodResponseWithData(c, "payment created", http.StatusCreated, record)
+}
+
+// @Summary Payment webhook
+// @Description Notification from a payment provider, signed with HMAC-SHA256 in the X-Signature header
+// @Tags Payment
+// @Accept  json
+// @Produce  json
+// @Param provider path string true "Payment provider"
+// @Param X-Signature header string true "Hex encoded HMAC-SHA256 of the body"
+// @Success 200 {object} handler.Response{data=domain.Payment} "payment updated"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Failure 401 {object} handler.Response "invalid signature"
+// @Router  /payments/webhook/{provider} [post]
+func (ctrl *ControllerPayment) Webhook(c *gin.Context) {
+	payload, err := c.GetRawData()
+	if err != nil {
+		BadResponse(c, "Bad Request (Body)", http.StatusBadRequest)
+		return
+	}
+	record, err := ctrl.service.HandleWebhook(c.Param("provider"), payload, c.GetHeader("X-Signature"))
+	if errors.Is(err, payment.ErrInvalidSignature) {
+		ctrl.logger.Warn("Rejected payment webhook", zap.String("provider", c.Param("provider")), zap.String("ip", c.ClientIP()))
+		BadResponse(c, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+	GoodResponseWithData(c, "payment updated", http.StatusOK, record)
+}
+
+// @Summary Payment reconciliation
+// @Description List orders whose paid amount differs from the order total
+// @Tags Payment
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} handler.Response{data=[]domain.PaymentMismatch} "payments reconciled"
+// @Failure 500 {object} handler.Response "server error"
+// @Router  /payments/reconciliation [get]
+func (ctrl *ControllerPayment) Reconciliation(c *gin.Context) {
+	mismatches, err := ctrl.service.Reconcile()
+	if err != nil {
+		BadResponse(c, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	GoodResponseWithData(c, "payments reconciled", http.StatusOK, mismatches)
+}
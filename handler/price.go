@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"net/http"
+	"project/domain"
+	"project/helper"
+	"project/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type ControllerPrice struct {
+	service service.ServicePrice
+	logger  *zap.Logger
+}
+
+func NewControllerPrice(service service.ServicePrice, logger *zap.Logger) *ControllerPrice {
+	return &ControllerPrice{service: service, logger: logger}
+}
+
+// @Summary Product price history
+// @Description Get every price and sale change of a product, the latest first
+// @Tags Products
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Product ID"
+// @Success 200 {object} handler.Response{data=[]domain.PriceHistory} "price history retrieved"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Failure 404 {object} handler.Response "product not found"
+// @Router  /products/{id}/price-history [get]
+func (ctrl *ControllerPrice) GetHistory(c *gin.Context) {
+	productId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	history, err := ctrl.service.GetHistory(productId)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusNotFound)
+		return
+	}
+	GoodResponseWithData(c, "price history retrieved", http.StatusOK, history)
+}
+
+// @Summary Scheduled price changes
+// @Description Get the price changes of a product that are still to come
+// @Tags Products
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Product ID"
+// @Success 200 {object} handler.Response{data=[]domain.ScheduledPrice} "scheduled prices retrieved"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Failure 404 {object} handler.Response "product not found"
+// @Router  /products/{id}/price-schedule [get]
+func (ctrl *ControllerPrice) GetScheduled(c *gin.Context) {
+	productId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	scheduled, err := ctrl.service.GetScheduled(productId)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusNotFound)
+		return
+	}
+	GoodResponseWithData(c, "scheduled prices retrieved", http.StatusOK, scheduled)
+}
+
+// @Summary Schedule a price change
+// @Description Change the price of a product at a future time
+// @Tags Products
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Product ID"
+// @Param price body domain.ScheduledPrice true "New price and when it takes effect"
+// @Success 201 {object} handler.Response{data=domain.ScheduledPrice} "price change scheduled"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Router  /products/{id}/price-schedule [post]
+func (ctrl *ControllerPrice) Schedule(c *gin.Context) {
+	productId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	var scheduled domain.ScheduledPrice
+	if err := c.ShouldBindJSON(&scheduled); err != nil {
+		BadResponse(c, "Bad Request (Body)", http.StatusBadRequest)
+		return
+	}
+	scheduled, err = ctrl.service.Schedule(productId, scheduled)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+	GoodResponseWithData(c, "price change scheduled", http.StatusCreated, scheduled)
+}
+
+// @Summary Cancel a scheduled price change
+// @Description Cancel a price change of a product that hasn't taken effect yet
+// @Tags Products
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Product ID"
+// @Param scheduleId path int true "Scheduled price ID"
+// @Success 200 {object} handler.Response "price change cancelled"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Failure 404 {object} handler.Response "scheduled price not found"
+// @Router  /products/{id}/price-schedule/{scheduleId} [delete]
+func (ctrl *ControllerPrice) CancelScheduled(c *gin.Context) {
+	productId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	id, err := helper.Uint(c.Param("scheduleId"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	if err := ctrl.service.CancelScheduled(productId, id); err != nil {
+		BadResponse(c, err.Error(), http.StatusNotFound)
+		return
+	}
+	GoodResponseWithData(c, "price change cancelled", http.StatusOK, id)
+}
+
+// @Summary Set the sale of a product
+// @Description Put a product on sale between an optional start and end, without a sale price the sale ends
+// @Tags Products
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Product ID"
+// @Param sale body domain.ProductSale true "Sale price and window"
+// @Success 200 {object} handler.Response{data=domain.Product} "sale updated"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Router  /products/{id}/sale [put]
+func (ctrl *ControllerPrice) SetSale(c *gin.Context) {
+	productId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	var sale domain.ProductSale
+	if err := c.ShouldBindJSON(&sale); err != nil {
+		BadResponse(c, "Bad Request (Body)", http.StatusBadRequest)
+		return
+	}
+	product, err := ctrl.service.SetSale(productId, sale)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+	GoodResponseWithData(c, "sale updated", http.StatusOK, product)
+}
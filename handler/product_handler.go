@@ -7,7 +7,7 @@ import (
 	"project/helper"
 	"project/service"
 	"strconv"
-	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -15,10 +15,15 @@ import (
 
 type ProductHandler interface {
 	ShowAllProduct(c *gin.Context)
+	SearchProducts(c *gin.Context)
 	GetProductByID(c *gin.Context)
 	CreateProduct(c *gin.Context)
 	DeleteProduct(c *gin.Context)
 	UpdateProduct(c *gin.Context)
+	AssignCategory(c *gin.Context)
+	UnassignCategory(c *gin.Context)
+	ShowAdminProducts(c *gin.Context)
+	ChangeStatus(c *gin.Context)
 }
 
 type productHandler struct {
@@ -68,6 +73,62 @@ func (ph *productHandler) ShowAllProduct(c *gin.Context) {
 	GoodResponseWithPage(c, "Successfully Retrieved Products", http.StatusOK, count, totalPages, page, limit, products)
 }
 
+// @Summary Search products
+// @Description Full-text search on product name and description with price, category and variant filters, facet counts and sorting
+// @Tags Product
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Number of items per page" default(10)
+// @Param q query string false "Search text"
+// @Param category_id query int false "Category ID"
+// @Param min_price query number false "Minimum price"
+// @Param max_price query number false "Maximum price"
+// @Param size query string false "Variant size"
+// @Param color query string false "Variant color"
+// @Param in_stock query bool false "Only products with a variant in stock"
+// @Param attr[name] query string false "Variant option value by option type name, e.g. attr[breed]=Angus"
+// @Param sort_by query string false "relevance, price, name, newest or best_selling"
+// @Param sort_order query string false "asc or desc"
+// @Success 200 {object} domain.DataPage{data=[]domain.Product,filters=domain.ProductSearch,facets=domain.ProductFacets} "Successfully searched products"
+// @Failure 400 {object} handler.Response "Invalid search"
+// @Failure 500 {object} handler.Response "Internal server error"
+// @Router /products/search [get]
+func (ph *productHandler) SearchProducts(c *gin.Context) {
+	page, _ := strconv.Atoi(c.Query("page"))
+	if page <= 0 {
+		page = 1
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	if limit < 10 {
+		limit = 10
+	}
+
+	var search domain.ProductSearch
+	if err := c.ShouldBindQuery(&search); err != nil {
+		ph.log.Error("Invalid search query", zap.Error(err))
+		BadResponse(c, "invalid search", http.StatusBadRequest)
+		return
+	}
+	if attributes := c.QueryMap("attr"); len(attributes) > 0 {
+		search.Attributes = attributes
+	}
+	if err := search.Validate(); err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	products, facets, count, totalPages, err := ph.service.Product.SearchProducts(page, limit, search)
+	if err != nil {
+		ph.log.Error("Failed to search products", zap.Error(err))
+		BadResponse(c, "Failed to search products", http.StatusInternalServerError)
+		return
+	}
+
+	GoodResponseWithFacets(c, "Successfully Searched Products", http.StatusOK, count, totalPages, page, limit, search, facets, products)
+}
+
 // Get Product By ID
 // @Summary Get Product By ID
 // @Description Get Product By ID
@@ -103,6 +164,9 @@ func (ph *productHandler) GetProductByID(c *gin.Context) {
 // @Param sku_product formData string true "Product SKU"
 // @Param price formData int true "Product Price"
 // @Param description formData string true "Product Description"
+// @Param category_id formData int false "Primary Category ID"
+// @Param status formData string false "Product Status" Enums(draft, active, scheduled) default(draft)
+// @Param publish_at formData string false "Publish time for scheduled products (RFC3339)"
 // @Param images formData file true "Product Images" multiple
 // @Param variants formData string true "Product Variants in JSON format"
 // @Success 201 {object} handler.Response{data=domain.Product} "Product created successfully"
@@ -124,11 +188,10 @@ func (ph *productHandler) CreateProduct(c *gin.Context) {
 		ph.log.Info("Processing uploaded file", zap.String("fileName", file.Filename), zap.Int64("fileSize", file.Size))
 	}
 
-	var wg sync.WaitGroup
-	responses, err := helper.Upload(&wg, files)
+	uploads, err := ph.service.Images.UploadAll(c.Request.Context(), files)
 	if err != nil {
 		ph.log.Error("Failed to upload images", zap.Error(err))
-		BadResponse(c, "Failed to upload images: "+err.Error(), http.StatusInternalServerError)
+		BadResponse(c, "Failed to upload images: "+err.Error(), uploadStatus(err))
 		return
 	}
 
@@ -141,12 +204,26 @@ func (ph *productHandler) CreateProduct(c *gin.Context) {
 		return
 	}
 
+	var categoryID *uint
+	if value := c.PostForm("category_id"); value != "" {
+		id, err := helper.Uint(value)
+		if err != nil {
+			ph.log.Error("Invalid category value", zap.String("category_id", value), zap.Error(err))
+			BadResponse(c, "Invalid category value", http.StatusBadRequest)
+			return
+		}
+		categoryID = &id
+	}
+
 	ph.log.Info("Parsed product data", zap.String("name", name), zap.String("skuProduct", skuProduct), zap.Int("price", price))
 
 	var images []*domain.Image
-	for _, response := range responses {
+	for _, upload := range uploads {
 		images = append(images, &domain.Image{
-			URLPath: response.Data.Url,
+			URLPath:          upload.URL,
+			FileID:           upload.Key,
+			Renditions:       upload.Renditions,
+			RenditionFileIDs: upload.RenditionKeys,
 		})
 	}
 
@@ -166,10 +243,31 @@ func (ph *productHandler) CreateProduct(c *gin.Context) {
 		SKUProduct:     skuProduct,
 		Price:          float64(price),
 		Description:    c.PostForm("description"),
+		CategoryID:     categoryID,
 		Image:          images,
 		ProductVariant: productVariants,
 	}
 
+	lifecycle := domain.ProductStatusChange{Status: domain.ProductStatus(c.DefaultPostForm("status", string(domain.ProductDraft)))}
+	if value := c.PostForm("publish_at"); value != "" {
+		publishAt, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			ph.log.Error("Invalid publish_at value", zap.String("publish_at", value), zap.Error(err))
+			BadResponse(c, "Invalid publish_at value", http.StatusBadRequest)
+			return
+		}
+		lifecycle.PublishAt = &publishAt
+	}
+	if lifecycle.Status == domain.ProductArchived {
+		BadResponse(c, "Invalid status value", http.StatusBadRequest)
+		return
+	}
+	if err := lifecycle.Apply(&product, time.Now()); err != nil {
+		ph.log.Error("Invalid product status", zap.String("status", string(lifecycle.Status)), zap.Error(err))
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	ph.log.Info("Creating product", zap.String("productName", product.Name), zap.Float64("price", product.Price))
 
 	if err := ph.service.Product.CreateProduct(&product); err != nil {
@@ -243,3 +341,138 @@ func (ph *productHandler) UpdateProduct(c *gin.Context) {
 	ph.log.Info("Product updated successfully", zap.Int("productID", id), zap.String("productName", product.Name))
 	GoodResponseWithData(c, "Product Updated successfully", http.StatusOK, product)
 }
+
+// Assign Category
+// @Summary Assign a category to a product
+// @Description Link a product to a category, optionally making it the primary category
+// @Tags Products
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Product ID"
+// @Param assignment body domain.CategoryAssignment true "Category and whether it becomes the primary one"
+// @Success 200 {object} handler.Response "Category assigned successfully"
+// @Failure 400 {object} handler.Response "Failed to assign category"
+// @Router /products/{id}/categories [post]
+func (ph *productHandler) AssignCategory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+
+	var assignment domain.CategoryAssignment
+	if err := c.ShouldBindJSON(&assignment); err != nil {
+		BadResponse(c, "Bad Request (Body)", http.StatusBadRequest)
+		return
+	}
+
+	if err := ph.service.Product.AssignCategory(id, assignment); err != nil {
+		ph.log.Error("Failed to assign category", zap.Int("productID", id), zap.Error(err))
+		BadResponse(c, "Failed to assign category: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	GoodResponseWithData(c, "Category assigned successfully", http.StatusOK, assignment)
+}
+
+// Unassign Category
+// @Summary Unassign a category from a product
+// @Description Unlink a product from a category, the next linked category becomes primary when needed
+// @Tags Products
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Product ID"
+// @Param categoryId path int true "Category ID"
+// @Success 200 {object} handler.Response "Category unassigned successfully"
+// @Failure 400 {object} handler.Response "Failed to unassign category"
+// @Router /products/{id}/categories/{categoryId} [delete]
+func (ph *productHandler) UnassignCategory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	categoryID, err := helper.Uint(c.Param("categoryId"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+
+	if err := ph.service.Product.UnassignCategory(id, categoryID); err != nil {
+		ph.log.Error("Failed to unassign category", zap.Int("productID", id), zap.Error(err))
+		BadResponse(c, "Failed to unassign category: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	GoodResponseWithData(c, "Category unassigned successfully", http.StatusOK, categoryID)
+}
+
+// @Summary Get products for the admin
+// @Description Fetches a paginated list of products in every status, optionally filtered by one
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Number of items per page" default(10)
+// @Param status query string false "Product status" Enums(draft, active, scheduled, archived)
+// @Success 200 {object} handler.Response{data=[]domain.Product} "Successfully retrieved products"
+// @Failure 400 {object} handler.Response "Invalid status"
+// @Failure 500 {object} handler.Response "Internal server error"
+// @Router /products/admin [get]
+func (ph *productHandler) ShowAdminProducts(c *gin.Context) {
+	page, _ := strconv.Atoi(c.Query("page"))
+	if page <= 0 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	if limit <= 0 {
+		limit = 10
+	}
+
+	status := domain.ProductStatus(c.Query("status"))
+	if status != "" && !status.Valid() {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+
+	products, count, totalPages, err := ph.service.Product.ShowAdminProducts(page, limit, status)
+	if err != nil {
+		ph.log.Error("Failed to fetch admin products", zap.Error(err))
+		BadResponse(c, "Failed to fetch products", http.StatusInternalServerError)
+		return
+	}
+
+	GoodResponseWithPage(c, "Successfully Retrieved Products", http.StatusOK, count, totalPages, page, limit, products)
+}
+
+// @Summary Change product status
+// @Description Moves a product to draft, active, scheduled or archived, scheduled products need a future publish_at
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param status body domain.ProductStatusChange true "New status"
+// @Success 200 {object} handler.Response{data=domain.Product} "Product status changed successfully"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Router /products/{id}/status [put]
+func (ph *productHandler) ChangeStatus(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+
+	var change domain.ProductStatusChange
+	if err := c.ShouldBindJSON(&change); err != nil {
+		BadResponse(c, "Bad Request (Body)", http.StatusBadRequest)
+		return
+	}
+
+	product, err := ph.service.Product.ChangeStatus(id, change)
+	if err != nil {
+		BadResponse(c, "Failed to change product status: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	GoodResponseWithData(c, "Product status changed successfully", http.StatusOK, product)
+}
@@ -4,13 +4,18 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"image"
+	"image/color"
+	"image/png"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"project/domain"
 	"project/handler"
-	productrepository "project/repository/product_repository"
+	"project/imaging"
 	"project/service"
+	productservice "project/service/product_service"
+	"project/storage"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -19,23 +24,38 @@ import (
 	"go.uber.org/zap"
 )
 
-func base() (handler.ProductHandler, *productrepository.ProductRepoMock) {
+func base(t *testing.T) (handler.ProductHandler, *productservice.ProductServiceMock) {
 
 	log := *zap.NewNop()
 
-	mockService := &productrepository.ProductRepoMock{}
+	mockService := &productservice.ProductServiceMock{}
+
+	cdn := storage.NewFakeCDN()
+	t.Cleanup(cdn.Close)
 
 	service := service.Service{
 		Product: mockService,
+		Images:  imaging.NewPipeline(storage.NewCDN(cdn.URL, cdn.Client()), imaging.DefaultLimits),
 	}
 
 	return handler.NewProductHandler(&service, &log), mockService
 }
 
+// pngImage encodes a small image to upload.
+func pngImage(t *testing.T) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	for x := 0; x < 40; x++ {
+		img.Set(x, x%20, color.RGBA{R: 200, A: 255})
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
 func TestShowAllProduct(t *testing.T) {
 
 	t.Run("Successfully retrieve all products", func(t *testing.T) {
-		handler, mockService := base()
+		handler, mockService := base(t)
 		r := gin.Default()
 		r.GET("/products", handler.ShowAllProduct)
 
@@ -69,7 +89,7 @@ func TestShowAllProduct(t *testing.T) {
 
 	t.Run("Fail to retrieve all products due to service error", func(t *testing.T) {
 
-		handler, mockService := base()
+		handler, mockService := base(t)
 
 		r := gin.Default()
 		r.GET("/products", handler.ShowAllProduct)
@@ -89,10 +109,92 @@ func TestShowAllProduct(t *testing.T) {
 	})
 }
 
+func TestSearchProducts(t *testing.T) {
+
+	t.Run("Successfully search products with facets", func(t *testing.T) {
+		handler, mockService := base(t)
+		r := gin.Default()
+		r.GET("/products/search", handler.SearchProducts)
+
+		mockProducts := []domain.Product{{ID: 1, Name: "Kaos Polos"}}
+		facets := domain.ProductFacets{Size: []domain.Facet{{Value: "M", Count: 1}}}
+		search := domain.ProductSearch{Query: "kaos", Size: "M", SortBy: domain.SortRelevance, SortOrder: "desc"}
+
+		mockService.On("SearchProducts", 1, 10, search).Return(&mockProducts, &facets, 1, 1, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/products/search?q=kaos&size=M", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertCalled(t, "SearchProducts", 1, 10, search)
+
+		var actualResponse map[string]interface{}
+		err := json.Unmarshal(w.Body.Bytes(), &actualResponse)
+		assert.NoError(t, err)
+		assert.Equal(t, "Successfully Searched Products", actualResponse["message"])
+		assert.NotNil(t, actualResponse["facets"])
+	})
+
+	t.Run("Fail to search with an unknown sort", func(t *testing.T) {
+		handler, mockService := base(t)
+		r := gin.Default()
+		r.GET("/products/search", handler.SearchProducts)
+
+		req := httptest.NewRequest(http.MethodGet, "/products/search?sort_by=rating", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertNotCalled(t, "SearchProducts", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestAssignCategory(t *testing.T) {
+
+	t.Run("Successfully assign a primary category", func(t *testing.T) {
+		handler, mockService := base(t)
+		r := gin.Default()
+		r.POST("/products/:id/categories", handler.AssignCategory)
+
+		assignment := domain.CategoryAssignment{CategoryID: 2, Primary: true}
+		mockService.On("AssignCategory", 1, assignment).Return(nil)
+
+		req := httptest.NewRequest(http.MethodPost, "/products/1/categories", bytes.NewBufferString(`{"category_id":2,"primary":true}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertCalled(t, "AssignCategory", 1, assignment)
+	})
+
+	t.Run("Fail to assign an unknown category", func(t *testing.T) {
+		handler, mockService := base(t)
+		r := gin.Default()
+		r.POST("/products/:id/categories", handler.AssignCategory)
+
+		assignment := domain.CategoryAssignment{CategoryID: 99}
+		mockService.On("AssignCategory", 1, assignment).Return(fmt.Errorf("category not found"))
+
+		req := httptest.NewRequest(http.MethodPost, "/products/1/categories", bytes.NewBufferString(`{"category_id":99}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"message":"Failed to assign category: category not found", "status":false}`, w.Body.String())
+	})
+}
+
 func TestGetProductByID(t *testing.T) {
 
 	t.Run("Successfully retrieve product by ID", func(t *testing.T) {
-		handler, mockService := base()
+		handler, mockService := base(t)
 
 		r := gin.Default()
 		r.GET("/products/:id", handler.GetProductByID)
@@ -120,7 +222,7 @@ func TestGetProductByID(t *testing.T) {
 	})
 
 	t.Run("Fail to retrieve product by ID due to service error", func(t *testing.T) {
-		handler, mockService := base()
+		handler, mockService := base(t)
 
 		r := gin.Default()
 		r.GET("/products/:id", handler.GetProductByID)
@@ -143,7 +245,7 @@ func TestGetProductByID(t *testing.T) {
 func TestCreateProductWithImage(t *testing.T) {
 
 	t.Run("Successfully create product with image", func(t *testing.T) {
-		handler, mockService := base()
+		handler, mockService := base(t)
 
 		r := gin.Default()
 		r.POST("/products", handler.CreateProduct)
@@ -151,7 +253,7 @@ func TestCreateProductWithImage(t *testing.T) {
 		mockService.On("CreateProduct", mock.AnythingOfType("*domain.Product")).Return(nil)
 
 		// Create a test file in memory
-		testFile := []byte("dummy image content")
+		testFile := pngImage(t)
 		fileName := "testimage.jpg"
 
 		formData := new(bytes.Buffer)
@@ -186,7 +288,7 @@ func TestCreateProductWithImage(t *testing.T) {
 	})
 
 	t.Run("Fail to create product with image due to invalid price", func(t *testing.T) {
-		handler, mockService := base()
+		handler, mockService := base(t)
 
 		r := gin.Default()
 		r.POST("/products", handler.CreateProduct)
@@ -194,7 +296,7 @@ func TestCreateProductWithImage(t *testing.T) {
 		mockService.On("CreateProduct", mock.AnythingOfType("*domain.Product")).Return(fmt.Errorf("failed to create product"))
 
 		// Create a test file in memory
-		testFile := []byte("dummy image content")
+		testFile := pngImage(t)
 		fileName := "testimage.jpg"
 
 		formData := new(bytes.Buffer)
@@ -227,7 +329,7 @@ func TestCreateProductWithImage(t *testing.T) {
 	})
 
 	t.Run("Fail to create product with image due to service error", func(t *testing.T) {
-		handler, mockService := base()
+		handler, mockService := base(t)
 
 		r := gin.Default()
 		r.POST("/products", handler.CreateProduct)
@@ -235,7 +337,7 @@ func TestCreateProductWithImage(t *testing.T) {
 		mockService.On("CreateProduct", mock.AnythingOfType("*domain.Product")).Return(fmt.Errorf("failed to create product"))
 
 		// Create a test file in memory
-		testFile := []byte("dummy image content")
+		testFile := pngImage(t)
 		fileName := "testimage.jpg"
 
 		formData := new(bytes.Buffer)
@@ -266,12 +368,42 @@ func TestCreateProductWithImage(t *testing.T) {
 		expectedResponse := `{"message":"Failed to create product: failed to create product", "status":false}`
 		assert.JSONEq(t, expectedResponse, w.Body.String())
 	})
+
+	t.Run("Fail to create product with a file that isn't an image", func(t *testing.T) {
+		handler, mockService := base(t)
+
+		r := gin.Default()
+		r.POST("/products", handler.CreateProduct)
+
+		formData := new(bytes.Buffer)
+		writer := multipart.NewWriter(formData)
+		writer.WriteField("name", "Product 1")
+		writer.WriteField("sku_product", "SKU001")
+		writer.WriteField("price", "100")
+		writer.WriteField("variants", `[{"size":"L","color":"Red"}]`)
+
+		part, err := writer.CreateFormFile("images", "testimage.jpg")
+		assert.NoError(t, err)
+		_, err = part.Write([]byte("dummy image content"))
+		assert.NoError(t, err)
+
+		writer.Close()
+
+		req := httptest.NewRequest(http.MethodPost, "/products", formData)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertNotCalled(t, "CreateProduct", mock.AnythingOfType("*domain.Product"))
+	})
 }
 
 func TestDeleteProduct(t *testing.T) {
 
 	t.Run("Successfully delete product", func(t *testing.T) {
-		handler, mockService := base()
+		handler, mockService := base(t)
 
 		r := gin.Default()
 		r.DELETE("/products/:id", handler.DeleteProduct)
@@ -295,7 +427,7 @@ func TestDeleteProduct(t *testing.T) {
 	})
 
 	t.Run("Fail to delete product due to service error", func(t *testing.T) {
-		handler, mockService := base()
+		handler, mockService := base(t)
 
 		r := gin.Default()
 		r.DELETE("/products/:id", handler.DeleteProduct)
@@ -321,7 +453,7 @@ func TestDeleteProduct(t *testing.T) {
 
 func TestProductHandler_UpdateProduct(t *testing.T) {
 	t.Run("Successfully update a product", func(t *testing.T) {
-		handler, mockService := base()
+		handler, mockService := base(t)
 
 		productID := 1
 		product := domain.Product{
@@ -349,7 +481,7 @@ func TestProductHandler_UpdateProduct(t *testing.T) {
 	})
 
 	t.Run("Failed to update product - Invalid JSON", func(t *testing.T) {
-		handler, _ := base()
+		handler, _ := base(t)
 
 		req, _ := http.NewRequest(http.MethodPut, "/products/1", bytes.NewBufferString("invalid-json"))
 		req.Header.Set("Content-Type", "application/json")
@@ -365,7 +497,7 @@ func TestProductHandler_UpdateProduct(t *testing.T) {
 	})
 
 	t.Run("Failed to update product - Service error", func(t *testing.T) {
-		handler, mockService := base()
+		handler, mockService := base(t)
 
 		productID := 2
 		product := domain.Product{
@@ -0,0 +1,234 @@
+package handler
+
+import (
+	"net/http"
+	"project/domain"
+	"project/imaging"
+	"project/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type ControllerProductImage struct {
+	service service.ServiceProductImage
+	images  *imaging.Pipeline
+	logger  *zap.Logger
+}
+
+func NewControllerProductImage(service service.ServiceProductImage, images *imaging.Pipeline, logger *zap.Logger) *ControllerProductImage {
+	return &ControllerProductImage{service: service, images: images, logger: logger}
+}
+
+// @Summary Product images
+// @Description Get the images of a product in display order
+// @Tags Products
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Product ID"
+// @Success 200 {object} handler.Response{data=[]domain.Image} "images retrieved"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Failure 404 {object} handler.Response "product not found"
+// @Router  /products/{id}/images [get]
+func (ctrl *ControllerProductImage) GetByProduct(c *gin.Context) {
+	productId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	images, err := ctrl.service.GetByProduct(productId)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusNotFound)
+		return
+	}
+	GoodResponseWithData(c, "images retrieved", http.StatusOK, images)
+}
+
+// @Summary Upload product images
+// @Description Upload JPEG, PNG, GIF or WebP images and add them after the existing images of a product, the first image of a product becomes its primary image. Thumbnail, medium and large WebP renditions are generated for each image
+// @Tags Products
+// @Accept  multipart/form-data
+// @Produce  json
+// @Param id path int true "Product ID"
+// @Param images formData file true "Product Images" multiple
+// @Param alt_text formData string false "Alt text for the images"
+// @Param variant_id formData int false "Variant the images show"
+// @Success 201 {object} handler.Response{data=[]domain.Image} "images uploaded"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Failure 500 {object} handler.Response "failed to upload images"
+// @Router  /products/{id}/images [post]
+func (ctrl *ControllerProductImage) Upload(c *gin.Context) {
+	productId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	form, err := c.MultipartForm()
+	if err != nil || len(form.File["images"]) == 0 {
+		BadResponse(c, "Bad Request (Body)", http.StatusBadRequest)
+		return
+	}
+
+	altText := c.PostForm("alt_text")
+	if len(altText) > 150 {
+		BadResponse(c, "alt text is limited to 150 characters", http.StatusBadRequest)
+		return
+	}
+	var variantId *int
+	if value := c.PostForm("variant_id"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			BadResponse(c, "Bad Request (Body)", http.StatusBadRequest)
+			return
+		}
+		variantId = &id
+	}
+
+	uploads, err := ctrl.images.UploadAll(c.Request.Context(), form.File["images"])
+	if err != nil {
+		ctrl.logger.Error("Failed to upload images", zap.Int("product_id", productId), zap.Error(err))
+		BadResponse(c, "failed to upload images: "+err.Error(), uploadStatus(err))
+		return
+	}
+
+	var images []*domain.Image
+	for _, upload := range uploads {
+		images = append(images, &domain.Image{
+			ProductVariantID: variantId,
+			URLPath:          upload.URL,
+			FileID:           upload.Key,
+			Renditions:       upload.Renditions,
+			RenditionFileIDs: upload.RenditionKeys,
+			AltText:          altText,
+		})
+	}
+
+	created, err := ctrl.service.Upload(productId, images)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+	GoodResponseWithData(c, "images uploaded", http.StatusCreated, created)
+}
+
+// @Summary Update a product image
+// @Description Change the alt text of an image or the variant it shows, a variant id of 0 detaches it from its variant
+// @Tags Products
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Product ID"
+// @Param imageId path int true "Image ID"
+// @Param image body domain.ImageUpdate true "Alt text and variant"
+// @Success 200 {object} handler.Response{data=domain.Image} "image updated"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Router  /products/{id}/images/{imageId} [put]
+func (ctrl *ControllerProductImage) Update(c *gin.Context) {
+	productId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	id, err := strconv.Atoi(c.Param("imageId"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	var input domain.ImageUpdate
+	if err := c.ShouldBindJSON(&input); err != nil {
+		BadResponse(c, "Bad Request (Body)", http.StatusBadRequest)
+		return
+	}
+	image, err := ctrl.service.Update(productId, id, input)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+	GoodResponseWithData(c, "image updated", http.StatusOK, image)
+}
+
+// @Summary Reorder product images
+// @Description Set the display order of the images of a product, the ids must list every image of the product
+// @Tags Products
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Product ID"
+// @Param order body domain.ImageOrder true "Image ids in display order"
+// @Success 200 {object} handler.Response{data=[]domain.Image} "images reordered"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Router  /products/{id}/images/order [put]
+func (ctrl *ControllerProductImage) Reorder(c *gin.Context) {
+	productId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	var order domain.ImageOrder
+	if err := c.ShouldBindJSON(&order); err != nil {
+		BadResponse(c, "Bad Request (Body)", http.StatusBadRequest)
+		return
+	}
+	images, err := ctrl.service.Reorder(productId, order)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+	GoodResponseWithData(c, "images reordered", http.StatusOK, images)
+}
+
+// @Summary Set the primary product image
+// @Description Make an image the primary image of its product
+// @Tags Products
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Product ID"
+// @Param imageId path int true "Image ID"
+// @Success 200 {object} handler.Response{data=domain.Image} "primary image set"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Router  /products/{id}/images/{imageId}/primary [put]
+func (ctrl *ControllerProductImage) SetPrimary(c *gin.Context) {
+	productId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	id, err := strconv.Atoi(c.Param("imageId"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	image, err := ctrl.service.SetPrimary(productId, id)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+	GoodResponseWithData(c, "primary image set", http.StatusOK, image)
+}
+
+// @Summary Delete a product image
+// @Description Move an image of a product to the trash, its files and renditions are removed from storage when it's purged
+// @Tags Products
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Product ID"
+// @Param imageId path int true "Image ID"
+// @Success 200 {object} handler.Response "image deleted"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Router  /products/{id}/images/{imageId} [delete]
+func (ctrl *ControllerProductImage) Delete(c *gin.Context) {
+	productId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	id, err := strconv.Atoi(c.Param("imageId"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	if err := ctrl.service.Delete(productId, id); err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+	GoodResponseWithData(c, "image deleted", http.StatusOK, nil)
+}
@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"net/http"
+	"project/domain"
+	"project/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type ControllerProductVariant struct {
+	service service.ServiceProductVariant
+	logger  *zap.Logger
+}
+
+func NewControllerProductVariant(service service.ServiceProductVariant, logger *zap.Logger) *ControllerProductVariant {
+	return &ControllerProductVariant{service: service, logger: logger}
+}
+
+// @Summary Product variants
+// @Description Get the variants of a product with their SKU, price override, weight, size, color and stock
+// @Tags Products
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Product ID"
+// @Success 200 {object} handler.Response{data=[]domain.ProductVariant} "variants retrieved"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Failure 404 {object} handler.Response "product not found"
+// @Router  /products/{id}/variants [get]
+func (ctrl *ControllerProductVariant) GetByProduct(c *gin.Context) {
+	productId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	variants, err := ctrl.service.GetByProduct(productId)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusNotFound)
+		return
+	}
+	GoodResponseWithData(c, "variants retrieved", http.StatusOK, variants)
+}
+
+// @Summary Create a product variant
+// @Description Add a variant to a product, its opening stock is recorded in the stock ledger
+// @Tags Products
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Product ID"
+// @Param variant body domain.NewProductVariant true "SKU, size, color, price override, weight and opening stock"
+// @Success 201 {object} handler.Response{data=domain.ProductVariant} "variant created"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Router  /products/{id}/variants [post]
+func (ctrl *ControllerProductVariant) Create(c *gin.Context) {
+	productId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	var newVariant domain.NewProductVariant
+	if err := c.ShouldBindJSON(&newVariant); err != nil {
+		BadResponse(c, "Bad Request (Body)", http.StatusBadRequest)
+		return
+	}
+	variant, err := ctrl.service.Create(productId, newVariant)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+	GoodResponseWithData(c, "variant created", http.StatusCreated, variant)
+}
+
+// @Summary Generate product variants
+// @Description Create one variant for every combination of the given sizes and colors, skipping the combinations the product already has
+// @Tags Products
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Product ID"
+// @Param generator body domain.VariantGenerator true "Sizes, colors and the shared price override, weight and opening stock"
+// @Success 201 {object} handler.Response{data=[]domain.ProductVariant} "variants generated"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Router  /products/{id}/variants/generate [post]
+func (ctrl *ControllerProductVariant) Generate(c *gin.Context) {
+	productId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	var generator domain.VariantGenerator
+	if err := c.ShouldBindJSON(&generator); err != nil {
+		BadResponse(c, "Bad Request (Body)", http.StatusBadRequest)
+		return
+	}
+	variants, err := ctrl.service.Generate(productId, generator)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+	GoodResponseWithData(c, "variants generated", http.StatusCreated, variants)
+}
+
+// @Summary Update a product variant
+// @Description Update the SKU, size, color, price override and weight of a variant, stock changes go through the stock endpoints
+// @Tags Products
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Product ID"
+// @Param variantId path int true "Variant ID"
+// @Param variant body domain.ProductVariantInput true "SKU, size, color, price override and weight"
+// @Success 200 {object} handler.Response{data=domain.ProductVariant} "variant updated"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Router  /products/{id}/variants/{variantId} [put]
+func (ctrl *ControllerProductVariant) Update(c *gin.Context) {
+	productId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	id, err := strconv.Atoi(c.Param("variantId"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	var input domain.ProductVariantInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		BadResponse(c, "Bad Request (Body)", http.StatusBadRequest)
+		return
+	}
+	variant, err := ctrl.service.Update(productId, id, input)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+	GoodResponseWithData(c, "variant updated", http.StatusOK, variant)
+}
+
+// @Summary Delete a product variant
+// @Description Remove a variant from a product, past orders keep referencing it
+// @Tags Products
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Product ID"
+// @Param variantId path int true "Variant ID"
+// @Success 200 {object} handler.Response "variant deleted"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Failure 404 {object} handler.Response "variant not found"
+// @Router  /products/{id}/variants/{variantId} [delete]
+func (ctrl *ControllerProductVariant) Delete(c *gin.Context) {
+	productId, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	id, err := strconv.Atoi(c.Param("variantId"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	if err := ctrl.service.Delete(productId, id); err != nil {
+		BadResponse(c, err.Error(), http.StatusNotFound)
+		return
+	}
+	GoodResponseWithData(c, "variant deleted", http.StatusOK, nil)
+}
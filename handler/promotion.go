@@ -109,3 +109,89 @@ func (ctrl *ControllerPromotion) Delete(c *gin.Context) {
 	}
 	GoodResponseWithData(c, "Delete Promotion success", http.StatusOK, data)
 }
+
+// @Summary Restrict a promotion to a segment
+// @Description Limit a promotion to the customers of a segment, or open it to everyone again with a null segment_id
+// @Tags promotions
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Promotion ID"
+// @Param segment body domain.PromotionSegment true "Segment ID or null"
+// @Success 200 {object} handler.Response{data=domain.Promotion}  "Promotion details"
+// @Failure 400 {object} handler.Response  "Bad Request"
+// @Router /promotion/{id}/segment [put]
+func (ctrl *ControllerPromotion) Restrict(c *gin.Context) {
+	id, err := helper.Uint(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	var data domain.PromotionSegment
+	if err := c.ShouldBindJSON(&data); err != nil {
+		BadResponse(c, "Bad Request (Body)", http.StatusBadRequest)
+		return
+	}
+	promotion, err := ctrl.service.Restrict(id, data.SegmentID)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+	GoodResponseWithData(c, "Restrict Promotion success", http.StatusOK, promotion)
+}
+
+// @Summary Evaluate the discount of a cart
+// @Description Price the items and compute the discount a customer gets, from the voucher code when one is given or else from the best direct discount, without using the promotion up. Admin only
+// @Tags promotions
+// @Accept  json
+// @Produce  json
+// @Param quote body domain.PromotionQuote true "Customer, items and optional voucher code"
+// @Success 200 {object} handler.Response{data=domain.PromotionEvaluation}  "Discount of the cart"
+// @Failure 400 {object} handler.Response  "Bad Request"
+// @Router /promotion/evaluate [post]
+func (ctrl *ControllerPromotion) Evaluate(c *gin.Context) {
+	var quote domain.PromotionQuote
+	if err := c.ShouldBindJSON(&quote); err != nil {
+		BadResponse(c, "Bad Request (Body)", http.StatusBadRequest)
+		return
+	}
+	evaluation, err := ctrl.service.Evaluate(quote)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+	GoodResponseWithData(c, "Evaluate Promotion success", http.StatusOK, evaluation)
+}
+
+// @Summary Usages of a promotion
+// @Description Get the orders a promotion has been used on, the latest first
+// @Tags promotions
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Promotion ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Number of items per page" default(10)
+// @Success 200 {object} handler.Response{data=[]domain.PromotionUsage}  "Promotion usages"
+// @Failure 400 {object} handler.Response  "Bad Request"
+// @Router /promotion/{id}/usage [get]
+func (ctrl *ControllerPromotion) GetUsages(c *gin.Context) {
+	id, err := helper.Uint(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	page, _ := helper.Uint(c.Query("page"))
+	if page == 0 {
+		page = 1
+	}
+	limit, _ := helper.Uint(c.Query("limit"))
+	if limit == 0 {
+		limit = 10
+	}
+
+	usages, total, pages, err := ctrl.service.GetUsages(id, page, limit)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+	GoodResponseWithPage(c, "Get Promotion usages success", http.StatusOK, total, pages, int(page), int(limit), usages)
+}
@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"net/http"
+	"project/domain"
+	"project/helper"
+	"project/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type ControllerPurchaseOrder struct {
+	service service.ServicePurchaseOrder
+	logger  *zap.Logger
+}
+
+func NewControllerPurchaseOrder(service service.ServicePurchaseOrder, logger *zap.Logger) *ControllerPurchaseOrder {
+	return &ControllerPurchaseOrder{service: service, logger: logger}
+}
+
+// @Summary Get all purchase orders
+// @Description Fetches a paginated list of purchase orders, optionally filtered by status
+// @Tags Purchase Order
+// @Accept  json
+// @Produce  json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Number of items per page" default(10)
+// @Param status query string false "draft, sent, partially_received, received or closed"
+// @Success 200 {object} handler.Response{data=[]domain.PurchaseOrder} "purchase orders retrieved"
+// @Failure 404 {object} handler.Response "no data found"
+// @Router  /purchase-orders [get]
+func (ctrl *ControllerPurchaseOrder) GetAll(c *gin.Context) {
+	page, _ := helper.Uint(c.Query("page"))
+	if page == 0 {
+		page = 1
+	}
+	limit, _ := helper.Uint(c.Query("limit"))
+	if limit == 0 {
+		limit = 10
+	}
+
+	total, pages, purchaseOrders, err := ctrl.service.GetAll(page, limit, c.Query("status"))
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	GoodResponseWithPage(c, "purchase orders retrieved", http.StatusOK, total, pages, int(page), int(limit), purchaseOrders)
+}
+
+// @Summary Get a purchase order by ID
+// @Description Get a purchase order with its supplier and line items
+// @Tags Purchase Order
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Purchase Order ID"
+// @Success 200 {object} handler.Response{data=domain.PurchaseOrder} "purchase order retrieved"
+// @Failure 404 {object} handler.Response "purchase order not found"
+// @Router  /purchase-orders/{id} [get]
+func (ctrl *ControllerPurchaseOrder) GetById(c *gin.Context) {
+	id, err := helper.Uint(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	po, err := ctrl.service.GetById(id)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusNotFound)
+		return
+	}
+	GoodResponseWithData(c, "purchase order retrieved", http.StatusOK, po)
+}
+
+// @Summary Create a purchase order
+// @Description Create a draft purchase order for a supplier with line items per product variant
+// @Tags Purchase Order
+// @Accept  json
+// @Produce  json
+// @Param purchaseOrder body domain.PurchaseOrder true "Purchase order data"
+// @Success 201 {object} handler.Response{data=domain.PurchaseOrder} "purchase order created"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Router  /purchase-orders [post]
+func (ctrl *ControllerPurchaseOrder) Create(c *gin.Context) {
+	var po domain.PurchaseOrder
+	if err := c.ShouldBindJSON(&po); err != nil {
+		BadResponse(c, "Bad Request (Body)", http.StatusBadRequest)
+		return
+	}
+	if err := ctrl.service.Create(&po); err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+	GoodResponseWithData(c, "purchase order created", http.StatusCreated, po)
+}
+
+// @Summary Edit a draft purchase order
+// @Description Replace the supplier, note and line items of a draft purchase order
+// @Tags Purchase Order
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Purchase Order ID"
+// @Param purchaseOrder body domain.PurchaseOrder true "Purchase order data"
+// @Success 200 {object} handler.Response{data=domain.PurchaseOrder} "purchase order updated"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Router  /purchase-orders/{id} [put]
+func (ctrl *ControllerPurchaseOrder) Edit(c *gin.Context) {
+	id, err := helper.Uint(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	var po domain.PurchaseOrder
+	if err := c.ShouldBindJSON(&po); err != nil {
+		BadResponse(c, "Bad Request (Body)", http.StatusBadRequest)
+		return
+	}
+	po.ID = id
+	if err := ctrl.service.Edit(&po); err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+	GoodResponseWithData(c, "purchase order updated", http.StatusOK, po)
+}
+
+// @Summary Send a purchase order
+// @Description Mark a draft purchase order as sent to the supplier
+// @Tags Purchase Order
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Purchase Order ID"
+// @Success 200 {object} handler.Response{data=domain.PurchaseOrder} "purchase order sent"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Router  /purchase-orders/{id}/send [post]
+func (ctrl *ControllerPurchaseOrder) Send(c *gin.Context) {
+	id, err := helper.Uint(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	po, err := ctrl.service.Send(id)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+	GoodResponseWithData(c, "purchase order sent", http.StatusOK, po)
+}
+
+// @Summary Close a purchase order
+// @Description Close a purchase order, including one that was only partially received
+// @Tags Purchase Order
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Purchase Order ID"
+// @Success 200 {object} handler.Response{data=domain.PurchaseOrder} "purchase order closed"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Router  /purchase-orders/{id}/close [post]
+func (ctrl *ControllerPurchaseOrder) Close(c *gin.Context) {
+	id, err := helper.Uint(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	po, err := ctrl.service.Close(id)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+	GoodResponseWithData(c, "purchase order closed", http.StatusOK, po)
+}
+
+// @Summary Receive goods
+// @Description Book a partial or full delivery and increase the stock of the received variants
+// @Tags Purchase Order
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Purchase Order ID"
+// @Param receipt body domain.GoodsReceipt true "Received quantities per purchase order item"
+// @Success 200 {object} handler.Response{data=domain.PurchaseOrder} "goods received"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Router  /purchase-orders/{id}/receive [post]
+func (ctrl *ControllerPurchaseOrder) Receive(c *gin.Context) {
+	id, err := helper.Uint(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	var receipt domain.GoodsReceipt
+	if err := c.ShouldBindJSON(&receipt); err != nil {
+		BadResponse(c, "Bad Request (Body)", http.StatusBadRequest)
+		return
+	}
+	po, err := ctrl.service.Receive(id, receipt)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+	GoodResponseWithData(c, "goods received", http.StatusOK, po)
+}
+
+// @Summary Open purchase orders report
+// @Description List sent and partially received purchase orders with their outstanding quantity and value
+// @Tags Purchase Order
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} handler.Response{data=[]domain.OpenPurchaseOrder} "open purchase orders retrieved"
+// @Failure 500 {object} handler.Response "server error"
+// @Router  /purchase-orders/open [get]
+func (ctrl *ControllerPurchaseOrder) GetOpen(c *gin.Context) {
+	report, err := ctrl.service.GetOpen()
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	GoodResponseWithData(c, "open purchase orders retrieved", http.StatusOK, report)
+}
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"net/http"
+	"project/domain"
+	"project/helper"
+	"project/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type ControllerRefund struct {
+	service service.ServiceRefund
+	logger  *zap.Logger
+}
+
+func NewControllerRefund(service service.ServiceRefund, logger *zap.Logger) *ControllerRefund {
+	return &ControllerRefund{service: service, logger: logger}
+}
+
+// @Summary Order refunds
+// @Description Get the refunds requested for an order
+// @Tags Refund
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Order ID"
+// @Success 200 {object} handler.Response{data=[]domain.Refund} "refunds retrieved"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Failure 404 {object} handler.Response "order not found"
+// @Router  /orders/{id}/refunds [get]
+func (ctrl *ControllerRefund) GetByOrder(c *gin.Context) {
+	id, err := helper.Uint(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	refunds, err := ctrl.service.GetByOrder(id)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusNotFound)
+		return
+	}
+	GoodResponseWithData(c, "refunds retrieved", http.StatusOK, refunds)
+}
+
+// @Summary Request a refund
+// @Description Request a refund of an amount of the order or of some of its items
+// @Tags Refund
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Order ID"
+// @Param refund body domain.NewRefund true "Reason, amount and returned items"
+// @Success 201 {object} handler.Response{data=domain.Refund} "refund requested"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Router  /orders/{id}/refunds [post]
+func (ctrl *ControllerRefund) Create(c *gin.Context) {
+	id, err := helper.Uint(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	var request domain.NewRefund
+	if err := c.ShouldBindJSON(&request); err != nil {
+		BadResponse(c, "Bad Request (Body)", http.StatusBadRequest)
+		return
+	}
+	refund, err := ctrl.service.Create(id, request)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+	GoodResponseWithData(c, "refund requested", http.StatusCreated, refund)
+}
+
+// @Summary Get a refund by ID
+// @Description Get a refund with its returned items
+// @Tags Refund
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Refund ID"
+// @Success 200 {object} handler.Response{data=domain.Refund} "refund retrieved"
+// @Failure 404 {object} handler.Response "refund not found"
+// @Router  /refunds/{id} [get]
+func (ctrl *ControllerRefund) GetById(c *gin.Context) {
+	id, err := helper.Uint(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	refund, err := ctrl.service.GetById(id)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusNotFound)
+		return
+	}
+	GoodResponseWithData(c, "refund retrieved", http.StatusOK, refund)
+}
+
+// @Summary Approve a refund
+// @Description Approve a requested refund
+// @Tags Refund
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Refund ID"
+// @Param decision body domain.RefundDecision false "Note for the decision"
+// @Success 200 {object} handler.Response{data=domain.Refund} "refund approved"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Router  /refunds/{id}/approve [post]
+func (ctrl *ControllerRefund) Approve(c *gin.Context) {
+	ctrl.decide(c, "refund approved", ctrl.service.Approve)
+}
+
+// @Summary Reject a refund
+// @Description Reject a requested refund
+// @Tags Refund
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Refund ID"
+// @Param decision body domain.RefundDecision false "Note for the decision"
+// @Success 200 {object} handler.Response{data=domain.Refund} "refund rejected"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Router  /refunds/{id}/reject [post]
+func (ctrl *ControllerRefund) Reject(c *gin.Context) {
+	ctrl.decide(c, "refund rejected", ctrl.service.Reject)
+}
+
+func (ctrl *ControllerRefund) decide(c *gin.Context, message string, decide func(uint, domain.RefundDecision) (domain.Refund, error)) {
+	id, err := helper.Uint(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	var decision domain.RefundDecision
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&decision); err != nil {
+			BadResponse(c, "Bad Request (Body)", http.StatusBadRequest)
+			return
+		}
+	}
+	refund, err := decide(id, decision)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+	GoodResponseWithData(c, message, http.StatusOK, refund)
+}
+
+// @Summary Pay a refund
+// @Description Mark an approved refund as paid out and restock its returned items when requested
+// @Tags Refund
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Refund ID"
+// @Success 200 {object} handler.Response{data=domain.Refund} "refund paid"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Router  /refunds/{id}/pay [post]
+func (ctrl *ControllerRefund) Pay(c *gin.Context) {
+	id, err := helper.Uint(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	refund, err := ctrl.service.Pay(id)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+	GoodResponseWithData(c, "refund paid", http.StatusOK, refund)
+}
@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"net/http"
+	"project/domain"
+	"project/helper"
+	"project/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type ControllerReview struct {
+	service service.ServiceReview
+	logger  *zap.Logger
+}
+
+func NewControllerReview(service service.ServiceReview, logger *zap.Logger) *ControllerReview {
+	return &ControllerReview{service: service, logger: logger}
+}
+
+// @Summary Product reviews
+// @Description Get reviews filtered by product, rating, date and moderation status, the latest first. Only admins see pending and hidden reviews
+// @Tags Review
+// @Accept  json
+// @Produce  json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Number of items per page" default(10)
+// @Param product_id query int false "Product ID"
+// @Param rating query int false "Stars from 1 to 5"
+// @Param status query string false "pending, approved or hidden" default(approved)
+// @Param date_from query string false "Created on or after (yyyy-mm-dd)"
+// @Param date_to query string false "Created on or before (yyyy-mm-dd)"
+// @Success 200 {object} handler.Response{data=[]domain.Review} "reviews retrieved"
+// @Failure 400 {object} handler.Response "invalid filter"
+// @Failure 500 {object} handler.Response "server error"
+// @Router  /reviews [get]
+func (ctrl *ControllerReview) GetAll(c *gin.Context) {
+	page, _ := helper.Uint(c.Query("page"))
+	if page == 0 {
+		page = 1
+	}
+	limit, _ := helper.Uint(c.Query("limit"))
+	if limit == 0 {
+		limit = 10
+	}
+
+	var filter domain.ReviewFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		BadResponse(c, "invalid filter", http.StatusBadRequest)
+		return
+	}
+	if err := filter.Validate(); err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !isAdmin(c) {
+		filter.Status = domain.ReviewApproved
+	}
+
+	reviews, total, pages, err := ctrl.service.GetAll(filter, page, limit)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	GoodResponseWithFilters(c, "reviews retrieved", http.StatusOK, total, pages, int(page), int(limit), filter, reviews)
+}
+
+// @Summary Review
+// @Description Get a review by ID, only admins see pending and hidden reviews
+// @Tags Review
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Review ID"
+// @Success 200 {object} handler.Response{data=domain.Review} "review retrieved"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Failure 404 {object} handler.Response "review not found"
+// @Router  /reviews/{id} [get]
+func (ctrl *ControllerReview) GetById(c *gin.Context) {
+	id, err := helper.Uint(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	review, err := ctrl.service.GetById(id)
+	if err != nil || (review.Status != domain.ReviewApproved && !isAdmin(c)) {
+		BadResponse(c, "review not found", http.StatusNotFound)
+		return
+	}
+	GoodResponseWithData(c, "review retrieved", http.StatusOK, review)
+}
+
+// @Summary Review an ordered item
+// @Description Review an item of a delivered order, the review is pending until it's moderated
+// @Tags Review
+// @Accept  json
+// @Produce  json
+// @Param review body domain.NewReview true "Order item, rating and comment"
+// @Success 201 {object} handler.Response{data=domain.Review} "review created"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Router  /reviews [post]
+func (ctrl *ControllerReview) Create(c *gin.Context) {
+	var newReview domain.NewReview
+	if err := c.ShouldBindJSON(&newReview); err != nil {
+		BadResponse(c, "Bad Request (Body)", http.StatusBadRequest)
+		return
+	}
+	review, err := ctrl.service.Create(newReview)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+	GoodResponseWithData(c, "review created", http.StatusCreated, review)
+}
+
+// @Summary Moderate a review
+// @Description Approve or hide a review, or put it back to pending
+// @Tags Review
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Review ID"
+// @Param status body domain.ReviewModeration true "Moderation status"
+// @Success 200 {object} handler.Response{data=domain.Review} "review moderated"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Failure 404 {object} handler.Response "review not found"
+// @Router  /reviews/{id}/status [put]
+func (ctrl *ControllerReview) Moderate(c *gin.Context) {
+	id, err := helper.Uint(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	var moderation domain.ReviewModeration
+	if err := c.ShouldBindJSON(&moderation); err != nil {
+		BadResponse(c, "Bad Request (Body)", http.StatusBadRequest)
+		return
+	}
+	review, err := ctrl.service.Moderate(id, moderation)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusNotFound)
+		return
+	}
+	GoodResponseWithData(c, "review moderated", http.StatusOK, review)
+}
+
+// @Summary Reply to a review
+// @Description Add the merchant's reply to a review, a new reply replaces the previous one
+// @Tags Review
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Review ID"
+// @Param reply body domain.ReviewReply true "Reply"
+// @Success 200 {object} handler.Response{data=domain.Review} "reply saved"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Failure 404 {object} handler.Response "review not found"
+// @Router  /reviews/{id}/reply [put]
+func (ctrl *ControllerReview) Reply(c *gin.Context) {
+	id, err := helper.Uint(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	var reply domain.ReviewReply
+	if err := c.ShouldBindJSON(&reply); err != nil {
+		BadResponse(c, "Bad Request (Body)", http.StatusBadRequest)
+		return
+	}
+	review, err := ctrl.service.Reply(id, reply)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusNotFound)
+		return
+	}
+	GoodResponseWithData(c, "reply saved", http.StatusOK, review)
+}
+
+// @Summary Products with declining sentiment
+// @Description Get the products whose review sentiment dropped the most between the latest window of days and the one before it, sentiment runs from -1 to 1
+// @Tags Dashboard
+// @Accept  json
+// @Produce  json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Number of items per page" default(10)
+// @Param min_drop query number false "Minimum drop of the average sentiment" default(0.1)
+// @Param min_reviews query int false "Minimum reviews in both windows" default(3)
+// @Success 200 {object} handler.Response{data=[]domain.ProductSentiment} "declining sentiment retrieved"
+// @Failure 400 {object} handler.Response "invalid filter"
+// @Failure 500 {object} handler.Response "server error"
+// @Router  /dashboard/decliningSentiment [get]
+func (ctrl *ControllerReview) GetDecliningSentiment(c *gin.Context) {
+	page, _ := helper.Uint(c.Query("page"))
+	if page == 0 {
+		page = 1
+	}
+	limit, _ := helper.Uint(c.Query("limit"))
+	if limit == 0 {
+		limit = 10
+	}
+
+	var filter domain.SentimentDeclineFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		BadResponse(c, "invalid filter", http.StatusBadRequest)
+		return
+	}
+	if err := filter.Validate(); err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	summaries, total, pages, err := ctrl.service.GetDecliningSentiment(filter, page, limit)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	GoodResponseWithFilters(c, "declining sentiment retrieved", http.StatusOK, total, pages, int(page), int(limit), filter, summaries)
+}
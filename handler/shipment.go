@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"net/http"
+	"project/domain"
+	"project/helper"
+	"project/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type ControllerShipment struct {
+	service service.ServiceShipment
+	logger  *zap.Logger
+}
+
+func NewControllerShipment(service service.ServiceShipment, logger *zap.Logger) *ControllerShipment {
+	return &ControllerShipment{service: service, logger: logger}
+}
+
+// @Summary Order shipments
+// @Description Get the shipments of an order with their carrier, tracking number and delivery status
+// @Tags Order
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Order ID"
+// @Success 200 {object} handler.Response{data=[]domain.Shipment} "shipments retrieved"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Failure 404 {object} handler.Response "order not found"
+// @Router  /orders/{id}/shipments [get]
+func (ctrl *ControllerShipment) GetByOrder(c *gin.Context) {
+	id, err := helper.Uint(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	shipments, err := ctrl.service.GetByOrder(id)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusNotFound)
+		return
+	}
+	GoodResponseWithData(c, "shipments retrieved", http.StatusOK, shipments)
+}
+
+// @Summary Ship an order
+// @Description Create a shipping label with a carrier and mark a processed order as shipped
+// @Tags Order
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Order ID"
+// @Param shipment body domain.NewShipment true "Carrier, service and parcel weight"
+// @Success 201 {object} handler.Response{data=domain.Shipment} "shipment created"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Router  /orders/{id}/shipments [post]
+func (ctrl *ControllerShipment) Create(c *gin.Context) {
+	id, err := helper.Uint(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	var newShipment domain.NewShipment
+	if err := c.ShouldBindJSON(&newShipment); err != nil {
+		BadResponse(c, "Bad Request (Body)", http.StatusBadRequest)
+		return
+	}
+	shipment, err := ctrl.service.Create(id, newShipment)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+	GoodResponseWithData(c, "shipment created", http.StatusCreated, shipment)
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"net/http"
+	"project/domain"
+	"project/helper"
+	"project/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type ControllerSupplier struct {
+	service service.ServiceSupplier
+	logger  *zap.Logger
+}
+
+func NewControllerSupplier(service service.ServiceSupplier, logger *zap.Logger) *ControllerSupplier {
+	return &ControllerSupplier{service: service, logger: logger}
+}
+
+// @Summary Get All Supplier
+// @Description Endpoint Fetch All Supplier
+// @Tags Supplier
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} handler.Response{data=[]domain.Supplier} "Get All Success"
+// @Failure 500 {object} handler.Response "server error"
+// @Router  /suppliers [get]
+func (ctrl *ControllerSupplier) GetAll(c *gin.Context) {
+	suppliers, err := ctrl.service.GetAll()
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	GoodResponseWithData(c, "Get Suppliers success", http.StatusOK, suppliers)
+}
+
+// @Summary Get a supplier by ID
+// @Description Get details of a specific supplier using the provided ID
+// @Tags Supplier
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Supplier ID"
+// @Success 200 {object} handler.Response{data=domain.Supplier} "Supplier details"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Failure 404 {object} handler.Response "Supplier not found"
+// @Router /suppliers/{id} [get]
+func (ctrl *ControllerSupplier) GetById(c *gin.Context) {
+	id, err := helper.Uint(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	supplier, err := ctrl.service.GetById(id)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusNotFound)
+		return
+	}
+	GoodResponseWithData(c, "Get Supplier success", http.StatusOK, supplier)
+}
+
+// @Summary Create a new supplier
+// @Description Create a new supplier by sending the supplier data in the request body
+// @Tags Supplier
+// @Accept  json
+// @Produce  json
+// @Param supplier body domain.Supplier true "Supplier data"
+// @Success 201 {object} handler.Response{data=domain.Supplier} "Supplier details"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Router /suppliers [post]
+func (ctrl *ControllerSupplier) Create(c *gin.Context) {
+	var data domain.Supplier
+	if err := c.ShouldBindJSON(&data); err != nil {
+		BadResponse(c, "Bad Request (Body)", http.StatusBadRequest)
+		return
+	}
+	if err := ctrl.service.Create(&data); err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+	GoodResponseWithData(c, "Create Supplier success", http.StatusCreated, data)
+}
+
+// @Summary Edit a supplier
+// @Description Update the details of a supplier
+// @Tags Supplier
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Supplier ID"
+// @Param supplier body domain.Supplier true "Supplier data"
+// @Success 200 {object} handler.Response{data=domain.Supplier} "Supplier details"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Router /suppliers/{id} [put]
+func (ctrl *ControllerSupplier) Edit(c *gin.Context) {
+	id, err := helper.Uint(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	var data domain.Supplier
+	if err := c.ShouldBindJSON(&data); err != nil {
+		BadResponse(c, "Bad Request (Body)", http.StatusBadRequest)
+		return
+	}
+	data.ID = id
+	if err := ctrl.service.Edit(&data); err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+	GoodResponseWithData(c, "Edit Supplier success", http.StatusOK, data)
+}
+
+// @Summary Delete a supplier by ID
+// @Description Delete a supplier that has no purchase orders
+// @Tags Supplier
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Supplier ID"
+// @Success 200 {object} handler.Response{data=domain.Supplier} "Supplier details"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Router /suppliers/{id} [delete]
+func (ctrl *ControllerSupplier) Delete(c *gin.Context) {
+	id, err := helper.Uint(c.Param("id"))
+	if err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	data := domain.Supplier{ID: id}
+	if err := ctrl.service.Delete(&data); err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+	GoodResponseWithData(c, "Delete Supplier success", http.StatusOK, data)
+}
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"project/domain"
+	"project/helper"
+	"project/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type ControllerTrash struct {
+	service service.ServiceTrash
+	logger  *zap.Logger
+}
+
+func NewControllerTrash(service service.ServiceTrash, logger *zap.Logger) *ControllerTrash {
+	return &ControllerTrash{service: service, logger: logger}
+}
+
+// @Summary Trash of an entity
+// @Description List the deleted products, variants, images, banners, categories or promotions, the most recently deleted first
+// @Tags Trash
+// @Accept  json
+// @Produce  json
+// @Param entity path string true "Entity" Enums(products, variants, images, banners, categories, promotions)
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Number of items per page" default(10)
+// @Success 200 {object} handler.Response{data=[]domain.TrashItem} "trash retrieved"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Router  /trash/{entity} [get]
+func (ctrl *ControllerTrash) GetAll(c *gin.Context) {
+	entity := domain.TrashEntity(c.Param("entity"))
+	if !entity.Valid() {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	page, _ := helper.Uint(c.Query("page"))
+	if page == 0 {
+		page = 1
+	}
+	limit, _ := helper.Uint(c.Query("limit"))
+	if limit == 0 {
+		limit = 10
+	}
+
+	items, total, pages, err := ctrl.service.GetAll(entity, int(page), int(limit))
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	GoodResponseWithPage(c, "trash retrieved", http.StatusOK, total, pages, int(page), int(limit), items)
+}
+
+// @Summary Restore from the trash
+// @Description Restore a deleted row, variants and images need their product restored first and restored products come back as drafts
+// @Tags Trash
+// @Accept  json
+// @Produce  json
+// @Param entity path string true "Entity" Enums(products, variants, images, banners, categories, promotions)
+// @Param id path int true "ID"
+// @Success 200 {object} handler.Response "restored"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Router  /trash/{entity}/{id}/restore [post]
+func (ctrl *ControllerTrash) Restore(c *gin.Context) {
+	entity, id, ok := trashParams(c)
+	if !ok {
+		return
+	}
+	if err := ctrl.service.Restore(entity, id); err != nil {
+		BadResponse(c, err.Error(), http.StatusBadRequest)
+		return
+	}
+	GoodResponseWithData(c, "restored", http.StatusOK, id)
+}
+
+// @Summary Purge from the trash
+// @Description Permanently delete a row from the trash, rows orders still refer to can't be purged
+// @Tags Trash
+// @Accept  json
+// @Produce  json
+// @Param entity path string true "Entity" Enums(products, variants, images, banners, categories, promotions)
+// @Param id path int true "ID"
+// @Success 200 {object} handler.Response "purged"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Failure 409 {object} handler.Response "still referenced"
+// @Router  /trash/{entity}/{id} [delete]
+func (ctrl *ControllerTrash) Purge(c *gin.Context) {
+	entity, id, ok := trashParams(c)
+	if !ok {
+		return
+	}
+	if err := ctrl.service.Purge(entity, id); err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, domain.ErrStillReferenced) {
+			status = http.StatusConflict
+		}
+		BadResponse(c, err.Error(), status)
+		return
+	}
+	GoodResponseWithData(c, "purged", http.StatusOK, id)
+}
+
+// @Summary Empty the trash
+// @Description Permanently delete everything in the trash of an entity, rows orders still refer to are skipped
+// @Tags Trash
+// @Accept  json
+// @Produce  json
+// @Param entity path string true "Entity" Enums(products, variants, images, banners, categories, promotions)
+// @Success 200 {object} handler.Response{data=domain.TrashPurge} "trash emptied"
+// @Failure 400 {object} handler.Response "Bad Request"
+// @Router  /trash/{entity} [delete]
+func (ctrl *ControllerTrash) Empty(c *gin.Context) {
+	entity := domain.TrashEntity(c.Param("entity"))
+	if !entity.Valid() {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return
+	}
+	result, err := ctrl.service.Empty(entity)
+	if err != nil {
+		BadResponse(c, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	GoodResponseWithData(c, "trash emptied", http.StatusOK, result)
+}
+
+func trashParams(c *gin.Context) (domain.TrashEntity, uint, bool) {
+	entity := domain.TrashEntity(c.Param("entity"))
+	id, err := helper.Uint(c.Param("id"))
+	if !entity.Valid() || err != nil {
+		BadResponse(c, "Bad Request (Params)", http.StatusBadRequest)
+		return "", 0, false
+	}
+	return entity, id, true
+}
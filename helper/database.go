@@ -1,6 +1,11 @@
 package helper
 
 import (
+	"errors"
+	"project/domain"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
 )
 
@@ -10,3 +15,40 @@ func Paginate(page uint, limit uint) func(db *gorm.DB) *gorm.DB {
 		return db.Offset(int(offset)).Limit(int(limit))
 	}
 }
+
+// CategorySubtree is a subquery selecting the id of a category and of all
+// its descendants.
+func CategorySubtree(db *gorm.DB, id uint) *gorm.DB {
+	return db.Raw(`
+		WITH RECURSIVE subtree AS (
+			SELECT id FROM categories WHERE id = ?
+			UNION ALL
+			SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+		)
+		SELECT id FROM subtree`, id)
+}
+
+// ImagesInOrder preloads the images of a product in their display order.
+func ImagesInOrder(db *gorm.DB) *gorm.DB {
+	return db.Order("position, id")
+}
+
+// ActiveProducts limits a products query to the products customers can see.
+func ActiveProducts(db *gorm.DB) *gorm.DB {
+	return db.Where("products.status = ?", domain.ProductActive)
+}
+
+// UniqueViolation reports whether a write failed on a unique constraint.
+func UniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+// likeEscaper escapes the LIKE wildcards, backslash is the default escape
+// character of Postgres.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// Contains is a LIKE pattern matching values that contain text literally.
+func Contains(text string) string {
+	return "%" + likeEscaper.Replace(text) + "%"
+}
@@ -0,0 +1,13 @@
+package helper_test
+
+import (
+	"project/helper"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContains(t *testing.T) {
+	assert.Equal(t, "%budi%", helper.Contains("budi"))
+	assert.Equal(t, `%100\%\_off\\%`, helper.Contains(`100%_off\`))
+}
@@ -0,0 +1,164 @@
+package helper
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"project/config"
+	"project/domain"
+	"strconv"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// OrderDocumentPDF renders one page per order, so a list of orders for the
+// day's shipping ends up in a single printable file.
+func OrderDocumentPDF(store config.StoreConfig, document domain.OrderDocument, orders []domain.OrderTotal) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(15, 15, 15)
+	pdf.SetAutoPageBreak(true, 15)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	for _, order := range orders {
+		pdf.AddPage()
+		documentHeader(pdf, store, tr)
+
+		switch document {
+		case domain.Invoice:
+			invoicePage(pdf, order, tr)
+		case domain.PackingSlip:
+			packingSlipPage(pdf, order, tr)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("could not render %s: %w", document, err)
+	}
+	return buf.Bytes(), nil
+}
+
+func documentHeader(pdf *fpdf.Fpdf, store config.StoreConfig, tr func(string) string) {
+	left, top, _, _ := pdf.GetMargins()
+	textX := left
+	if _, err := os.Stat(store.LogoPath); store.LogoPath != "" && err == nil {
+		pdf.ImageOptions(store.LogoPath, left, top, 0, 20, false, fpdf.ImageOptions{ReadDpi: true}, 0, "")
+		textX = left + 25
+	}
+
+	pdf.SetXY(textX, top)
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.CellFormat(0, 7, tr(store.Name), "", 2, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 9)
+	for _, line := range []string{store.Address, store.Phone} {
+		if line != "" {
+			pdf.CellFormat(0, 5, tr(line), "", 2, "L", false, 0, "")
+		}
+	}
+
+	pdf.SetXY(left, top+25)
+	pageWidth, _ := pdf.GetPageSize()
+	pdf.Line(left, top+23, pageWidth-left, top+23)
+}
+
+func invoicePage(pdf *fpdf.Fpdf, order domain.OrderTotal, tr func(string) string) {
+	pdf.SetFont("Helvetica", "B", 18)
+	pdf.CellFormat(0, 10, "INVOICE", "", 1, "L", false, 0, "")
+
+	documentInfo(pdf, tr, [][2]string{
+		{"Invoice number", order.InvoiceCode()},
+		{"Order", "#" + strconv.Itoa(int(order.ID))},
+		{"Date", order.CreatedAt.Format("02 Jan 2006")},
+		{"Payment method", order.PaymentMethod},
+		{"Bill to", order.CustomerName},
+		{"Address", order.CustomerAddress},
+	})
+
+	widths := []float64{95, 20, 32.5, 32.5}
+	tableRow(pdf, tr, widths, true, "Product", "Qty", "Unit price", "Subtotal")
+	for _, item := range order.Items {
+		tableRow(pdf, tr, widths, false,
+			item.ProductName,
+			strconv.Itoa(int(item.Quantity)),
+			Rupiah(float64(item.UnitPrice)),
+			Rupiah(float64(item.Subtotal)))
+	}
+
+	pdf.Ln(2)
+	totals := [][2]string{{"Subtotal", Rupiah(float64(order.Subtotal))}}
+	if order.Discount > 0 {
+		totals = append(totals, [2]string{"Discount", "- " + Rupiah(float64(order.Discount))})
+	}
+	totals = append(totals, [2]string{"Total", Rupiah(float64(order.Total))})
+	for i, total := range totals {
+		style := ""
+		if i == len(totals)-1 {
+			style = "B"
+		}
+		pdf.SetFont("Helvetica", style, 10)
+		pdf.CellFormat(147.5, 7, total[0], "", 0, "R", false, 0, "")
+		pdf.CellFormat(32.5, 7, tr(total[1]), "", 1, "R", false, 0, "")
+	}
+}
+
+func packingSlipPage(pdf *fpdf.Fpdf, order domain.OrderTotal, tr func(string) string) {
+	pdf.SetFont("Helvetica", "B", 18)
+	pdf.CellFormat(0, 10, "PACKING SLIP", "", 1, "L", false, 0, "")
+
+	documentInfo(pdf, tr, [][2]string{
+		{"Order", "#" + strconv.Itoa(int(order.ID))},
+		{"Date", order.CreatedAt.Format("02 Jan 2006")},
+		{"Ship to", order.CustomerName},
+		{"Address", order.CustomerAddress},
+		{"Tracking number", order.TrackingNumber},
+	})
+
+	widths := []float64{20, 140, 20}
+	tableRow(pdf, tr, widths, true, "Packed", "Product", "Qty")
+	for _, item := range order.Items {
+		tableRow(pdf, tr, widths, false, "", item.ProductName, strconv.Itoa(int(item.Quantity)))
+	}
+}
+
+func documentInfo(pdf *fpdf.Fpdf, tr func(string) string, rows [][2]string) {
+	for _, row := range rows {
+		if row[1] == "" {
+			continue
+		}
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(40, 6, row[0], "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.MultiCell(0, 6, tr(row[1]), "", "L", false)
+	}
+	pdf.Ln(4)
+}
+
+func tableRow(pdf *fpdf.Fpdf, tr func(string) string, widths []float64, header bool, cells ...string) {
+	style := ""
+	if header {
+		style = "B"
+		pdf.SetFillColor(230, 230, 230)
+	}
+	pdf.SetFont("Helvetica", style, 10)
+	for i, cell := range cells {
+		align := "R"
+		if i == 0 || widths[i] > 50 {
+			align = "L"
+		}
+		pdf.CellFormat(widths[i], 7, tr(cell), "1", 0, align, header, 0, "")
+	}
+	pdf.Ln(-1)
+}
+
+// Rupiah formats an amount the way it is printed on documents, e.g. Rp 1.250.000.
+func Rupiah(amount float64) string {
+	digits := strconv.FormatInt(int64(amount+0.5), 10)
+	var groups []string
+	for len(digits) > 3 {
+		groups = append([]string{digits[len(digits)-3:]}, groups...)
+		digits = digits[:len(digits)-3]
+	}
+	groups = append([]string{digits}, groups...)
+	return "Rp " + strings.Join(groups, ".")
+}
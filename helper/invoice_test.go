@@ -0,0 +1,47 @@
+package helper_test
+
+import (
+	"bytes"
+	"project/config"
+	"project/domain"
+	"project/helper"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRupiah(t *testing.T) {
+	assert.Equal(t, "Rp 0", helper.Rupiah(0))
+	assert.Equal(t, "Rp 950", helper.Rupiah(950))
+	assert.Equal(t, "Rp 1.250.000", helper.Rupiah(1250000))
+}
+
+func TestOrderDocumentPDF(t *testing.T) {
+	invoiceNumber := uint(12)
+	orders := []domain.OrderTotal{
+		{
+			ID:              1,
+			CustomerName:    "Customer Satu",
+			CustomerAddress: "Alamat Satu",
+			PaymentMethod:   "debit",
+			Subtotal:        300000,
+			Discount:        30000,
+			Total:           270000,
+			InvoiceNumber:   &invoiceNumber,
+			CreatedAt:       time.Date(2024, 12, 20, 0, 0, 0, 0, time.UTC),
+			Items: []domain.OrderItemSubtotal{
+				{ProductName: "Product A size: S color: Red", Quantity: 3, UnitPrice: 100000, Subtotal: 300000},
+			},
+		},
+		{ID: 2, CustomerName: "Customer Dua", CustomerAddress: "Alamat Dua"},
+	}
+
+	for _, document := range []domain.OrderDocument{domain.Invoice, domain.PackingSlip} {
+		pdf, err := helper.OrderDocumentPDF(config.StoreConfig{Name: "Toko Satu"}, document, orders)
+
+		assert.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF-")))
+		assert.Equal(t, 2, bytes.Count(pdf, []byte("/Type /Page\n")))
+	}
+}
@@ -0,0 +1,133 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"path/filepath"
+	"strings"
+
+	"github.com/HugoSmits86/nativewebp"
+	"github.com/gabriel-vasile/mimetype"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// ErrInvalidImage is wrapped by the errors of uploads that aren't acceptable
+// images, as opposed to failures of the storage.
+var ErrInvalidImage = errors.New("invalid image")
+
+// Limits bound what an uploaded image may be.
+type Limits struct {
+	MaxBytes  int64
+	MaxWidth  int
+	MaxHeight int
+}
+
+var DefaultLimits = Limits{MaxBytes: 5 << 20, MaxWidth: 6000, MaxHeight: 6000}
+
+// Size is a rendition generated for every image, it fits in a square of the
+// given width and is never larger than the original.
+type Size struct {
+	Name  string
+	Width int
+}
+
+var Sizes = []Size{
+	{Name: "thumbnail", Width: 150},
+	{Name: "medium", Width: 600},
+	{Name: "large", Width: 1200},
+}
+
+// AllowedTypes are the image types accepted by content, whatever the name or
+// the content type the client sent.
+var AllowedTypes = []string{"image/jpeg", "image/png", "image/gif", "image/webp"}
+
+// Rendition is an encoded image ready to be stored.
+type Rendition struct {
+	Size   string
+	Name   string
+	Data   []byte
+	Width  int
+	Height int
+}
+
+// Process validates an uploaded image and returns its WebP renditions.
+func Process(name string, data []byte, limits Limits) ([]Rendition, error) {
+	img, err := Decode(data, limits)
+	if err != nil {
+		return nil, err
+	}
+
+	base := strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))
+
+	renditions := make([]Rendition, 0, len(Sizes))
+	for _, size := range Sizes {
+		resized := Fit(img, size.Width)
+		var buf bytes.Buffer
+		if err := nativewebp.Encode(&buf, resized, nil); err != nil {
+			return nil, fmt.Errorf("failed to encode %s rendition: %w", size.Name, err)
+		}
+		renditions = append(renditions, Rendition{
+			Size:   size.Name,
+			Name:   base + "-" + size.Name + ".webp",
+			Data:   buf.Bytes(),
+			Width:  resized.Bounds().Dx(),
+			Height: resized.Bounds().Dy(),
+		})
+	}
+	return renditions, nil
+}
+
+// Decode checks the size, type and dimensions of an uploaded image before
+// decoding it. The dimensions are read from the header first so oversized
+// images are refused without being decoded.
+func Decode(data []byte, limits Limits) (image.Image, error) {
+	if limits.MaxBytes > 0 && int64(len(data)) > limits.MaxBytes {
+		return nil, fmt.Errorf("%w: file is larger than %d bytes", ErrInvalidImage, limits.MaxBytes)
+	}
+
+	mime := mimetype.Detect(data)
+	if !mimetype.EqualsAny(mime.String(), AllowedTypes...) {
+		return nil, fmt.Errorf("%w: %s is not an accepted image type", ErrInvalidImage, mime.String())
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+	if (limits.MaxWidth > 0 && config.Width > limits.MaxWidth) || (limits.MaxHeight > 0 && config.Height > limits.MaxHeight) {
+		return nil, fmt.Errorf("%w: %dx%d is larger than %dx%d", ErrInvalidImage, config.Width, config.Height, limits.MaxWidth, limits.MaxHeight)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+	return img, nil
+}
+
+// Fit scales an image down to fit in a square of the given width keeping its
+// aspect ratio, smaller images are returned as they are.
+func Fit(img image.Image, width int) image.Image {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w <= width && h <= width {
+		return img
+	}
+
+	if w >= h {
+		h = max(1, h*width/w)
+		w = width
+	} else {
+		w = max(1, w*width/h)
+		h = width
+	}
+	resized := image.NewNRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(resized, resized.Bounds(), img, bounds, draw.Over, nil)
+	return resized
+}
@@ -0,0 +1,142 @@
+package imaging_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"project/imaging"
+	"project/storage"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/image/webp"
+)
+
+func pngImage(t *testing.T, width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		img.Set(x, x%height, color.RGBA{G: 180, A: 255})
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func fileHeaders(t *testing.T, files map[string][]byte, names ...string) []*multipart.FileHeader {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for _, name := range names {
+		part, err := writer.CreateFormFile("images", name)
+		assert.NoError(t, err)
+		part.Write(files[name])
+	}
+	writer.Close()
+
+	request := httptest.NewRequest(http.MethodPost, "/", body)
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+	assert.NoError(t, request.ParseMultipartForm(1<<20))
+	return request.MultipartForm.File["images"]
+}
+
+func TestDecode(t *testing.T) {
+	limits := imaging.Limits{MaxBytes: 1 << 20, MaxWidth: 500, MaxHeight: 400}
+
+	t.Run("Successfully decode a JPEG", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.NoError(t, jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 30, 20)), nil))
+
+		img, err := imaging.Decode(buf.Bytes(), limits)
+		assert.NoError(t, err)
+		assert.Equal(t, 30, img.Bounds().Dx())
+	})
+
+	t.Run("Refuse a file that isn't an image whatever its name", func(t *testing.T) {
+		_, err := imaging.Decode([]byte("<html><script>alert(1)</script></html>"), limits)
+		assert.True(t, errors.Is(err, imaging.ErrInvalidImage))
+		assert.ErrorContains(t, err, "text/html")
+	})
+
+	t.Run("Refuse a file that is too large", func(t *testing.T) {
+		_, err := imaging.Decode(pngImage(t, 50, 50), imaging.Limits{MaxBytes: 10})
+		assert.True(t, errors.Is(err, imaging.ErrInvalidImage))
+	})
+
+	t.Run("Refuse an image with too many pixels", func(t *testing.T) {
+		_, err := imaging.Decode(pngImage(t, 600, 10), limits)
+		assert.True(t, errors.Is(err, imaging.ErrInvalidImage))
+		assert.ErrorContains(t, err, "600x10")
+	})
+}
+
+func TestFit(t *testing.T) {
+	landscape := image.NewRGBA(image.Rect(0, 0, 1000, 500))
+	assert.Equal(t, image.Rect(0, 0, 150, 75), imaging.Fit(landscape, 150).Bounds())
+
+	portrait := image.NewRGBA(image.Rect(0, 0, 300, 1200))
+	assert.Equal(t, image.Rect(0, 0, 150, 600), imaging.Fit(portrait, 600).Bounds())
+
+	small := image.NewRGBA(image.Rect(0, 0, 100, 80))
+	assert.Same(t, small, imaging.Fit(small, 600))
+}
+
+func TestProcess(t *testing.T) {
+	renditions, err := imaging.Process("mango.png", pngImage(t, 800, 400), imaging.DefaultLimits)
+	assert.NoError(t, err)
+	assert.Len(t, renditions, 3)
+
+	widths := map[string]int{"thumbnail": 150, "medium": 600, "large": 800}
+	for _, rendition := range renditions {
+		assert.Equal(t, "mango-"+rendition.Size+".webp", rendition.Name)
+		img, err := webp.Decode(bytes.NewReader(rendition.Data))
+		assert.NoError(t, err)
+		assert.Equal(t, widths[rendition.Size], img.Bounds().Dx())
+		assert.Equal(t, rendition.Width, img.Bounds().Dx())
+	}
+}
+
+func TestPipeline(t *testing.T) {
+	files := map[string][]byte{
+		"apple.png":  pngImage(t, 200, 100),
+		"banana.png": pngImage(t, 100, 300),
+		"notes.txt":  []byte("not an image"),
+	}
+
+	t.Run("Successfully store images with their renditions", func(t *testing.T) {
+		fake := storage.NewFakeCDN()
+		defer fake.Close()
+		pipeline := imaging.NewPipeline(storage.NewCDN(fake.URL, fake.Client()), imaging.DefaultLimits)
+
+		uploads, err := pipeline.UploadAll(context.Background(), fileHeaders(t, files, "apple.png", "banana.png"))
+		assert.NoError(t, err)
+		assert.Len(t, uploads, 2)
+		assert.Equal(t, 8, fake.Len())
+
+		content, _ := fake.File(uploads[0].Key)
+		assert.Equal(t, files["apple.png"], content)
+		assert.Len(t, uploads[1].Renditions, 3)
+		assert.Contains(t, uploads[1].Renditions["thumbnail"], "banana-thumbnail.webp")
+
+		err = pipeline.Delete(context.Background(), uploads[0].Keys()...)
+		assert.ErrorIs(t, err, storage.ErrDeleteUnsupported)
+		assert.Equal(t, 8, fake.Len())
+	})
+
+	t.Run("Stored images are removed when one of them is invalid", func(t *testing.T) {
+		dir := t.TempDir()
+		pipeline := imaging.NewPipeline(storage.NewLocal(dir, "/uploads"), imaging.DefaultLimits)
+
+		_, err := pipeline.UploadAll(context.Background(), fileHeaders(t, files, "apple.png", "notes.txt"))
+		assert.True(t, errors.Is(err, imaging.ErrInvalidImage))
+		assert.ErrorContains(t, err, "notes.txt")
+		entries, _ := os.ReadDir(dir)
+		assert.Empty(t, entries)
+	})
+}
@@ -0,0 +1,125 @@
+package imaging
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"sync"
+
+	"project/storage"
+)
+
+// Upload is a stored image with its renditions.
+type Upload struct {
+	URL        string
+	Key        string
+	Renditions map[string]string
+	// Keys of the stored renditions, to delete them with the image.
+	RenditionKeys []string
+}
+
+// Pipeline validates uploaded images and stores them with their renditions.
+type Pipeline struct {
+	storage storage.Backend
+	limits  Limits
+}
+
+func NewPipeline(storage storage.Backend, limits Limits) *Pipeline {
+	return &Pipeline{storage: storage, limits: limits}
+}
+
+// UploadAll processes and stores the images concurrently and returns them in
+// the order they were given. When one of them fails every file already stored
+// is deleted again and the first error is returned.
+func (pipeline *Pipeline) UploadAll(ctx context.Context, files []*multipart.FileHeader) ([]Upload, error) {
+	uploads := make([]Upload, len(files))
+	errs := make([]error, len(files))
+
+	var wg sync.WaitGroup
+	for i, file := range files {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			uploads[i], errs[i] = pipeline.upload(ctx, file)
+		}()
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		for j := range uploads {
+			if errs[j] == nil {
+				pipeline.Delete(ctx, uploads[j].Keys()...)
+			}
+		}
+		return nil, fmt.Errorf("failed to upload %s: %w", files[i].Filename, err)
+	}
+	return uploads, nil
+}
+
+// Delete removes stored files, it keeps going when one of them fails and
+// returns the first error.
+func (pipeline *Pipeline) Delete(ctx context.Context, keys ...string) error {
+	var first error
+	for _, key := range keys {
+		if err := pipeline.storage.Delete(ctx, key); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// Keys returns the keys of the image and of all its renditions.
+func (upload Upload) Keys() []string {
+	keys := []string{}
+	if upload.Key != "" {
+		keys = append(keys, upload.Key)
+	}
+	return append(keys, upload.RenditionKeys...)
+}
+
+func (pipeline *Pipeline) upload(ctx context.Context, file *multipart.FileHeader) (Upload, error) {
+	if pipeline.limits.MaxBytes > 0 && file.Size > pipeline.limits.MaxBytes {
+		return Upload{}, fmt.Errorf("%w: file is larger than %d bytes", ErrInvalidImage, pipeline.limits.MaxBytes)
+	}
+	f, err := file.Open()
+	if err != nil {
+		return Upload{}, err
+	}
+	defer f.Close()
+	var reader io.Reader = f
+	if pipeline.limits.MaxBytes > 0 {
+		reader = io.LimitReader(f, pipeline.limits.MaxBytes+1)
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return Upload{}, err
+	}
+
+	renditions, err := Process(file.Filename, data, pipeline.limits)
+	if err != nil {
+		return Upload{}, err
+	}
+
+	upload := Upload{Renditions: map[string]string{}}
+	original, err := pipeline.storage.Put(ctx, file.Filename, bytes.NewReader(data))
+	if err != nil {
+		return Upload{}, err
+	}
+	upload.URL, upload.Key = original.URL, original.Key
+
+	for _, rendition := range renditions {
+		object, err := pipeline.storage.Put(ctx, rendition.Name, bytes.NewReader(rendition.Data))
+		if err != nil {
+			pipeline.Delete(ctx, upload.Keys()...)
+			return Upload{}, err
+		}
+		upload.Renditions[rendition.Size] = object.URL
+		upload.RenditionKeys = append(upload.RenditionKeys, object.Key)
+	}
+	return upload, nil
+}
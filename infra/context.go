@@ -1,13 +1,17 @@
 package infra
 
 import (
+	"project/analyzer"
+	"project/carrier"
 	"project/config"
 	"project/database"
 	"project/handler"
 	"project/log"
 	"project/middleware"
+	"project/payment"
 	"project/repository"
 	"project/service"
+	"project/storage"
 
 	"go.uber.org/zap"
 )
@@ -18,6 +22,7 @@ type ServiceContext struct {
 	Ctl        handler.Handler
 	Log        *zap.Logger
 	Middleware middleware.Middleware
+	Svc        service.Service
 }
 
 func NewServiceContext(migrateDb bool, seedDb bool) (*ServiceContext, error) {
@@ -49,13 +54,31 @@ func NewServiceContext(migrateDb bool, seedDb bool) (*ServiceContext, error) {
 	// instance repository
 	repo := repository.NewRepository(db, rdb, appConfig, logger)
 
+	// instance carriers and payment providers, the fake ones are only
+	// available while debugging
+	carriers := carrier.NewRegistry()
+	providers := payment.NewRegistry()
+	if appConfig.AppDebug {
+		carriers = carrier.NewRegistry(carrier.NewFake())
+		providers = payment.NewRegistry(payment.NewFake(appConfig.PaymentConfig.WebhookSecret))
+	}
+
+	// instance the storage uploaded files are kept in
+	backend, err := storage.New(appConfig.StorageConfig)
+	if err != nil {
+		return handlerError(err)
+	}
+
+	// instance the analyzer review comments are scored with
+	reviews := analyzer.NewLexicon()
+
 	// instance service
-	service := service.NewService(repo, logger)
+	service := service.NewService(repo, appConfig, carriers, providers, backend, reviews, logger)
 
 	// instance controller
 	Ctl := handler.NewHandler(service, logger)
 
 	mw := middleware.NewMiddleware(rdb, appConfig.AppSecret)
 
-	return &ServiceContext{Cacher: rdb, Cfg: appConfig, Ctl: *Ctl, Log: logger, Middleware: mw}, nil
+	return &ServiceContext{Cacher: rdb, Cfg: appConfig, Ctl: *Ctl, Log: logger, Middleware: mw, Svc: service}, nil
 }
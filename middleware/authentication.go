@@ -4,14 +4,13 @@ import (
 	"github.com/gin-gonic/gin"
 	"net/http"
 	"project/handler"
+	"strconv"
+	"strings"
 )
 
 func (m *Middleware) Authentication() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		token := c.GetHeader("token")
-		isValid, _ := validateToken(token, m.secretKey)
-
-		if !isValid {
+		if !m.identify(c) {
 			handler.BadResponse(c, "Unauthorized", http.StatusUnauthorized)
 			c.Abort()
 			return
@@ -20,3 +19,30 @@ func (m *Middleware) Authentication() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// Identify sets the user and role of a valid token like Authentication does,
+// but lets anonymous requests through so public endpoints can tell them apart.
+func (m *Middleware) Identify() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		m.identify(c)
+		c.Next()
+	}
+}
+
+func (m *Middleware) identify(c *gin.Context) bool {
+	token := c.GetHeader("token")
+	isValid, data := validateToken(token, m.secretKey)
+	if !isValid {
+		return false
+	}
+
+	// token data is "id:role:issued at"
+	userData := strings.Split(data, ":")
+	userId, err := strconv.ParseUint(userData[0], 10, 64)
+	if len(userData) != 3 || err != nil {
+		return false
+	}
+	c.Set(handler.ContextUserID, uint(userId))
+	c.Set(handler.ContextRole, userData[1])
+	return true
+}
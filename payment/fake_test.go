@@ -0,0 +1,42 @@
+package payment_test
+
+import (
+	"errors"
+	"project/payment"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeProvider(t *testing.T) {
+	fake := payment.NewFake("secret")
+	payload := []byte(`{"reference": "PAY0000070001", "status": "paid", "amount": 150000}`)
+
+	t.Run("Successfully create a charge", func(t *testing.T) {
+		charge, err := fake.CreateCharge(payment.ChargeRequest{OrderID: 7, Amount: 150000})
+		assert.NoError(t, err)
+		assert.Equal(t, "PAY0000070001", charge.Reference)
+	})
+
+	t.Run("Successfully parse a signed webhook", func(t *testing.T) {
+		event, err := fake.ParseWebhook(payload, fake.Sign(payload))
+		assert.NoError(t, err)
+		assert.Equal(t, payment.Event{Reference: "PAY0000070001", Status: payment.Paid, Amount: 150000}, event)
+	})
+
+	t.Run("Failed to parse a webhook with a wrong signature", func(t *testing.T) {
+		_, err := fake.ParseWebhook(payload, payment.Sign(payload, "other"))
+		assert.True(t, errors.Is(err, payment.ErrInvalidSignature))
+	})
+
+	t.Run("Failed to parse a webhook with an unknown status", func(t *testing.T) {
+		body := []byte(`{"reference": "PAY0000070001", "status": "settled"}`)
+		_, err := fake.ParseWebhook(body, fake.Sign(body))
+		assert.Error(t, err)
+	})
+
+	t.Run("Failed to verify without a secret", func(t *testing.T) {
+		_, err := payment.NewFake("").ParseWebhook(payload, payment.Sign(payload, ""))
+		assert.Error(t, err)
+	})
+}
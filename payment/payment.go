@@ -0,0 +1,81 @@
+package payment
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+type Status string
+
+const (
+	Paid   Status = "paid"
+	Failed Status = "failed"
+)
+
+var ErrInvalidSignature = errors.New("invalid signature")
+
+type ChargeRequest struct {
+	OrderID uint
+	Amount  float64
+	Method  string
+}
+
+type Charge struct {
+	Reference  string
+	PaymentURL string
+}
+
+// Event is the normalized content of a provider webhook call.
+type Event struct {
+	Reference string
+	Status    Status
+	Amount    float64
+}
+
+// PaymentProvider is implemented by every payment gateway the store can charge
+// orders with.
+type PaymentProvider interface {
+	Name() string
+	CreateCharge(request ChargeRequest) (Charge, error)
+	// ParseWebhook verifies the signature of a webhook call and returns its event.
+	ParseWebhook(payload []byte, signature string) (Event, error)
+}
+
+type Registry map[string]PaymentProvider
+
+func NewRegistry(providers ...PaymentProvider) Registry {
+	registry := Registry{}
+	for _, provider := range providers {
+		registry[provider.Name()] = provider
+	}
+	return registry
+}
+
+func (registry Registry) Get(name string) (PaymentProvider, error) {
+	provider, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown payment provider %q", name)
+	}
+	return provider, nil
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of the payload.
+func Sign(payload []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature of the payload in constant time.
+func Verify(payload []byte, signature, secret string) error {
+	if secret == "" {
+		return errors.New("webhook secret is not configured")
+	}
+	if !hmac.Equal([]byte(Sign(payload, secret)), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"project/database"
+	"project/domain"
+)
+
+// RepositoryBulkJob keeps the progress of bulk order jobs in the cache, they
+// expire together with the other cached entries.
+type RepositoryBulkJob interface {
+	Save(job domain.BulkJob) error
+	FindById(id string) (domain.BulkJob, error)
+}
+
+type repositoryBulkJob struct {
+	cacher database.Cacher
+}
+
+func NewRepositoryBulkJob(cacher database.Cacher) RepositoryBulkJob {
+	return &repositoryBulkJob{cacher}
+}
+
+func (repo *repositoryBulkJob) Save(job domain.BulkJob) error {
+	value, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return repo.cacher.Set("bulk_job_"+job.ID, string(value))
+}
+
+func (repo *repositoryBulkJob) FindById(id string) (domain.BulkJob, error) {
+	value, err := repo.cacher.Get("bulk_job_" + id)
+	if err != nil {
+		return domain.BulkJob{}, errors.New("bulk job not found")
+	}
+
+	var job domain.BulkJob
+	if err := json.Unmarshal([]byte(value), &job); err != nil {
+		return domain.BulkJob{}, err
+	}
+	return job, nil
+}
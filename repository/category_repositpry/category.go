@@ -8,14 +8,23 @@ import (
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ProductCount counts the live products linked to each category.
+const ProductCount = `(SELECT COUNT(*) FROM product_categories pc
+	JOIN products p ON p.id = pc.product_id AND p.deleted_at IS NULL
+	WHERE pc.category_id = categories.id) AS product_count`
+
 type CategoryRepo interface {
 	CreateCategory(category *domain.Category) error
 	ShowAllCategory(page, limit int) (*[]domain.Category, int, int, error)
-	DeleteCategory(id int) error
+	DeleteCategory(id int, options domain.CategoryDelete) error
 	GetCategoryByID(id int) (*domain.Category, error)
 	UpdateCategory(id int, category *domain.Category) error
+	ShowCategoryProducts(id, page, limit int, subtree bool) (*[]domain.Product, int, int, error)
+	ShowCategoryTree() ([]*domain.Category, error)
+	MoveCategory(id int, move domain.CategoryMove) (*domain.Category, error)
 }
 
 type categoryRepo struct {
@@ -37,7 +46,9 @@ func (cr *categoryRepo) ShowAllCategory(page, limit int) (*[]domain.Category, in
 		return nil, 0, 0, err
 	}
 
-	result := cr.db.Scopes(helper.Paginate(uint(page), uint(limit))).Find(&category)
+	result := cr.db.Scopes(helper.Paginate(uint(page), uint(limit))).
+		Select("categories.*, " + ProductCount).
+		Find(&category)
 
 	if result.Error != nil {
 		return nil, 0, 0, result.Error
@@ -52,29 +63,95 @@ func (cr *categoryRepo) ShowAllCategory(page, limit int) (*[]domain.Category, in
 	return &category, int(count), totalPages, nil
 }
 
-func (cr *categoryRepo) DeleteCategory(id int) error {
+// DeleteCategory refuses to delete a category that still has products unless
+// forced. Forcing moves the products to options.ReassignTo, or falls back to
+// another of their categories as primary. Subcategories move up one level.
+func (cr *categoryRepo) DeleteCategory(id int, options domain.CategoryDelete) error {
 
-	result := cr.db.Delete(&domain.Category{}, id)
+	return cr.db.Transaction(func(tx *gorm.DB) error {
+		var linked int64
+		if err := tx.Model(&domain.ProductCategory{}).Where("category_id = ?", id).Count(&linked).Error; err != nil {
+			return err
+		}
 
-	if result.RowsAffected == 0 {
-		return fmt.Errorf("category not found")
+		if linked > 0 {
+			if !options.Force {
+				return fmt.Errorf("%w (%d), use force=true to reassign them", domain.ErrCategoryHasProducts, linked)
+			}
+			if err := cr.reassignProducts(tx, uint(id), options.ReassignTo); err != nil {
+				cr.log.Error("Error reassigning products", zap.Int("categoryID", id), zap.Error(err))
+				return err
+			}
+		}
+
+		// children move up to the parent of the deleted category
+		if err := tx.Model(&domain.Category{}).Where("parent_id = ?", id).
+			Update("parent_id", gorm.Expr("(SELECT parent_id FROM categories WHERE id = ?)", id)).Error; err != nil {
+			return err
+		}
+
+		result := tx.Delete(&domain.Category{}, id)
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("category not found")
+		}
+
+		return nil
+	})
+}
+
+func (cr *categoryRepo) reassignProducts(tx *gorm.DB, id uint, reassignTo *uint) error {
+	if reassignTo != nil {
+		var target domain.Category
+		if err := tx.First(&target, *reassignTo).Error; err != nil {
+			return fmt.Errorf("category to reassign to not found")
+		}
+
+		if err := tx.Exec(`
+			INSERT INTO product_categories (product_id, category_id, created_at)
+			SELECT product_id, ?, NOW() FROM product_categories WHERE category_id = ?
+			ON CONFLICT DO NOTHING`, target.ID, id).Error; err != nil {
+			return err
+		}
 	}
 
-	if result.Error != nil {
-		return result.Error
+	if err := tx.Where("category_id = ?", id).Delete(&domain.ProductCategory{}).Error; err != nil {
+		return err
 	}
 
-	return nil
+	return tx.Model(&domain.Product{}).Where("category_id = ?", id).
+		Update("category_id", gorm.Expr("(SELECT MIN(pc.category_id) FROM product_categories pc WHERE pc.product_id = products.id)")).Error
 }
 
+// CreateCategory adds a category after its last sibling, under ParentID when
+// it's set.
 func (cr *categoryRepo) CreateCategory(category *domain.Category) error {
 
-	err := cr.db.Create(category).Error
-	if err != nil {
-		return fmt.Errorf("failed to create category: %s", err)
-	}
+	return cr.db.Transaction(func(tx *gorm.DB) error {
+		siblings := tx.Model(&domain.Category{})
+		if category.ParentID != nil {
+			// the locked parent keeps concurrent children from taking the same position
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&domain.Category{}, *category.ParentID).Error; err != nil {
+				return fmt.Errorf("parent category not found")
+			}
+			siblings = siblings.Where("parent_id = ?", *category.ParentID)
+		} else {
+			siblings = siblings.Where("parent_id IS NULL")
+		}
 
-	return nil
+		if err := siblings.Select("COALESCE(MAX(position) + 1, 0)").Scan(&category.Position).Error; err != nil {
+			return fmt.Errorf("failed to create category: %s", err)
+		}
+
+		if err := tx.Create(category).Error; err != nil {
+			return fmt.Errorf("failed to create category: %s", err)
+		}
+		return nil
+	})
 }
 
 func (cr *categoryRepo) GetCategoryByID(id int) (*domain.Category, error) {
@@ -90,6 +167,93 @@ func (cr *categoryRepo) GetCategoryByID(id int) (*domain.Category, error) {
 		return nil, fmt.Errorf("category not found or already deleted")
 	}
 
+	breadcrumbs, err := cr.ancestors(cr.db, category.ID)
+	if err != nil {
+		cr.log.Error("Error fetching breadcrumbs", zap.Int("categoryID", id), zap.Error(err))
+		return nil, err
+	}
+	category.Breadcrumbs = breadcrumbs
+
+	return &category, nil
+}
+
+// ancestors returns the path from the root category down to the category.
+func (cr *categoryRepo) ancestors(tx *gorm.DB, id uint) ([]domain.CategoryCrumb, error) {
+	crumbs := []domain.CategoryCrumb{}
+	err := tx.Raw(`
+		WITH RECURSIVE path AS (
+			SELECT id, name, parent_id, 0 AS depth FROM categories WHERE id = ?
+			UNION ALL
+			SELECT c.id, c.name, c.parent_id, p.depth + 1 FROM categories c JOIN path p ON c.id = p.parent_id
+		)
+		SELECT id, name FROM path ORDER BY depth DESC`, id).
+		Scan(&crumbs).Error
+	return crumbs, err
+}
+
+func (cr *categoryRepo) ShowCategoryTree() ([]*domain.Category, error) {
+
+	categories := []domain.Category{}
+	if err := cr.db.Select("categories.*, " + ProductCount).Order("position, name").Find(&categories).Error; err != nil {
+		cr.log.Error("Error fetching categories", zap.Error(err))
+		return nil, err
+	}
+
+	return domain.BuildCategoryTree(categories), nil
+}
+
+// MoveCategory changes the parent of a category and renumbers the positions
+// of its new siblings.
+func (cr *categoryRepo) MoveCategory(id int, move domain.CategoryMove) (*domain.Category, error) {
+
+	category := domain.Category{}
+	err := cr.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&category, id).Error; err != nil {
+			return fmt.Errorf("category not found")
+		}
+
+		if move.ParentID != nil {
+			ancestors, err := cr.ancestors(tx, *move.ParentID)
+			if err != nil {
+				return err
+			}
+			if len(ancestors) == 0 {
+				return fmt.Errorf("parent category not found")
+			}
+			if err := move.CheckParent(category.ID, ancestors); err != nil {
+				return err
+			}
+		}
+
+		siblings := []*domain.Category{}
+		query := tx.Where("id <> ?", category.ID).Order("position, name")
+		if move.ParentID != nil {
+			query = query.Where("parent_id = ?", *move.ParentID)
+		} else {
+			query = query.Where("parent_id IS NULL")
+		}
+		if err := query.Find(&siblings).Error; err != nil {
+			return err
+		}
+
+		category.ParentID = move.ParentID
+		for _, sibling := range move.Reorder(&category, siblings) {
+			updates := map[string]interface{}{"position": sibling.Position}
+			if sibling.ID == category.ID {
+				updates["parent_id"] = category.ParentID
+			}
+			if err := tx.Model(&domain.Category{}).Where("id = ?", sibling.ID).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		cr.log.Error("Error moving category", zap.Int("categoryID", id), zap.Error(err))
+		return nil, err
+	}
+
 	return &category, nil
 }
 
@@ -109,3 +273,44 @@ func (cr *categoryRepo) UpdateCategory(id int, category *domain.Category) error
 
 	return nil
 }
+
+// ShowCategoryProducts pages through the products of a category, including
+// the products of its descendants when subtree is set.
+func (cr *categoryRepo) ShowCategoryProducts(id, page, limit int, subtree bool) (*[]domain.Product, int, int, error) {
+
+	if _, err := cr.GetCategoryByID(id); err != nil {
+		return nil, 0, 0, err
+	}
+
+	products := []domain.Product{}
+	var count int64
+
+	var categories interface{} = []int{id}
+	if subtree {
+		categories = helper.CategorySubtree(cr.db, uint(id))
+	}
+	inCategory := cr.db.Model(&domain.Product{}).
+		Scopes(helper.ActiveProducts).
+		Where("EXISTS (SELECT 1 FROM product_categories pc WHERE pc.product_id = products.id AND pc.category_id IN (?))", categories).
+		Session(&gorm.Session{})
+
+	if err := inCategory.Count(&count).Error; err != nil {
+		cr.log.Error("Error counting category products", zap.Int("categoryID", id), zap.Error(err))
+		return nil, 0, 0, err
+	}
+
+	result := inCategory.Scopes(helper.Paginate(uint(page), uint(limit))).
+		Preload("ProductVariant").
+		Preload("Image", helper.ImagesInOrder).
+		Order("products.id").
+		Find(&products)
+
+	if result.Error != nil {
+		cr.log.Error("Error fetching category products", zap.Int("categoryID", id), zap.Error(result.Error))
+		return nil, 0, 0, result.Error
+	}
+
+	totalPages := int(math.Ceil(float64(count) / float64(limit)))
+
+	return &products, int(count), totalPages, nil
+}
@@ -18,8 +18,8 @@ func (cr *CategoryRepoMock) ShowAllCategory(page, limit int) (*[]domain.Category
 	return nil, 0, 0, args.Error(3)
 }
 
-func (cr *CategoryRepoMock) DeleteCategory(id int) error {
-	args := cr.Called(id)
+func (cr *CategoryRepoMock) DeleteCategory(id int, options domain.CategoryDelete) error {
+	args := cr.Called(id, options)
 	return args.Error(0)
 }
 
@@ -46,3 +46,27 @@ func (cr *CategoryRepoMock) UpdateCategory(id int, category *domain.Category) er
 	}
 	return nil
 }
+
+func (cr *CategoryRepoMock) ShowCategoryProducts(id, page, limit int, subtree bool) (*[]domain.Product, int, int, error) {
+	args := cr.Called(id, page, limit, subtree)
+	if products, ok := args.Get(0).(*[]domain.Product); ok {
+		return products, args.Int(1), args.Int(2), args.Error(3)
+	}
+	return nil, 0, 0, args.Error(3)
+}
+
+func (cr *CategoryRepoMock) ShowCategoryTree() ([]*domain.Category, error) {
+	args := cr.Called()
+	if tree, ok := args.Get(0).([]*domain.Category); ok {
+		return tree, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (cr *CategoryRepoMock) MoveCategory(id int, move domain.CategoryMove) (*domain.Category, error) {
+	args := cr.Called(id, move)
+	if category, ok := args.Get(0).(*domain.Category); ok {
+		return category, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
@@ -27,7 +27,7 @@ func TestShowAllCategory(t *testing.T) {
 		mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "categories"`)).
 			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
 
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "categories" LIMIT $1`)).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT categories.*, ` + categoryrepositpry.ProductCount + ` FROM "categories" WHERE "categories"."deleted_at" IS NULL LIMIT $1`)).
 			WithArgs(limit).
 			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
 				AddRow(1, "Category 1").
@@ -50,7 +50,7 @@ func TestShowAllCategory(t *testing.T) {
 		mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "categories"`)).
 			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
 
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "categories" LIMIT $1`)).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT categories.*, ` + categoryrepositpry.ProductCount + ` FROM "categories" WHERE "categories"."deleted_at" IS NULL LIMIT $1`)).
 			WithArgs(limit).
 			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
 
@@ -84,7 +84,7 @@ func TestShowAllCategory(t *testing.T) {
 		mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "categories"`)).
 			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
 
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "categories" LIMIT $1 OFFSET $2`)).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT categories.*, `+categoryrepositpry.ProductCount+` FROM "categories" WHERE "categories"."deleted_at" IS NULL LIMIT $1 OFFSET $2`)).
 			WithArgs(limit, (page-1)*limit).
 			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
 				AddRow(3, "Category 3").
@@ -113,12 +113,18 @@ func TestDeleteCategory(t *testing.T) {
 		id := 1
 
 		mock.ExpectBegin()
-		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "categories" WHERE "categories"."id" = $1`)).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "product_categories" WHERE category_id = $1`)).
 			WithArgs(id).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "categories" SET "parent_id"=(SELECT parent_id FROM categories WHERE id = $1),"updated_at"=$2 WHERE parent_id = $3`)).
+			WithArgs(id, sqlmock.AnyArg(), id).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "categories" SET "deleted_at"=$1 WHERE "categories"."id" = $2 AND "categories"."deleted_at" IS NULL`)).
+			WithArgs(sqlmock.AnyArg(), id).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 		mock.ExpectCommit()
 
-		err := categoryRepo.DeleteCategory(id)
+		err := categoryRepo.DeleteCategory(id, domain.CategoryDelete{})
 
 		assert.NoError(t, err)
 	})
@@ -127,16 +133,37 @@ func TestDeleteCategory(t *testing.T) {
 		id := 999
 
 		mock.ExpectBegin()
-		mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "categories" WHERE "categories"."id" = $1`)).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "product_categories" WHERE category_id = $1`)).
 			WithArgs(id).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "categories" SET "parent_id"=(SELECT parent_id FROM categories WHERE id = $1),"updated_at"=$2 WHERE parent_id = $3`)).
+			WithArgs(id, sqlmock.AnyArg(), id).
 			WillReturnResult(sqlmock.NewResult(0, 0))
-		mock.ExpectCommit()
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "categories" SET "deleted_at"=$1 WHERE "categories"."id" = $2 AND "categories"."deleted_at" IS NULL`)).
+			WithArgs(sqlmock.AnyArg(), id).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
 
-		err := categoryRepo.DeleteCategory(id)
+		err := categoryRepo.DeleteCategory(id, domain.CategoryDelete{})
 
 		assert.Error(t, err)
 		assert.EqualError(t, err, "category not found")
 	})
+
+	t.Run("Failed to delete category that still has products", func(t *testing.T) {
+		id := 2
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "product_categories" WHERE category_id = $1`)).
+			WithArgs(id).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+		mock.ExpectRollback()
+
+		err := categoryRepo.DeleteCategory(id, domain.CategoryDelete{})
+
+		assert.ErrorIs(t, err, domain.ErrCategoryHasProducts)
+		assert.EqualError(t, err, "category still has products (3), use force=true to reassign them")
+	})
 }
 
 func TestCreateCategory(t *testing.T) {
@@ -153,10 +180,16 @@ func TestCreateCategory(t *testing.T) {
 		}
 
 		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT COALESCE(MAX(position) + 1, 0) FROM "categories" WHERE parent_id IS NULL AND "categories"."deleted_at" IS NULL`)).
+			WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(3))
 		mock.ExpectQuery((`INSERT INTO "categories"`)).
 			WithArgs(
 				category.Name,
 				category.Icon,
+				category.ParentID,
+				3,
+				sqlmock.AnyArg(),
+				sqlmock.AnyArg(),
 				sqlmock.AnyArg(),
 				sqlmock.AnyArg()).
 			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
@@ -166,6 +199,26 @@ func TestCreateCategory(t *testing.T) {
 
 		// Assertions
 		assert.NoError(t, err)
+		assert.Equal(t, 3, category.Position)
+	})
+
+	t.Run("Failed to create category under a missing parent", func(t *testing.T) {
+		parent := uint(99)
+		category := &domain.Category{
+			Name:     "New Category",
+			Icon:     "http//skall.jpg",
+			ParentID: &parent,
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "categories" WHERE "categories"."id" = $1 AND "categories"."deleted_at" IS NULL ORDER BY "categories"."id" LIMIT $2 FOR UPDATE`)).
+			WithArgs(parent, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectRollback()
+
+		err := categoryRepo.CreateCategory(category)
+
+		assert.EqualError(t, err, "parent category not found")
 	})
 
 	t.Run("Failed to create category due to database error", func(t *testing.T) {
@@ -175,10 +228,16 @@ func TestCreateCategory(t *testing.T) {
 		}
 
 		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT COALESCE(MAX(position) + 1, 0) FROM "categories" WHERE parent_id IS NULL AND "categories"."deleted_at" IS NULL`)).
+			WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(0))
 		mock.ExpectQuery(`INSERT INTO "categories"`).
 			WithArgs(
 				category.Name,
 				category.Icon,
+				category.ParentID,
+				0,
+				sqlmock.AnyArg(),
+				sqlmock.AnyArg(),
 				sqlmock.AnyArg(),
 				sqlmock.AnyArg()).
 			WillReturnError(fmt.Errorf("database error"))
@@ -208,12 +267,19 @@ func TestGetCategoryByID(t *testing.T) {
 			WithArgs(1).
 			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(category.ID, category.Name))
 
+		mock.ExpectQuery(`WITH RECURSIVE path AS`).
+			WithArgs(category.ID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+				AddRow(4, "Fruits").
+				AddRow(category.ID, category.Name))
+
 		result, err := categoryRepo.GetCategoryByID(1)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
 		assert.Equal(t, category.ID, result.ID)
 		assert.Equal(t, category.Name, result.Name)
+		assert.Equal(t, []domain.CategoryCrumb{{ID: 4, Name: "Fruits"}, {ID: 1, Name: "Category 1"}}, result.Breadcrumbs)
 	})
 
 	t.Run("Category not found", func(t *testing.T) {
@@ -255,7 +321,7 @@ func TestUpdateCategory(t *testing.T) {
 		}
 
 		mock.ExpectBegin()
-		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "categories" SET "name"=$1,"updated_at"=$2 WHERE id = $3 AND "id" = $4`)).
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "categories" SET "name"=$1,"updated_at"=$2 WHERE id = $3 AND "categories"."deleted_at" IS NULL AND "id" = $4`)).
 			WithArgs(
 				category.Name,
 				sqlmock.AnyArg(),
@@ -277,7 +343,7 @@ func TestUpdateCategory(t *testing.T) {
 		}
 
 		mock.ExpectBegin()
-		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "categories" SET "name"=$1,"updated_at"=$2 WHERE id = $3 AND "id" = $4`)).
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "categories" SET "name"=$1,"updated_at"=$2 WHERE id = $3 AND "categories"."deleted_at" IS NULL AND "id" = $4`)).
 			WithArgs(
 				category.Name,
 				sqlmock.AnyArg(),
@@ -300,7 +366,7 @@ func TestUpdateCategory(t *testing.T) {
 		}
 
 		mock.ExpectBegin()
-		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "categories" SET "name"=$1,"updated_at"=$2 WHERE id = $3 AND "id" = $4`)).
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "categories" SET "name"=$1,"updated_at"=$2 WHERE id = $3 AND "categories"."deleted_at" IS NULL AND "id" = $4`)).
 			WithArgs(
 				category.Name,
 				sqlmock.AnyArg(),
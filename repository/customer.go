@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"project/domain"
+	"project/helper"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type RepositoryCustomer interface {
+	FindAll(page, limit uint, filter domain.CustomerFilter) (int, int, []domain.Customer, error)
+	FindById(id uint) (domain.Customer, error)
+	Insert(customer *domain.Customer) error
+	Update(customer *domain.Customer) error
+	Delete(id uint) error
+	Metrics(id uint) (domain.CustomerMetrics, error)
+}
+
+type repositoryCustomer struct {
+	db  *gorm.DB
+	log *zap.Logger
+}
+
+func NewRepositoryCustomer(db *gorm.DB, log *zap.Logger) RepositoryCustomer {
+	return &repositoryCustomer{db, log}
+}
+
+func (repo *repositoryCustomer) FindAll(page, limit uint, filter domain.CustomerFilter) (int, int, []domain.Customer, error) {
+	query := repo.db.Model(&domain.Customer{})
+	if filter.Search != "" {
+		search := helper.Contains(filter.Search)
+		query = query.Where("name ILIKE ? OR email ILIKE ? OR phone ILIKE ?", search, search, search)
+	}
+	if filter.Tag != "" {
+		tag, _ := json.Marshal([]string{filter.Tag})
+		query = query.Where("tags @> ?", string(tag))
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		repo.log.Error("Error counting customers", zap.Error(err))
+		return 0, 0, nil, err
+	}
+	pages := int(math.Ceil(float64(count) / float64(limit)))
+
+	customers := []domain.Customer{}
+	if err := query.Scopes(helper.Paginate(page, limit)).Order("name").Find(&customers).Error; err != nil {
+		repo.log.Error("Error fetching customers", zap.Error(err))
+		return 0, 0, nil, err
+	}
+	return int(count), pages, customers, nil
+}
+
+func (repo *repositoryCustomer) FindById(id uint) (domain.Customer, error) {
+	var customer domain.Customer
+	if err := repo.db.Preload("Addresses", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).First(&customer, id).Error; err != nil {
+		return domain.Customer{}, errors.New("customer not found")
+	}
+	return customer, nil
+}
+
+func (repo *repositoryCustomer) Insert(customer *domain.Customer) error {
+	if err := repo.db.Create(customer).Error; err != nil {
+		repo.log.Error("Error creating customer", zap.Error(err))
+		return errors.New("failed to create customer")
+	}
+	return nil
+}
+
+// Update replaces the customer fields and its addresses.
+func (repo *repositoryCustomer) Update(customer *domain.Customer) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		var current domain.Customer
+		if err := tx.First(&current, customer.ID).Error; err != nil {
+			return errors.New("customer not found")
+		}
+
+		if err := tx.Model(customer).Select("name", "email", "phone", "address", "tags", "notes").Updates(customer).Error; err != nil {
+			repo.log.Error("Error updating customer", zap.Uint("id", customer.ID), zap.Error(err))
+			return errors.New("failed to update customer")
+		}
+
+		if err := tx.Where("customer_id = ?", customer.ID).Delete(&domain.CustomerAddress{}).Error; err != nil {
+			return err
+		}
+		if len(customer.Addresses) == 0 {
+			return nil
+		}
+		for i := range customer.Addresses {
+			customer.Addresses[i].ID = 0
+			customer.Addresses[i].CustomerID = customer.ID
+		}
+		return tx.Create(&customer.Addresses).Error
+	})
+}
+
+func (repo *repositoryCustomer) Delete(id uint) error {
+	var count int64
+	if err := repo.db.Model(&domain.Order{}).Where("customer_id = ?", id).Count(&count).Error; err != nil {
+		repo.log.Error("Error counting customer orders", zap.Uint("id", id), zap.Error(err))
+		return errors.New("failed to delete customer")
+	}
+	if count > 0 {
+		return errors.New("customer still has orders")
+	}
+
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("customer_id = ?", id).Delete(&domain.CustomerAddress{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&domain.Customer{}, id)
+		if result.Error != nil {
+			repo.log.Error("Error deleting customer", zap.Uint("id", id), zap.Error(result.Error))
+			return errors.New("failed to delete customer")
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("customer not found")
+		}
+		return nil
+	})
+}
+
+func (repo *repositoryCustomer) Metrics(id uint) (domain.CustomerMetrics, error) {
+	var metrics domain.CustomerMetrics
+	err := repo.db.Table("orders as o").
+		Select(`COUNT(o.id) as order_count,
+			COALESCE(SUM(items.total - o.discount), 0) as total_spend,
+			COALESCE(AVG(items.total - o.discount), 0) as average_order_value,
+			MIN(o.created_at) as first_order_at,
+			MAX(o.created_at) as last_order_at`).
+		Joins(`JOIN (
+			SELECT order_id, SUM(quantity * unit_price) as total
+			FROM order_items
+			GROUP BY order_id) items ON items.order_id = o.id`).
+		Where("o.customer_id = ? AND o.status <> ?", id, domain.Canceled).
+		Scan(&metrics).Error
+	if err != nil {
+		repo.log.Error("Error computing customer metrics", zap.Uint("id", id), zap.Error(err))
+		return domain.CustomerMetrics{}, err
+	}
+	return metrics, nil
+}
@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"errors"
+	"math"
+	"project/domain"
+	"project/helper"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RepositorySegment interface {
+	FindAll() ([]domain.CustomerSegment, error)
+	FindById(id uint) (domain.CustomerSegment, error)
+	Insert(segment *domain.CustomerSegment) error
+	Update(segment *domain.CustomerSegment) error
+	Delete(id uint) error
+	FindMembers(segment domain.CustomerSegment, page, limit uint) (int, int, []domain.SegmentMember, error)
+	RFMStats() ([]domain.CustomerRFM, error)
+	SaveRFM(rows []domain.CustomerRFM) error
+}
+
+type repositorySegment struct {
+	db  *gorm.DB
+	log *zap.Logger
+}
+
+func NewRepositorySegment(db *gorm.DB, log *zap.Logger) RepositorySegment {
+	return &repositorySegment{db, log}
+}
+
+func (repo *repositorySegment) FindAll() ([]domain.CustomerSegment, error) {
+	segments := []domain.CustomerSegment{}
+	if err := repo.db.Order("name").Find(&segments).Error; err != nil {
+		repo.log.Error("Error fetching segments", zap.Error(err))
+		return nil, errors.New("internal server error")
+	}
+	return segments, nil
+}
+
+func (repo *repositorySegment) FindById(id uint) (domain.CustomerSegment, error) {
+	var segment domain.CustomerSegment
+	if err := repo.db.First(&segment, id).Error; err != nil {
+		return domain.CustomerSegment{}, errors.New("segment not found")
+	}
+	return segment, nil
+}
+
+func (repo *repositorySegment) Insert(segment *domain.CustomerSegment) error {
+	if err := repo.db.Create(segment).Error; err != nil {
+		repo.log.Error("Error creating segment", zap.Error(err))
+		return errors.New("failed to create segment")
+	}
+	return nil
+}
+
+func (repo *repositorySegment) Update(segment *domain.CustomerSegment) error {
+	result := repo.db.Model(segment).Select("name", "description", "rule").Updates(segment)
+	if result.Error != nil {
+		repo.log.Error("Error updating segment", zap.Error(result.Error))
+		return errors.New("failed to update segment")
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("segment not found")
+	}
+	return nil
+}
+
+func (repo *repositorySegment) Delete(id uint) error {
+	var count int64
+	if err := repo.db.Model(&domain.Promotion{}).Where("segment_id = ?", id).Count(&count).Error; err != nil {
+		repo.log.Error("Error counting segment promotions", zap.Uint("id", id), zap.Error(err))
+		return errors.New("failed to delete segment")
+	}
+	if count > 0 {
+		return errors.New("segment is still used by promotions")
+	}
+
+	result := repo.db.Delete(&domain.CustomerSegment{}, id)
+	if result.Error != nil {
+		repo.log.Error("Error deleting segment", zap.Error(result.Error))
+		return errors.New("failed to delete segment")
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("segment not found")
+	}
+	return nil
+}
+
+func (repo *repositorySegment) FindMembers(segment domain.CustomerSegment, page, limit uint) (int, int, []domain.SegmentMember, error) {
+	rule, err := domain.ParseSegmentRule(segment.Rule)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	condition, args := rule.SQL("r")
+	query := repo.db.Table("customers as c").
+		Joins("JOIN customer_rfms as r ON r.customer_id = c.id").
+		Where(condition, args...)
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		repo.log.Error("Error counting segment members", zap.Uint("segment_id", segment.ID), zap.Error(err))
+		return 0, 0, nil, err
+	}
+	pages := int(math.Ceil(float64(count) / float64(limit)))
+
+	var members []domain.SegmentMember
+	result := query.Select(`c.id as customer_id, c.name, c.email, c.phone, c.tags,
+			r.recency_days, r.frequency, r.monetary, r.score, r.last_order_at`).
+		Scopes(helper.Paginate(page, limit)).
+		Order("r.monetary DESC").
+		Scan(&members)
+	if result.Error != nil {
+		repo.log.Error("Error fetching segment members", zap.Uint("segment_id", segment.ID), zap.Error(result.Error))
+		return 0, 0, nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return 0, 0, nil, errors.New("segment has no members")
+	}
+	return int(count), pages, members, nil
+}
+
+// RFMStats returns the last order date, order count and spend of every
+// customer over its completed orders, customers without any included.
+func (repo *repositorySegment) RFMStats() ([]domain.CustomerRFM, error) {
+	var rows []domain.CustomerRFM
+	err := repo.db.Table("customers as c").
+		Select(`c.id as customer_id, MAX(ot.created_at) as last_order_at,
+			COUNT(ot.id) as frequency, COALESCE(SUM(ot.total), 0) as monetary`).
+		Joins("LEFT JOIN order_totals as ot ON ot.customer_id = c.id AND ot.status IN ?",
+			[]domain.Status{domain.Completed, domain.Delivered}).
+		Group("c.id").
+		Scan(&rows).Error
+	if err != nil {
+		repo.log.Error("Error computing RFM statistics", zap.Error(err))
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (repo *repositorySegment) SaveRFM(rows []domain.CustomerRFM) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	return repo.db.Clauses(clause.OnConflict{UpdateAll: true}).CreateInBatches(rows, 500).Error
+}
+
+// segmentsOf reports which of the given segments a customer currently falls
+// within, the customer's RFM row and the segments are each read once.
+func segmentsOf(tx *gorm.DB, customerId uint, segmentIds []uint) (map[uint]bool, error) {
+	member := map[uint]bool{}
+	if len(segmentIds) == 0 {
+		return member, nil
+	}
+
+	var rfm []domain.CustomerRFM
+	if err := tx.Where("customer_id = ?", customerId).Limit(1).Find(&rfm).Error; err != nil {
+		return nil, err
+	}
+	if len(rfm) == 0 {
+		return member, nil
+	}
+
+	var segments []domain.CustomerSegment
+	if err := tx.Where("id IN ?", segmentIds).Find(&segments).Error; err != nil {
+		return nil, err
+	}
+	for _, segment := range segments {
+		rule, err := domain.ParseSegmentRule(segment.Rule)
+		if err != nil {
+			return nil, err
+		}
+		member[segment.ID] = rule.Match(rfm[0])
+	}
+	return member, nil
+}
@@ -2,6 +2,8 @@ package dashboardrepository
 
 import (
 	"project/domain"
+	"sort"
+	"strings"
 	"time"
 
 	"go.uber.org/zap"
@@ -25,6 +27,9 @@ func NewDashboardRepo(db *gorm.DB, log *zap.Logger) DashboardRepo {
 	return &dashboardRepo{db, log}
 }
 
+// fulfilledStatuses are the order statuses counted as earned revenue.
+var fulfilledStatuses = []domain.Status{domain.Completed, domain.Delivered}
+
 func StartOfMonth(t time.Time) time.Time {
 	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
 }
@@ -45,7 +50,7 @@ func (dr *dashboardRepo) GetEarningDashboard() (int, error) {
 	query := dr.db.Table("orders as o").
 		Select("SUM(oi.unit_price * oi.quantity) as total_amount").
 		Joins("JOIN order_items as oi ON oi.order_id = o.id").
-		Where("o.status = ?", "completed").
+		Where("o.status IN ?", fulfilledStatuses).
 		Where("o.created_at BETWEEN ? AND ?",
 			StartOfMonth(now),
 			EndOfMonth(now),
@@ -99,7 +104,7 @@ func (dr *dashboardRepo) GetBestSeller() ([]*domain.BestSeller, error) {
 		Select("oi.variant_id as product_id, SUM(oi.quantity) as total_sold").
 		Joins("JOIN order_items as oi ON oi.order_id = o.id").
 		Group("oi.variant_id").
-		Where("o.status = ?", "completed").
+		Where("o.status IN ?", fulfilledStatuses).
 		Where("o.created_at BETWEEN ? AND ?", StartOfMonth(now), EndOfMonth(now)).
 		Order("total_sold DESC").
 		Limit(5).
@@ -119,15 +124,62 @@ func (dr *dashboardRepo) GetMonthlyRevenue() ([]*domain.Revenue, error) {
 	query := dr.db.Table("orders as o").
 		Select("TO_CHAR(o.created_at, 'Month') as month, SUM(oi.unit_price * oi.quantity) as revenue").
 		Joins("JOIN order_items as oi ON oi.order_id = o.id").
-		Where("o.status = ?", "completed").
+		Where("o.status IN ?", fulfilledStatuses).
 		Where("o.created_at >= ?", StartOfYear(now)).
 		Group("TO_CHAR(o.created_at, 'Month')").
-		Order("MIN(o.created_at)").
 		Scan(&revenues)
 
 	if query.Error != nil {
 		return nil, query.Error
 	}
 
-	return revenues, nil
+	// refunds are taken off the month they were paid out in
+	var refunds []*domain.Revenue
+	queryRefund := dr.db.Table("refunds as r").
+		Select("TO_CHAR(r.paid_at, 'Month') as month, SUM(r.amount) as revenue").
+		Where("r.status = ?", domain.RefundPaid).
+		Where("r.paid_at >= ?", StartOfYear(now)).
+		Group("TO_CHAR(r.paid_at, 'Month')").
+		Scan(&refunds)
+
+	if queryRefund.Error != nil {
+		return nil, queryRefund.Error
+	}
+
+	return netRevenue(revenues, refunds), nil
+}
+
+// netRevenue takes the refunds off the revenue of their month and returns the
+// months in calendar order.
+func netRevenue(revenues, refunds []*domain.Revenue) []*domain.Revenue {
+	months := map[string]*domain.Revenue{}
+	for _, revenue := range revenues {
+		months[revenue.Month] = &domain.Revenue{Month: revenue.Month, Revenue: revenue.Revenue}
+	}
+	for _, refund := range refunds {
+		revenue, ok := months[refund.Month]
+		if !ok {
+			revenue = &domain.Revenue{Month: refund.Month}
+			months[refund.Month] = revenue
+		}
+		revenue.Revenue -= refund.Revenue
+	}
+
+	net := make([]*domain.Revenue, 0, len(months))
+	for _, revenue := range months {
+		net = append(net, revenue)
+	}
+	sort.Slice(net, func(i, j int) bool {
+		return monthNumber(net[i].Month) < monthNumber(net[j].Month)
+	})
+	return net
+}
+
+// monthNumber parses the month names TO_CHAR(..., 'Month') pads with spaces.
+func monthNumber(name string) time.Month {
+	month, err := time.Parse("January", strings.TrimSpace(name))
+	if err != nil {
+		return 0
+	}
+	return month.Month()
 }
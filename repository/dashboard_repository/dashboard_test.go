@@ -24,8 +24,8 @@ func TestGetEarningDashboard(t *testing.T) {
 	mock.ExpectQuery(regexp.QuoteMeta(
 		`SELECT SUM(oi.unit_price * oi.quantity) as total_amount FROM orders as o 
 		JOIN order_items as oi ON oi.order_id = o.id 
-		WHERE o.status = $1 AND (o.created_at BETWEEN $2 AND $3)`)).
-		WithArgs("completed", sqlmock.AnyArg(), sqlmock.AnyArg()). // Use sqlmock.AnyArg() to match any argument for time
+		WHERE o.status IN ($1,$2) AND (o.created_at BETWEEN $3 AND $4)`)).
+		WithArgs("completed", "delivered", sqlmock.AnyArg(), sqlmock.AnyArg()). // Use sqlmock.AnyArg() to match any argument for time
 		WillReturnRows(rows)
 
 	totalEarning, err := dashboardRepo.GetEarningDashboard()
@@ -110,9 +110,9 @@ func TestGetBestSeller(t *testing.T) {
 		`SELECT oi.variant_id as product_id, 
 		SUM(oi.quantity) as total_sold 
 		FROM orders as o JOIN order_items as oi ON oi.order_id = o.id 
-		WHERE o.status = $1 AND (o.created_at BETWEEN $2 AND $3) 
-		GROUP BY "oi"."variant_id" ORDER BY total_sold DESC LIMIT $4`)).
-		WithArgs("completed", sqlmock.AnyArg(), sqlmock.AnyArg(), 5).
+		WHERE o.status IN ($1,$2) AND (o.created_at BETWEEN $3 AND $4) 
+		GROUP BY "oi"."variant_id" ORDER BY total_sold DESC LIMIT $5`)).
+		WithArgs("completed", "delivered", sqlmock.AnyArg(), sqlmock.AnyArg(), 5).
 		WillReturnRows(rows)
 
 	bestSellers, err := dashboardRepo.GetBestSeller()
@@ -142,33 +142,53 @@ func TestGetMonthlyRevenue(t *testing.T) {
 	dashboardRepo := dashboardrepository.NewDashboardRepo(db, log)
 
 	rows := sqlmock.NewRows([]string{"month", "revenue"}).
-		AddRow("January", 500000).
-		AddRow("February", 600000).
-		AddRow("March", 700000)
+		AddRow("January  ", 500000).
+		AddRow("February ", 600000).
+		AddRow("March    ", 700000).
+		AddRow("May      ", 800000)
 
 	mock.ExpectQuery(regexp.QuoteMeta(
 		`SELECT TO_CHAR(o.created_at, 'Month') as month, SUM(oi.unit_price * oi.quantity) as revenue FROM orders as o 
 		JOIN order_items as oi ON oi.order_id = o.id 
-		WHERE o.status = $1 AND o.created_at >= $2 
-		GROUP BY TO_CHAR(o.created_at, 'Month') ORDER BY MIN(o.created_at)`)).
-		WithArgs("completed", sqlmock.AnyArg()).
+		WHERE o.status IN ($1,$2) AND o.created_at >= $3 
+		GROUP BY TO_CHAR(o.created_at, 'Month')`)).
+		WithArgs("completed", "delivered", sqlmock.AnyArg()).
 		WillReturnRows(rows)
 
+	refundRows := sqlmock.NewRows([]string{"month", "revenue"}).
+		AddRow("February ", 100000).
+		AddRow("April    ", 50000)
+
+	mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT TO_CHAR(r.paid_at, 'Month') as month, SUM(r.amount) as revenue FROM refunds as r 
+		WHERE r.status = $1 AND r.paid_at >= $2 
+		GROUP BY TO_CHAR(r.paid_at, 'Month')`)).
+		WithArgs("paid", sqlmock.AnyArg()).
+		WillReturnRows(refundRows)
+
 	revenues, err := dashboardRepo.GetMonthlyRevenue()
 
 	if err != nil {
 		t.Errorf("Unexpected error: %s", err)
 	}
 
-	if len(revenues) != 3 {
-		t.Errorf("Expected 3 monthly revenues, but got %d", len(revenues))
+	if len(revenues) != 5 {
+		t.Fatalf("Expected 5 monthly revenues, but got %d", len(revenues))
 	}
 
-	if revenues[0].Month != "January" || revenues[0].Revenue != 500000 {
+	if revenues[0].Month != "January  " || revenues[0].Revenue != 500000 {
 		t.Errorf("Expected January revenue 500000, but got month %s with revenue %d", revenues[0].Month, revenues[0].Revenue)
 	}
 
-	if revenues[1].Month != "February" || revenues[1].Revenue != 600000 {
-		t.Errorf("Expected February revenue 600000, but got month %s with revenue %d", revenues[1].Month, revenues[1].Revenue)
+	if revenues[1].Month != "February " || revenues[1].Revenue != 500000 {
+		t.Errorf("Expected February net revenue 500000, but got month %s with revenue %d", revenues[1].Month, revenues[1].Revenue)
+	}
+
+	if revenues[3].Month != "April    " || revenues[3].Revenue != -50000 {
+		t.Errorf("Expected April net revenue -50000, but got month %s with revenue %d", revenues[3].Month, revenues[3].Revenue)
+	}
+
+	if revenues[4].Month != "May      " || revenues[4].Revenue != 800000 {
+		t.Errorf("Expected May revenue 800000, but got month %s with revenue %d", revenues[4].Month, revenues[4].Revenue)
 	}
 }
@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"errors"
+	"project/domain"
+	"strings"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type RepositoryOption interface {
+	FindByCategory(categoryId uint) ([]domain.OptionType, error)
+	Insert(optionType *domain.OptionType) error
+	InsertValue(value *domain.OptionValue) error
+	Delete(id uint) error
+	DeleteValue(id uint) error
+}
+
+type repositoryOption struct {
+	db  *gorm.DB
+	log *zap.Logger
+}
+
+func NewRepositoryOption(db *gorm.DB, log *zap.Logger) RepositoryOption {
+	return &repositoryOption{db, log}
+}
+
+// categoryAncestors selects the category and all its ancestors, the option
+// types of any of them apply to the category.
+func categoryAncestors(db *gorm.DB, categoryId uint) *gorm.DB {
+	return db.Raw(`
+		WITH RECURSIVE path AS (
+			SELECT id, parent_id FROM categories WHERE id = ?
+			UNION ALL
+			SELECT c.id, c.parent_id FROM categories c JOIN path p ON c.id = p.parent_id
+		)
+		SELECT id FROM path`, categoryId)
+}
+
+// productCategoryAncestors selects the categories of a product and all their
+// ancestors.
+func productCategoryAncestors(db *gorm.DB, productId int) *gorm.DB {
+	return db.Raw(`
+		WITH RECURSIVE path AS (
+			SELECT c.id, c.parent_id FROM categories c
+			JOIN product_categories pc ON pc.category_id = c.id AND pc.product_id = ?
+			UNION
+			SELECT c.id, c.parent_id FROM categories c JOIN path p ON c.id = p.parent_id
+		)
+		SELECT id FROM path`, productId)
+}
+
+func (repo *repositoryOption) FindByCategory(categoryId uint) ([]domain.OptionType, error) {
+	if err := repo.db.First(&domain.Category{}, categoryId).Error; err != nil {
+		return nil, errors.New("category not found")
+	}
+
+	optionTypes := []domain.OptionType{}
+	err := repo.db.
+		Where("category_id IS NULL OR category_id IN (?)", categoryAncestors(repo.db, categoryId)).
+		Preload("Values", func(db *gorm.DB) *gorm.DB { return db.Order("position, id") }).
+		Order("position, id").
+		Find(&optionTypes).Error
+	if err != nil {
+		repo.log.Error("Error fetching option types", zap.Uint("category_id", categoryId), zap.Error(err))
+		return nil, errors.New("internal server error")
+	}
+	return optionTypes, nil
+}
+
+func (repo *repositoryOption) Insert(optionType *domain.OptionType) error {
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&domain.Category{}, optionType.CategoryID).Error; err != nil {
+			return errors.New("category not found")
+		}
+
+		var count int64
+		if err := tx.Model(&domain.OptionType{}).
+			Where("category_id = ? AND LOWER(name) = ?", optionType.CategoryID, strings.ToLower(optionType.Name)).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return errors.New("option type " + optionType.Name + " already exists in this category")
+		}
+
+		if err := tx.Model(&domain.OptionType{}).
+			Where("category_id = ?", optionType.CategoryID).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		optionType.Position = int(count)
+
+		return tx.Create(optionType).Error
+	})
+
+	if err != nil {
+		repo.log.Error("Error creating option type", zap.String("name", optionType.Name), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (repo *repositoryOption) InsertValue(value *domain.OptionValue) error {
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&domain.OptionType{}, value.OptionTypeID).Error; err != nil {
+			return errors.New("option type not found")
+		}
+
+		var count int64
+		if err := tx.Model(&domain.OptionValue{}).
+			Where("option_type_id = ? AND LOWER(value) = ?", value.OptionTypeID, strings.ToLower(value.Value)).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return errors.New("value " + value.Value + " already exists")
+		}
+
+		if err := tx.Model(&domain.OptionValue{}).Where("option_type_id = ?", value.OptionTypeID).Count(&count).Error; err != nil {
+			return err
+		}
+		value.Position = int(count)
+
+		return tx.Create(value).Error
+	})
+
+	if err != nil {
+		repo.log.Error("Error creating option value", zap.String("value", value.Value), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// Delete removes an option type with its values, as long as no variant uses
+// them.
+func (repo *repositoryOption) Delete(id uint) error {
+	var count int64
+	if err := repo.db.Table("variant_option_values AS vov").
+		Joins("JOIN option_values ov ON ov.id = vov.option_value_id").
+		Where("ov.option_type_id = ?", id).
+		Count(&count).Error; err != nil {
+		repo.log.Error("Error counting option type variants", zap.Uint("id", id), zap.Error(err))
+		return errors.New("failed to delete option type")
+	}
+	if count > 0 {
+		return errors.New("option type is still used by variants")
+	}
+
+	result := repo.db.Delete(&domain.OptionType{}, id)
+	if result.Error != nil {
+		repo.log.Error("Error deleting option type", zap.Uint("id", id), zap.Error(result.Error))
+		return errors.New("failed to delete option type")
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("option type not found")
+	}
+	return nil
+}
+
+func (repo *repositoryOption) DeleteValue(id uint) error {
+	var count int64
+	if err := repo.db.Model(&domain.VariantOptionValue{}).Where("option_value_id = ?", id).Count(&count).Error; err != nil {
+		repo.log.Error("Error counting option value variants", zap.Uint("id", id), zap.Error(err))
+		return errors.New("failed to delete option value")
+	}
+	if count > 0 {
+		return errors.New("option value is still used by variants")
+	}
+
+	result := repo.db.Delete(&domain.OptionValue{}, id)
+	if result.Error != nil {
+		repo.log.Error("Error deleting option value", zap.Uint("id", id), zap.Error(result.Error))
+		return errors.New("failed to delete option value")
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("option value not found")
+	}
+	return nil
+}
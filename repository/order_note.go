@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"errors"
+	"project/domain"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type RepositoryOrderNote interface {
+	FindByOrder(orderId uint, visibility domain.NoteVisibility) ([]domain.OrderNote, error)
+	FindById(orderId, id uint) (domain.OrderNote, error)
+	Insert(note *domain.OrderNote) error
+	Update(note *domain.OrderNote) error
+	Delete(note *domain.OrderNote) error
+}
+
+type repositoryOrderNote struct {
+	db  *gorm.DB
+	log *zap.Logger
+}
+
+func NewRepositoryOrderNote(db *gorm.DB, log *zap.Logger) RepositoryOrderNote {
+	return &repositoryOrderNote{db, log}
+}
+
+func (repo *repositoryOrderNote) FindByOrder(orderId uint, visibility domain.NoteVisibility) ([]domain.OrderNote, error) {
+	query := repo.db.Preload("Author").Where("order_id = ?", orderId)
+	if visibility != "" {
+		query = query.Where("visibility = ?", visibility)
+	}
+
+	notes := []domain.OrderNote{}
+	if err := query.Order("created_at").Find(&notes).Error; err != nil {
+		repo.log.Error("Error fetching order notes", zap.Uint("order_id", orderId), zap.Error(err))
+		return nil, errors.New("internal server error")
+	}
+	return notes, nil
+}
+
+func (repo *repositoryOrderNote) FindById(orderId, id uint) (domain.OrderNote, error) {
+	var note domain.OrderNote
+	if err := repo.db.Preload("Author").Where("order_id = ?", orderId).First(&note, id).Error; err != nil {
+		return domain.OrderNote{}, errors.New("note not found")
+	}
+	return note, nil
+}
+
+func (repo *repositoryOrderNote) Insert(note *domain.OrderNote) error {
+	if note.Visibility == "" {
+		note.Visibility = domain.NoteInternal
+	}
+	if err := repo.db.Omit("Author").Create(note).Error; err != nil {
+		repo.log.Error("Error creating order note", zap.Uint("order_id", note.OrderID), zap.Error(err))
+		return errors.New("failed to create note")
+	}
+	return repo.db.First(&note.Author, note.AuthorID).Error
+}
+
+func (repo *repositoryOrderNote) Update(note *domain.OrderNote) error {
+	err := repo.db.Model(note).Omit("Author").Updates(map[string]interface{}{
+		"content":    note.Content,
+		"visibility": note.Visibility,
+	}).Error
+	if err != nil {
+		repo.log.Error("Error updating order note", zap.Uint("id", note.ID), zap.Error(err))
+		return errors.New("failed to update note")
+	}
+	return nil
+}
+
+func (repo *repositoryOrderNote) Delete(note *domain.OrderNote) error {
+	if err := repo.db.Delete(note).Error; err != nil {
+		repo.log.Error("Error deleting order note", zap.Uint("id", note.ID), zap.Error(err))
+		return errors.New("failed to delete note")
+	}
+	return nil
+}
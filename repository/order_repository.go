@@ -2,62 +2,318 @@ package repository
 
 import (
 	"errors"
+	"fmt"
+	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"math"
 	"project/domain"
 	"project/helper"
+	"time"
 )
 
 type OrderRepository struct {
-	db *gorm.DB
+	db  *gorm.DB
+	log *zap.Logger
 }
 
-func NewOrderRepository(db *gorm.DB) *OrderRepository {
-	return &OrderRepository{db: db}
+func NewOrderRepository(db *gorm.DB, log *zap.Logger) *OrderRepository {
+	return &OrderRepository{db: db, log: log}
 }
 
+// Create validates stock and prices every line against the locked variants and
+// inserts the order with its items in a single transaction.
+func (repo OrderRepository) Create(newOrder domain.NewOrder) (domain.Order, error) {
+	order := domain.Order{
+		CustomerID:    newOrder.CustomerID,
+		PaymentMethod: newOrder.PaymentMethod,
+		Status:        domain.Created,
+	}
+
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&domain.Customer{}, newOrder.CustomerID).Error; err != nil {
+			return errors.New("customer not found")
+		}
+
+		now := time.Now()
+		cart := domain.Cart{CustomerID: newOrder.CustomerID}
+		for _, line := range newOrder.Lines() {
+			var variant domain.ProductVariant
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&variant, line.VariantID).Error; err != nil {
+				return fmt.Errorf("variant %d not found", line.VariantID)
+			}
+			if variant.Stock < int(line.Quantity) {
+				return fmt.Errorf("not enough stock for variant %d", line.VariantID)
+			}
+
+			priced, err := cartLine(tx, variant, line.Quantity, now)
+			if err != nil {
+				return err
+			}
+			cart.Lines = append(cart.Lines, priced)
+			order.Items = append(order.Items, domain.OrderItem{
+				VariantID: line.VariantID,
+				Quantity:  line.Quantity,
+				UnitPrice: priced.UnitPrice,
+			})
+		}
+
+		promotion, err := findPromotion(tx, repo.log, cart, newOrder.VoucherCode, now)
+		if err != nil {
+			return err
+		}
+		if promotion.ID != 0 {
+			// the promotion stays locked until the order is in so its limits
+			// hold under concurrent orders
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&promotion, promotion.ID).Error; err != nil {
+				return err
+			}
+			used, err := promotionUsed(tx, promotion.ID, newOrder.CustomerID)
+			if err != nil {
+				return err
+			}
+			if err := order.ApplyPromotion(promotion, cart, used, now); err != nil {
+				return err
+			}
+			if err := tx.Model(&promotion).UpdateColumn("limit", gorm.Expr(`"limit" - 1`)).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Omit("Customer", "Items.Variant").Create(&order).Error; err != nil {
+			return err
+		}
+		if order.PromotionID == nil {
+			return nil
+		}
+		return tx.Create(&domain.PromotionUsage{
+			PromotionID: *order.PromotionID,
+			CustomerID:  order.CustomerID,
+			OrderID:     order.ID,
+			Discount:    order.Discount,
+		}).Error
+	})
+
+	return order, err
+}
+
+// Update confirms an order. The status check, the stock the order takes when
+// it's processed and the order itself are saved in one transaction holding
+// the order and its variants locked.
 func (repo OrderRepository) Update(orderId uint, confirmation domain.OrderConfirmation) error {
-	var order = domain.Order{ID: orderId}
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		order, err := lockOrder(tx, orderId)
+		if err != nil {
+			return err
+		}
+		return confirmOrder(tx, &order, confirmation)
+	})
+}
 
-	repo.db.Preload("Items.Variant").First(&order)
+// ProcessPaid processes an order that is still created once the paid amount
+// covers its total. Orders past the created state are left as they are, so a
+// repeated payment notification doesn't take their stock again.
+func (repo OrderRepository) ProcessPaid(orderId uint, paid float64) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		order, err := lockOrder(tx, orderId)
+		if err != nil {
+			return err
+		}
+		if order.Status != domain.Created {
+			return nil
+		}
+
+		var total float64
+		if err := tx.Model(&domain.OrderTotal{}).Select("total").Where("id = ?", orderId).Scan(&total).Error; err != nil {
+			return err
+		}
+		if paid < total {
+			return nil
+		}
+		return confirmOrder(tx, &order, domain.OrderConfirmation{Accept: true})
+	})
+}
+
+func lockOrder(tx *gorm.DB, orderId uint) (domain.Order, error) {
+	var order domain.Order
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, orderId).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return order, errors.New("order not found")
+	}
+	return order, err
+}
+
+// confirmOrder moves a locked order to its next status and saves it.
+func confirmOrder(tx *gorm.DB, order *domain.Order, confirmation domain.OrderConfirmation) error {
 	if err := order.Confirm(confirmation); err != nil {
 		return err
 	}
-
-	if err := repo.shouldUpdateStock(&order); err != nil {
+	if order.Status == domain.Processed {
+		if err := deductStock(tx, order.ID); err != nil {
+			return err
+		}
+	}
+	if err := tx.Omit(clause.Associations).Save(order).Error; err != nil {
 		return err
 	}
+	return releasePromotion(tx, *order)
+}
 
-	return repo.db.Save(&order).Error
+// releasePromotion gives the promotion of a canceled order back, its usage is
+// removed and the promotion's Limit goes up again.
+func releasePromotion(tx *gorm.DB, order domain.Order) error {
+	if order.Status != domain.Canceled || order.PromotionID == nil {
+		return nil
+	}
+	result := tx.Where("order_id = ?", order.ID).Delete(&domain.PromotionUsage{})
+	if result.Error != nil || result.RowsAffected == 0 {
+		return result.Error
+	}
+	return tx.Model(&domain.Promotion{}).Where("id = ?", *order.PromotionID).
+		UpdateColumn("limit", gorm.Expr(`"limit" + 1`)).Error
 }
 
-func (repo OrderRepository) shouldUpdateStock(order *domain.Order) error {
-	if order.Status == domain.Processed {
-		for _, item := range order.Items {
-			if err := item.Variant.DeductStock(item.Quantity); err != nil {
-				return err
-			}
-			repo.db.Save(&item.Variant)
+// deductStock takes the items of an order from the stock of their variants,
+// which are locked in id order so concurrent orders can't deadlock.
+func deductStock(tx *gorm.DB, orderId uint) error {
+	var items []domain.OrderItem
+	if err := tx.Where("order_id = ?", orderId).Find(&items).Error; err != nil {
+		return err
+	}
+	variantIds := make([]uint, 0, len(items))
+	for _, item := range items {
+		variantIds = append(variantIds, item.VariantID)
+	}
+
+	var variants []domain.ProductVariant
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ?", variantIds).Order("id").Find(&variants).Error; err != nil {
+		return err
+	}
+	byId := make(map[uint]*domain.ProductVariant, len(variants))
+	for i := range variants {
+		byId[uint(variants[i].ID)] = &variants[i]
+	}
+
+	for _, item := range items {
+		variant, ok := byId[item.VariantID]
+		if !ok {
+			return fmt.Errorf("variant %d not found", item.VariantID)
+		}
+		if err := variant.DeductStock(item.Quantity); err != nil {
+			return fmt.Errorf("variant %d: %w", item.VariantID, err)
+		}
+	}
+	for _, variant := range variants {
+		if err := tx.Model(&variant).UpdateColumn("stock", variant.Stock).Error; err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
-func (repo OrderRepository) All(page, limit uint) (int, int, []domain.OrderTotal, error) {
+// All returns a page of the orders matching the filter, an empty page when
+// none match.
+func (repo OrderRepository) All(page, limit uint, filter domain.OrderFilter) (int, int, []domain.OrderTotal, error) {
 	var count int64
-	repo.db.Model(&domain.OrderTotal{}).Count(&count)
+	if err := repo.db.Model(&domain.OrderTotal{}).Scopes(filterOrders(filter)).Count(&count).Error; err != nil {
+		repo.log.Error("Error counting orders", zap.Error(err))
+		return 0, 0, nil, errors.New("internal server error")
+	}
 	pages := int(math.Ceil(float64(count) / float64(limit)))
 
-	var orders []domain.OrderTotal
-	result := repo.db.Scopes(helper.Paginate(page, limit)).Find(&orders)
-	if result.RowsAffected == 0 {
-		return 0, 0, nil, errors.New("order not found")
+	orders := []domain.OrderTotal{}
+	if err := repo.db.Scopes(filterOrders(filter), helper.Paginate(page, limit)).Order(filter.OrderBy()).Find(&orders).Error; err != nil {
+		repo.log.Error("Error fetching orders", zap.Error(err))
+		return 0, 0, nil, errors.New("internal server error")
 	}
 	return int(count), pages, orders, nil
 }
 
 func (repo OrderRepository) Get(orderId uint) (domain.OrderTotal, error) {
 	var order domain.OrderTotal
-	result := repo.db.Preload("Items").First(&order, orderId)
+	result := repo.db.Preload("Items").
+		Preload("Notes", func(db *gorm.DB) *gorm.DB { return db.Order("created_at") }).
+		Preload("Notes.Author").
+		First(&order, orderId)
 	return order, result.Error
 }
+
+// AssignInvoiceNumbers gives every order that hasn't been invoiced yet the next
+// number of the invoice sequence, in the order the ids are given. Nothing is
+// numbered when one of the orders hasn't been paid for.
+func (repo OrderRepository) AssignInvoiceNumbers(orderIds []uint) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		for _, id := range orderIds {
+			order, err := lockOrder(tx, id)
+			if err != nil {
+				return err
+			}
+			if !order.Invoiceable() {
+				return fmt.Errorf("order %d: %w", id, domain.ErrNotInvoiceable)
+			}
+
+			err = tx.Exec(`UPDATE orders SET invoice_number = nextval('invoice_number_seq') WHERE id = ? AND invoice_number IS NULL`, id).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (repo OrderRepository) FindByIds(orderIds []uint) ([]domain.OrderTotal, error) {
+	var orders []domain.OrderTotal
+	if err := repo.db.Preload("Items").Where("id IN ?", orderIds).Find(&orders).Error; err != nil {
+		return nil, err
+	}
+
+	byId := make(map[uint]domain.OrderTotal, len(orders))
+	for _, order := range orders {
+		byId[order.ID] = order
+	}
+
+	sorted := make([]domain.OrderTotal, 0, len(orderIds))
+	for _, id := range orderIds {
+		order, ok := byId[id]
+		if !ok {
+			return nil, fmt.Errorf("order %d not found", id)
+		}
+		sorted = append(sorted, order)
+	}
+	return sorted, nil
+}
+
+func filterOrders(filter domain.OrderFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if filter.CustomerID != 0 {
+			db = db.Where("customer_id = ?", filter.CustomerID)
+		}
+		if filter.Status != "" {
+			db = db.Where("status = ?", filter.Status)
+		}
+		if filter.PaymentMethod != "" {
+			db = db.Where("payment_method = ?", filter.PaymentMethod)
+		}
+		if filter.CustomerName != "" {
+			db = db.Where("customer_name ILIKE ?", helper.Contains(filter.CustomerName))
+		}
+		if filter.DateFrom != "" {
+			db = db.Where("created_at >= ?", helper.Date(filter.DateFrom))
+		}
+		if filter.DateTo != "" {
+			db = db.Where("created_at < ?", helper.Date(filter.DateTo).AddDate(0, 0, 1))
+		}
+		if filter.MinTotal != nil {
+			db = db.Where("total >= ?", *filter.MinTotal)
+		}
+		if filter.MaxTotal != nil {
+			db = db.Where("total <= ?", *filter.MaxTotal)
+		}
+		if filter.Search != "" {
+			search := helper.Contains(filter.Search)
+			db = db.Where("customer_name ILIKE ? OR customer_address ILIKE ? OR tracking_number ILIKE ?", search, search, search)
+		}
+		return db
+	}
+}
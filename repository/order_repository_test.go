@@ -0,0 +1,146 @@
+package repository_test
+
+import (
+	"project/domain"
+	"project/helper"
+	"project/repository"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestProcessPaidOrder(t *testing.T) {
+	db, mock := helper.SetupTestDB()
+	repo := repository.NewOrderRepository(db, zap.NewNop())
+	lockOrder := regexp.QuoteMeta(`SELECT * FROM "orders" WHERE "orders"."id" = $1 ORDER BY "orders"."id" LIMIT $2 FOR UPDATE`)
+
+	t.Run("Successfully process a paid order and take its stock", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockOrder).
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "customer_id", "status"}).AddRow(1, 3, "created"))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT "total" FROM "order_totals" WHERE id = $1`)).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"total"}).AddRow(150000))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "order_items" WHERE order_id = $1`)).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "variant_id", "quantity"}).
+				AddRow(1, 1, 7, 2).
+				AddRow(2, 1, 7, 1))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "product_variants" WHERE id IN ($1,$2) AND "product_variants"."deleted_at" IS NULL ORDER BY id FOR UPDATE`)).
+			WithArgs(7, 7).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "stock"}).AddRow(7, 5))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "product_variants" SET "stock"=$1 WHERE "product_variants"."deleted_at" IS NULL AND "id" = $2`)).
+			WithArgs(2, 7).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "orders" SET`)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		assert.NoError(t, repo.ProcessPaid(1, 150000))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Order past created is left alone", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockOrder).
+			WithArgs(2, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "customer_id", "status"}).AddRow(2, 3, "processed"))
+		mock.ExpectCommit()
+
+		assert.NoError(t, repo.ProcessPaid(2, 150000))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failed when a variant is out of stock", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockOrder).
+			WithArgs(3, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "customer_id", "status"}).AddRow(3, 3, "created"))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT "total" FROM "order_totals" WHERE id = $1`)).
+			WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"total"}).AddRow(50000))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "order_items" WHERE order_id = $1`)).
+			WithArgs(3).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "variant_id", "quantity"}).AddRow(3, 3, 8, 4))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "product_variants" WHERE id IN ($1) AND "product_variants"."deleted_at" IS NULL ORDER BY id FOR UPDATE`)).
+			WithArgs(8).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "stock"}).AddRow(8, 1))
+		mock.ExpectRollback()
+
+		assert.EqualError(t, repo.ProcessPaid(3, 50000), "variant 8: not enough stock")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestAllOrders(t *testing.T) {
+	db, mock := helper.SetupTestDB()
+	repo := repository.NewOrderRepository(db, zap.NewNop())
+
+	t.Run("No matching orders is an empty page", func(t *testing.T) {
+		filter := domain.OrderFilter{CustomerID: 9}
+		assert.NoError(t, filter.Validate())
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "order_totals" WHERE customer_id = $1`)).
+			WithArgs(9).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "order_totals" WHERE customer_id = $1 ORDER BY created_at desc LIMIT $2`)).
+			WithArgs(9, 10).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		total, pages, orders, err := repo.All(1, 10, filter)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, total)
+		assert.Equal(t, 0, pages)
+		assert.Empty(t, orders)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestAssignInvoiceNumbers(t *testing.T) {
+	db, mock := helper.SetupTestDB()
+	repo := repository.NewOrderRepository(db, zap.NewNop())
+	lockOrder := regexp.QuoteMeta(`SELECT * FROM "orders" WHERE "orders"."id" = $1 ORDER BY "orders"."id" LIMIT $2 FOR UPDATE`)
+	assign := regexp.QuoteMeta(`UPDATE orders SET invoice_number = nextval('invoice_number_seq') WHERE id = $1 AND invoice_number IS NULL`)
+
+	t.Run("Successfully number processed orders", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockOrder).
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(1, "processed"))
+		mock.ExpectExec(assign).
+			WithArgs(1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(lockOrder).
+			WithArgs(2, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(2, "delivered"))
+		mock.ExpectExec(assign).
+			WithArgs(2).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		assert.NoError(t, repo.AssignInvoiceNumbers([]uint{1, 2}))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Unpaid order rejects the whole batch", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockOrder).
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(1, "processed"))
+		mock.ExpectExec(assign).
+			WithArgs(1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(lockOrder).
+			WithArgs(3, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(3, "created"))
+		mock.ExpectRollback()
+
+		err := repo.AssignInvoiceNumbers([]uint{1, 3})
+		assert.ErrorIs(t, err, domain.ErrNotInvoiceable)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"errors"
+	"project/domain"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type RepositoryPayment interface {
+	FindByOrder(orderId uint) ([]domain.Payment, error)
+	FindByReference(provider, reference string) (domain.Payment, error)
+	Insert(payment *domain.Payment) error
+	UpdateStatus(payment *domain.Payment) error
+	PaidAmount(orderId uint) (float64, error)
+	FindMismatches() ([]domain.PaymentMismatch, error)
+}
+
+type repositoryPayment struct {
+	db  *gorm.DB
+	log *zap.Logger
+}
+
+func NewRepositoryPayment(db *gorm.DB, log *zap.Logger) RepositoryPayment {
+	return &repositoryPayment{db, log}
+}
+
+// receivedStatuses are the payments whose money reached the store, refunded
+// ones included.
+var receivedStatuses = []domain.PaymentStatus{domain.PaymentPaid, domain.PaymentRefunded}
+
+func (repo *repositoryPayment) FindByOrder(orderId uint) ([]domain.Payment, error) {
+	payments := []domain.Payment{}
+	if err := repo.db.Where("order_id = ?", orderId).Order("created_at").Find(&payments).Error; err != nil {
+		repo.log.Error("Error fetching payments", zap.Uint("order_id", orderId), zap.Error(err))
+		return nil, errors.New("internal server error")
+	}
+	return payments, nil
+}
+
+func (repo *repositoryPayment) FindByReference(provider, reference string) (domain.Payment, error) {
+	var payment domain.Payment
+	err := repo.db.Where("provider = ? AND provider_reference = ?", provider, reference).First(&payment).Error
+	if err != nil {
+		return domain.Payment{}, errors.New("payment not found")
+	}
+	return payment, nil
+}
+
+func (repo *repositoryPayment) Insert(payment *domain.Payment) error {
+	payment.Status = domain.PaymentPending
+	if err := repo.db.Create(payment).Error; err != nil {
+		repo.log.Error("Error creating payment", zap.Uint("order_id", payment.OrderID), zap.Error(err))
+		return errors.New("failed to create payment")
+	}
+	return nil
+}
+
+func (repo *repositoryPayment) UpdateStatus(payment *domain.Payment) error {
+	err := repo.db.Model(payment).Updates(map[string]interface{}{
+		"status":  payment.Status,
+		"amount":  payment.Amount,
+		"paid_at": payment.PaidAt,
+	}).Error
+	if err != nil {
+		repo.log.Error("Error updating payment", zap.Uint("id", payment.ID), zap.Error(err))
+		return errors.New("failed to update payment")
+	}
+	return nil
+}
+
+func (repo *repositoryPayment) PaidAmount(orderId uint) (float64, error) {
+	var paid float64
+	err := repo.db.Model(&domain.Payment{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("order_id = ? AND status IN ?", orderId, receivedStatuses).
+		Scan(&paid).Error
+	return paid, err
+}
+
+// FindMismatches lists the orders past the created state whose paid amount
+// doesn't match the order total. Canceled orders are expected to have nothing
+// paid.
+func (repo *repositoryPayment) FindMismatches() ([]domain.PaymentMismatch, error) {
+	var mismatches []domain.PaymentMismatch
+	err := repo.db.Table("order_totals as ot").
+		Select(`ot.id as order_id, ot.status,
+			CASE WHEN ot.status = 'canceled' THEN 0 ELSE ot.total END as total,
+			COALESCE(SUM(p.amount), 0) as paid,
+			COALESCE(SUM(p.amount), 0) - CASE WHEN ot.status = 'canceled' THEN 0 ELSE ot.total END as difference`).
+		Joins("LEFT JOIN payments as p ON p.order_id = ot.id AND p.status IN ?", receivedStatuses).
+		Group("ot.id, ot.status, ot.total").
+		Having("(ot.status <> 'created' OR COUNT(p.id) > 0) AND ABS(COALESCE(SUM(p.amount), 0) - CASE WHEN ot.status = 'canceled' THEN 0 ELSE ot.total END) >= 0.01").
+		Order("ot.id").
+		Scan(&mismatches).Error
+	if err != nil {
+		repo.log.Error("Error reconciling payments", zap.Error(err))
+		return nil, err
+	}
+	return mismatches, nil
+}
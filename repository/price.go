@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"errors"
+	"project/domain"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RepositoryPrice interface {
+	FindHistory(productId int) ([]domain.PriceHistory, error)
+	FindScheduled(productId int) ([]domain.ScheduledPrice, error)
+	InsertScheduled(scheduled *domain.ScheduledPrice) error
+	DeleteScheduled(productId int, id uint) error
+	SetSale(productId int, sale domain.ProductSale) (domain.Product, error)
+	ApplyScheduled(now time.Time) (int, error)
+}
+
+type repositoryPrice struct {
+	db  *gorm.DB
+	log *zap.Logger
+}
+
+func NewRepositoryPrice(db *gorm.DB, log *zap.Logger) RepositoryPrice {
+	return &repositoryPrice{db, log}
+}
+
+// FindHistory lists the price changes of a product, the latest first. The
+// history of archived products stays available.
+func (repo *repositoryPrice) FindHistory(productId int) ([]domain.PriceHistory, error) {
+	if err := repo.db.Unscoped().First(&domain.Product{}, productId).Error; err != nil {
+		return nil, errors.New("product not found")
+	}
+
+	history := []domain.PriceHistory{}
+	if err := repo.db.Where("product_id = ?", productId).Order("changed_at DESC, id DESC").Find(&history).Error; err != nil {
+		repo.log.Error("Error fetching price history", zap.Int("product_id", productId), zap.Error(err))
+		return nil, errors.New("internal server error")
+	}
+	return history, nil
+}
+
+// FindScheduled lists the price changes of a product that are still to come.
+func (repo *repositoryPrice) FindScheduled(productId int) ([]domain.ScheduledPrice, error) {
+	if err := repo.db.First(&domain.Product{}, productId).Error; err != nil {
+		return nil, errors.New("product not found")
+	}
+
+	scheduled := []domain.ScheduledPrice{}
+	if err := repo.db.Where("product_id = ? AND applied_at IS NULL", productId).Order("effective_at, id").Find(&scheduled).Error; err != nil {
+		repo.log.Error("Error fetching scheduled prices", zap.Int("product_id", productId), zap.Error(err))
+		return nil, errors.New("internal server error")
+	}
+	return scheduled, nil
+}
+
+func (repo *repositoryPrice) InsertScheduled(scheduled *domain.ScheduledPrice) error {
+	if err := repo.db.First(&domain.Product{}, scheduled.ProductID).Error; err != nil {
+		return errors.New("product not found")
+	}
+	if err := repo.db.Create(scheduled).Error; err != nil {
+		repo.log.Error("Error scheduling price", zap.Int("product_id", scheduled.ProductID), zap.Error(err))
+		return errors.New("internal server error")
+	}
+	return nil
+}
+
+// DeleteScheduled cancels a price change that hasn't been applied yet.
+func (repo *repositoryPrice) DeleteScheduled(productId int, id uint) error {
+	result := repo.db.Where("product_id = ? AND applied_at IS NULL", productId).Delete(&domain.ScheduledPrice{}, id)
+	if result.Error != nil {
+		repo.log.Error("Error cancelling scheduled price", zap.Uint("id", id), zap.Error(result.Error))
+		return errors.New("internal server error")
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("scheduled price not found")
+	}
+	return nil
+}
+
+// SetSale puts a product on sale or ends its sale and records the change in
+// the price history.
+func (repo *repositoryPrice) SetSale(productId int, sale domain.ProductSale) (domain.Product, error) {
+	var product domain.Product
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&product, productId).Error; err != nil {
+			return errors.New("product not found")
+		}
+		if err := sale.Apply(&product); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&product).Select("sale_price", "sale_starts_at", "sale_ends_at").Updates(&product).Error; err != nil {
+			repo.log.Error("Error updating sale", zap.Int("product_id", productId), zap.Error(err))
+			return errors.New("internal server error")
+		}
+		history := domain.NewPriceHistory(product, domain.PriceSale, time.Now())
+		if err := tx.Create(&history).Error; err != nil {
+			repo.log.Error("Error recording price", zap.Int("product_id", productId), zap.Error(err))
+			return errors.New("internal server error")
+		}
+		return nil
+	})
+	return product, err
+}
+
+// ApplyScheduled sets the price of the products whose scheduled change is
+// due, in the order the changes take effect, and returns how many were
+// applied. Changes of archived products are applied too so they come back at
+// the right price. Changes of products purged since are dropped.
+func (repo *repositoryPrice) ApplyScheduled(now time.Time) (int, error) {
+	applied := 0
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		var due []domain.ScheduledPrice
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("applied_at IS NULL AND effective_at <= ?", now).
+			Order("effective_at, id").
+			Find(&due).Error; err != nil {
+			return err
+		}
+
+		for _, scheduled := range due {
+			var product domain.Product
+			if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).First(&product, scheduled.ProductID).Error; err != nil {
+				if !errors.Is(err, gorm.ErrRecordNotFound) {
+					return err
+				}
+				if err := tx.Delete(&scheduled).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			product.Price = scheduled.Price
+			if err := tx.Unscoped().Model(&product).UpdateColumn("price", product.Price).Error; err != nil {
+				return err
+			}
+			history := domain.NewPriceHistory(product, domain.PriceScheduled, scheduled.EffectiveAt)
+			if err := tx.Create(&history).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&scheduled).UpdateColumn("applied_at", now).Error; err != nil {
+				return err
+			}
+			applied++
+		}
+		return nil
+	})
+
+	if err != nil {
+		repo.log.Error("Error applying scheduled prices", zap.Error(err))
+		return 0, errors.New("internal server error")
+	}
+	return applied, nil
+}
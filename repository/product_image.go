@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"errors"
+	"project/domain"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RepositoryProductImage interface {
+	FindByProduct(productId int) ([]domain.Image, error)
+	FindById(productId, id int) (domain.Image, error)
+	Insert(productId int, images []*domain.Image) error
+	Update(image *domain.Image) error
+	Reorder(productId int, order domain.ImageOrder) ([]domain.Image, error)
+	SetPrimary(image *domain.Image) error
+	Delete(image *domain.Image) error
+}
+
+type repositoryProductImage struct {
+	db  *gorm.DB
+	log *zap.Logger
+}
+
+func NewRepositoryProductImage(db *gorm.DB, log *zap.Logger) RepositoryProductImage {
+	return &repositoryProductImage{db, log}
+}
+
+func (repo *repositoryProductImage) FindByProduct(productId int) ([]domain.Image, error) {
+	if err := repo.db.First(&domain.Product{}, productId).Error; err != nil {
+		return nil, errors.New("product not found")
+	}
+
+	images := []domain.Image{}
+	if err := repo.db.Where("product_id = ?", productId).Order("position, id").Find(&images).Error; err != nil {
+		repo.log.Error("Error fetching images", zap.Int("product_id", productId), zap.Error(err))
+		return nil, errors.New("internal server error")
+	}
+	return images, nil
+}
+
+func (repo *repositoryProductImage) FindById(productId, id int) (domain.Image, error) {
+	image := domain.Image{}
+	if err := repo.db.Where("product_id = ?", productId).First(&image, id).Error; err != nil {
+		return domain.Image{}, errors.New("image not found")
+	}
+	return image, nil
+}
+
+// Insert appends images after the existing ones of a product, the first one
+// becomes the primary image when the product has none yet.
+func (repo *repositoryProductImage) Insert(productId int, images []*domain.Image) error {
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		var product domain.Product
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&product, productId).Error; err != nil {
+			return errors.New("product not found")
+		}
+
+		var last struct {
+			Position *int
+			Primary  bool
+		}
+		if err := tx.Model(&domain.Image{}).
+			Select("MAX(position) AS position, COALESCE(BOOL_OR(is_primary), false) AS \"primary\"").
+			Where("product_id = ?", productId).
+			Scan(&last).Error; err != nil {
+			return err
+		}
+		position := 0
+		if last.Position != nil {
+			position = *last.Position + 1
+		}
+
+		for i, image := range images {
+			image.ProductID = product.ID
+			image.Position = position + i
+			image.IsPrimary = !last.Primary && i == 0
+			if err := variantOfProduct(tx, image); err != nil {
+				return err
+			}
+			if err := tx.Create(image).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		repo.log.Error("Error creating images", zap.Int("product_id", productId), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (repo *repositoryProductImage) Update(image *domain.Image) error {
+	if err := variantOfProduct(repo.db, image); err != nil {
+		return err
+	}
+	if err := repo.db.Model(image).Select("alt_text", "product_variant_id").Updates(image).Error; err != nil {
+		repo.log.Error("Error updating image", zap.Int("id", image.ID), zap.Error(err))
+		return errors.New("failed to update image")
+	}
+	return nil
+}
+
+// Reorder sets the display order of all images of a product.
+func (repo *repositoryProductImage) Reorder(productId int, order domain.ImageOrder) ([]domain.Image, error) {
+	images := []domain.Image{}
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&domain.Product{}, productId).Error; err != nil {
+			return errors.New("product not found")
+		}
+		if err := tx.Where("product_id = ?", productId).Order("position, id").Find(&images).Error; err != nil {
+			return err
+		}
+		if err := order.Apply(images); err != nil {
+			return err
+		}
+		for _, image := range images {
+			if err := tx.Model(&image).UpdateColumn("position", image.Position).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		repo.log.Error("Error reordering images", zap.Int("product_id", productId), zap.Error(err))
+		return nil, err
+	}
+	return repo.FindByProduct(productId)
+}
+
+// SetPrimary makes an image the primary image of its product.
+func (repo *repositoryProductImage) SetPrimary(image *domain.Image) error {
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&domain.Image{}).
+			Where("product_id = ? AND is_primary", image.ProductID).
+			UpdateColumn("is_primary", false).Error; err != nil {
+			return err
+		}
+		return tx.Model(image).UpdateColumn("is_primary", true).Error
+	})
+
+	if err != nil {
+		repo.log.Error("Error setting primary image", zap.Int("id", image.ID), zap.Error(err))
+		return errors.New("failed to set primary image")
+	}
+	image.IsPrimary = true
+	return nil
+}
+
+// Delete moves an image to the trash, the next image in order becomes primary
+// when the primary image is deleted. Its files stay in storage until the image
+// is purged.
+func (repo *repositoryProductImage) Delete(image *domain.Image) error {
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&domain.Image{}).Where("id = ?", image.ID).UpdateColumn("is_primary", false).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(image).Error; err != nil {
+			return err
+		}
+
+		if image.IsPrimary {
+			var next domain.Image
+			err := tx.Where("product_id = ?", image.ProductID).Order("position, id").First(&next).Error
+			if err == nil {
+				if err := tx.Model(&next).UpdateColumn("is_primary", true).Error; err != nil {
+					return err
+				}
+			} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		repo.log.Error("Error deleting image", zap.Int("id", image.ID), zap.Error(err))
+		return errors.New("failed to delete image")
+	}
+	return nil
+}
+
+// variantOfProduct checks that the variant an image is attached to belongs to
+// the image's product.
+func variantOfProduct(tx *gorm.DB, image *domain.Image) error {
+	if image.ProductVariantID == nil {
+		return nil
+	}
+	var count int64
+	if err := tx.Model(&domain.ProductVariant{}).
+		Where("id = ? AND product_id = ?", *image.ProductVariantID, image.ProductID).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return errors.New("variant not found")
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package repository_test
+
+import (
+	"project/domain"
+	"project/helper"
+	"project/repository"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestDeleteProductImage(t *testing.T) {
+	db, mock := helper.SetupTestDB()
+	repo := repository.NewRepositoryProductImage(db, zap.NewNop())
+
+	t.Run("Next image becomes primary when the primary image is deleted", func(t *testing.T) {
+		image := &domain.Image{ID: 1, ProductID: 5, IsPrimary: true}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "images" SET "is_primary"=$1 WHERE id = $2 AND "images"."deleted_at" IS NULL`)).
+			WithArgs(false, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "images" SET "deleted_at"=$1 WHERE "images"."id" = $2 AND "images"."deleted_at" IS NULL`)).
+			WithArgs(sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "images" WHERE product_id = $1 AND "images"."deleted_at" IS NULL ORDER BY position, id,"images"."id" LIMIT $2`)).
+			WithArgs(5, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "position"}).AddRow(2, 5, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "images" SET "is_primary"=$1 WHERE "images"."deleted_at" IS NULL AND "id" = $2`)).
+			WithArgs(true, 2).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		assert.NoError(t, repo.Delete(image))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Other images keep their flag when a secondary image is deleted", func(t *testing.T) {
+		image := &domain.Image{ID: 3, ProductID: 5}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "images" SET "is_primary"=$1 WHERE id = $2 AND "images"."deleted_at" IS NULL`)).
+			WithArgs(false, 3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "images" SET "deleted_at"=$1 WHERE "images"."id" = $2 AND "images"."deleted_at" IS NULL`)).
+			WithArgs(sqlmock.AnyArg(), 3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		assert.NoError(t, repo.Delete(image))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -4,20 +4,31 @@ import (
 	"fmt"
 	"log"
 	"math"
+	"project/database"
 	"project/domain"
 	"project/helper"
+	"sort"
 	"sync"
+	"time"
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ProductRepo interface {
 	ShowAllProduct(page, limit int) (*[]domain.Product, int, int, error)
+	SearchProducts(page, limit int, search domain.ProductSearch) (*[]domain.Product, *domain.ProductFacets, int, int, error)
 	GetProductByID(id int) (*domain.Product, error)
+	GetActiveProductByID(id int) (*domain.Product, error)
 	CreateProduct(product *domain.Product) error
 	DeleteProduct(id int) error
 	UpdateProduct(productID uint, product *domain.Product) error
+	AssignCategory(productID int, assignment domain.CategoryAssignment) error
+	UnassignCategory(productID int, categoryID uint) error
+	ShowAdminProducts(page, limit int, status domain.ProductStatus) (*[]domain.Product, int, int, error)
+	ChangeStatus(id int, change domain.ProductStatusChange) (*domain.Product, error)
+	ActivateScheduled(now time.Time) (int64, error)
 }
 
 type productRepo struct {
@@ -35,14 +46,14 @@ func (pr *productRepo) ShowAllProduct(page, limit int) (*[]domain.Product, int,
 	productList := []domain.Product{}
 	var count int64
 
-	if err := pr.db.Model(&domain.Product{}).Count(&count).Error; err != nil {
+	if err := pr.db.Model(&domain.Product{}).Scopes(helper.ActiveProducts).Count(&count).Error; err != nil {
 		pr.log.Error("Error counting products", zap.Error(err))
 		return nil, 0, 0, err
 	}
 
-	result := pr.db.Scopes(helper.Paginate(uint(page), uint(limit))).
+	result := pr.db.Scopes(helper.ActiveProducts, helper.Paginate(uint(page), uint(limit))).
 		Preload("ProductVariant").
-		Preload("Image").
+		Preload("Image", helper.ImagesInOrder).
 		Find(&productList)
 
 	if result.Error != nil {
@@ -61,20 +72,212 @@ func (pr *productRepo) ShowAllProduct(page, limit int) (*[]domain.Product, int,
 	return &productList, int(count), totalPages, nil
 }
 
+// searchDocument is the text the full-text search matches against, it must stay
+// identical to the expression of the idx_products_search index.
+const searchDocument = "to_tsvector('simple', products.name || ' ' || products.description)"
+
+// soldStatuses are the order statuses counted as sold for best-selling sorting.
+var soldStatuses = []domain.Status{domain.Completed, domain.Delivered}
+
+func (pr *productRepo) SearchProducts(page, limit int, search domain.ProductSearch) (*[]domain.Product, *domain.ProductFacets, int, int, error) {
+	pr.log.Info("Searching products", zap.Int("page", page), zap.Int("limit", limit), zap.Any("search", search))
+
+	productList := []domain.Product{}
+	var count int64
+
+	if err := pr.db.Model(&domain.Product{}).Scopes(searchProducts(search)).Count(&count).Error; err != nil {
+		pr.log.Error("Error counting products", zap.Error(err))
+		return nil, nil, 0, 0, err
+	}
+
+	query := pr.db.Model(&domain.Product{}).Scopes(searchProducts(search), helper.Paginate(uint(page), uint(limit)))
+	switch search.SortBy {
+	case domain.SortRelevance:
+		query = query.Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:  "ts_rank(" + searchDocument + ", plainto_tsquery('simple', ?)) " + search.SortOrder,
+			Vars: []interface{}{search.Query},
+		}})
+	case domain.SortPrice:
+		query = query.Order("products.price " + search.SortOrder)
+	case domain.SortName:
+		query = query.Order("products.name " + search.SortOrder)
+	case domain.SortNewest:
+		query = query.Order("products.created_at " + search.SortOrder)
+	case domain.SortBestSelling:
+		sales := pr.db.Table("order_items AS oi").
+			Select("pv.product_id, SUM(oi.quantity) AS sold").
+			Joins("JOIN orders AS o ON o.id = oi.order_id").
+			Joins("JOIN product_variants AS pv ON pv.id = oi.variant_id").
+			Where("o.status IN ?", soldStatuses).
+			Group("pv.product_id")
+		query = query.Joins("LEFT JOIN (?) AS sales ON sales.product_id = products.id", sales).
+			Order("COALESCE(sales.sold, 0) " + search.SortOrder)
+	}
+	// ties keep a stable order across pages
+	query = query.Order("products.id DESC")
+
+	if err := query.Preload("ProductVariant").Preload("Image", helper.ImagesInOrder).Find(&productList).Error; err != nil {
+		pr.log.Error("Error searching products", zap.Error(err))
+		return nil, nil, 0, 0, err
+	}
+
+	facets, err := pr.searchFacets(search)
+	if err != nil {
+		pr.log.Error("Error counting product facets", zap.Error(err))
+		return nil, nil, 0, 0, err
+	}
+
+	totalPages := int(math.Ceil(float64(count) / float64(limit)))
+
+	pr.log.Info("Successfully searched products", zap.Int("totalCount", int(count)), zap.Int("totalPages", totalPages))
+	return &productList, facets, int(count), totalPages, nil
+}
+
+// searchFacets counts the matching products per variant size, variant color
+// and category. Each facet leaves its own filter out so the other values stay
+// selectable.
+func (pr *productRepo) searchFacets(search domain.ProductSearch) (*domain.ProductFacets, error) {
+	facets := domain.ProductFacets{
+		Size:     []domain.Facet{},
+		Color:    []domain.Facet{},
+		Category: []domain.CategoryFacet{},
+	}
+
+	bySize, byColor := search, search
+	bySize.Size, byColor.Color = "", ""
+	for column, facet := range map[string]struct {
+		search domain.ProductSearch
+		values *[]domain.Facet
+	}{"size": {bySize, &facets.Size}, "color": {byColor, &facets.Color}} {
+		matched := pr.db.Model(&domain.Product{}).Scopes(searchProducts(facet.search)).Select("products.id")
+		err := pr.db.Table("product_variants AS pv").
+			Select("pv."+column+" AS value, COUNT(DISTINCT pv.product_id) AS count").
+			Where("pv.product_id IN (?)", matched).
+			Where("pv.deleted_at IS NULL AND pv." + column + " <> ''").
+			Scopes(variantFilters(facet.search)).
+			Group("pv." + column).
+			Order("pv." + column).
+			Scan(facet.values).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	byCategory := search
+	byCategory.CategoryID = nil
+	matched := pr.db.Model(&domain.Product{}).Scopes(searchProducts(byCategory)).Select("products.id")
+	err := pr.db.Table("product_categories AS pc").
+		Select("categories.id, categories.name, COUNT(*) AS count").
+		Joins("JOIN categories ON categories.id = pc.category_id").
+		Where("pc.product_id IN (?)", matched).
+		Group("categories.id, categories.name").
+		Order("categories.name").
+		Scan(&facets.Category).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &facets, nil
+}
+
+func searchProducts(search domain.ProductSearch) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = helper.ActiveProducts(db)
+		if search.Query != "" {
+			db = db.Where(searchDocument+" @@ plainto_tsquery('simple', ?)", search.Query)
+		}
+		if search.CategoryID != nil {
+			subtree := helper.CategorySubtree(db.Session(&gorm.Session{NewDB: true}), *search.CategoryID)
+			db = db.Where("EXISTS (SELECT 1 FROM product_categories pc WHERE pc.product_id = products.id AND pc.category_id IN (?))", subtree)
+		}
+		if search.MinPrice != nil {
+			db = db.Where("products.price >= ?", *search.MinPrice)
+		}
+		if search.MaxPrice != nil {
+			db = db.Where("products.price <= ?", *search.MaxPrice)
+		}
+
+		// size, color, stock and attributes must all hold for the same variant
+		if search.Size != "" || search.Color != "" || search.InStock || len(search.Attributes) > 0 {
+			variants := db.Session(&gorm.Session{NewDB: true}).Table("product_variants AS pv").
+				Select("1").
+				Where("pv.product_id = products.id AND pv.deleted_at IS NULL").
+				Scopes(variantFilters(search))
+			db = db.Where("EXISTS (?)", variants)
+		}
+		return db
+	}
+}
+
+// variantFilters keeps the variants, aliased pv, matching the size, color,
+// stock and attribute filters of a search.
+func variantFilters(search domain.ProductSearch) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if search.Size != "" {
+			db = db.Where("pv.size = ?", search.Size)
+		}
+		if search.Color != "" {
+			db = db.Where("pv.color = ?", search.Color)
+		}
+		if search.InStock {
+			db = db.Where("pv.stock > 0")
+		}
+		names := make([]string, 0, len(search.Attributes))
+		for name := range search.Attributes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			db = db.Where(`EXISTS (SELECT 1 FROM variant_option_values vov
+				JOIN option_values ov ON ov.id = vov.option_value_id
+				JOIN option_types ot ON ot.id = ov.option_type_id
+				WHERE vov.product_variant_id = pv.id AND LOWER(ot.name) = LOWER(?) AND LOWER(ov.value) = LOWER(?))`,
+				name, search.Attributes[name])
+		}
+		return db
+	}
+}
+
+// GetProductByID returns a product whatever its status, for managing it.
 func (pr *productRepo) GetProductByID(id int) (*domain.Product, error) {
+	return pr.getProduct(id)
+}
+
+// GetActiveProductByID returns a product customers can see.
+func (pr *productRepo) GetActiveProductByID(id int) (*domain.Product, error) {
+	return pr.getProduct(id, helper.ActiveProducts)
+}
+
+func (pr *productRepo) getProduct(id int, scopes ...func(*gorm.DB) *gorm.DB) (*domain.Product, error) {
 	pr.log.Info("Fetching product by ID", zap.Int("id", id))
 
 	product := domain.Product{}
 
-	result := pr.db.Model(&product).Where("id = ?", id).
+	result := pr.db.Model(&product).Scopes(scopes...).Where("id = ?", id).
 		Preload("ProductVariant").
-		Preload("Image").First(&product)
+		Preload("Image", helper.ImagesInOrder).
+		Preload("Categories").First(&product)
 
 	if result.Error != nil {
 		pr.log.Error("Error fetching product", zap.Int("id", id), zap.Error(result.Error))
 		return nil, fmt.Errorf("product not found")
 	}
 
+	// reviews are of an ordered variant of the product
+	counts := []domain.RatingCount{}
+	if err := pr.db.Table("reviews").
+		Select("ROUND(reviews.rating)::int AS rating, COUNT(*) AS count").
+		Joins("JOIN order_items ON order_items.id = reviews.order_item_id").
+		Joins("JOIN product_variants ON product_variants.id = order_items.variant_id").
+		Where("product_variants.product_id = ? AND reviews.status = ?", id, domain.ReviewApproved).
+		Group("ROUND(reviews.rating)").
+		Scan(&counts).Error; err != nil {
+		pr.log.Error("Error fetching product rating", zap.Int("id", id), zap.Error(err))
+		return nil, err
+	}
+	rating := domain.NewProductRating(counts)
+	product.Rating = &rating
+
 	pr.log.Info("Successfully fetched product", zap.Int("id", id))
 	return &product, nil
 }
@@ -86,24 +289,49 @@ func (pr *productRepo) CreateProduct(product *domain.Product) error {
 	var err error
 
 	err = pr.db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Create(&product).Error; err != nil {
+		if err := tx.Omit(clause.Associations).Create(&product).Error; err != nil {
 			pr.log.Error("Failed to create product", zap.String("productName", product.Name), zap.Error(err))
 			return fmt.Errorf("failed to create product: %w", err)
 		}
 
-		if err := tx.Create(&domain.ProductVariant{
-			ProductID: product.ID,
-		}).Error; err != nil {
-			pr.log.Error("Failed to create product variant", zap.Error(err))
-			return fmt.Errorf("failed to create product variant: %w", err)
+		if product.CategoryID != nil {
+			if err := tx.Create(&domain.ProductCategory{ProductID: product.ID, CategoryID: *product.CategoryID}).Error; err != nil {
+				pr.log.Error("Failed to link product category", zap.Error(err))
+				return fmt.Errorf("failed to link product category: %w", err)
+			}
 		}
 
-		if err := tx.Create(&domain.Image{
-			ProductID: product.ID,
-		}).Error; err != nil {
-			pr.log.Error("Failed to create image", zap.Error(err))
-			log.Printf("failed to create image: %v", err)
-			return fmt.Errorf("failed to create image: %w", err)
+		variantIds := []int{}
+		for _, variant := range product.ProductVariant {
+			variant.ProductID = product.ID
+			if err := tx.Omit("OptionValues").Create(variant).Error; err != nil {
+				pr.log.Error("Failed to create product variant", zap.Error(err))
+				return fmt.Errorf("failed to create product variant: %w", err)
+			}
+			variantIds = append(variantIds, variant.ID)
+		}
+		if len(variantIds) > 0 {
+			if err := database.LinkLegacyOptions(tx, variantIds...); err != nil {
+				pr.log.Error("Failed to link variant options", zap.Error(err))
+				return fmt.Errorf("failed to link variant options: %w", err)
+			}
+		}
+
+		for i, image := range product.Image {
+			image.ProductID = product.ID
+			image.Position = i
+			image.IsPrimary = i == 0
+			if err := tx.Create(image).Error; err != nil {
+				pr.log.Error("Failed to create image", zap.Error(err))
+				log.Printf("failed to create image: %v", err)
+				return fmt.Errorf("failed to create image: %w", err)
+			}
+		}
+
+		history := domain.NewPriceHistory(*product, domain.PriceCreated, product.CreatedAt)
+		if err := tx.Create(&history).Error; err != nil {
+			pr.log.Error("Failed to record product price", zap.Error(err))
+			return fmt.Errorf("failed to record product price: %w", err)
 		}
 
 		wg.Wait()
@@ -129,17 +357,43 @@ func (pr *productRepo) CreateProduct(product *domain.Product) error {
 func (pr *productRepo) UpdateProduct(productID uint, product *domain.Product) error {
 	pr.log.Info("Updating product", zap.Uint("productID", productID), zap.String("productName", product.Name))
 
-	result := pr.db.Model(&product).
-		Where("id = ?", productID).Updates(product)
+	err := pr.db.Transaction(func(tx *gorm.DB) error {
+		var current domain.Product
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&current, productID).Error; err != nil {
+			pr.log.Warn("No record found to update", zap.Uint("productID", productID))
+			return fmt.Errorf("no record found with shipping_id %d", productID)
+		}
 
-	if result.Error != nil {
-		pr.log.Error("Failed to update product", zap.Uint("productID", productID), zap.Error(result.Error))
-		return result.Error
-	}
+		// the lifecycle and the sale have their own endpoints
+		result := tx.Model(&product).
+			Omit("status", "publish_at", "sale_price", "sale_starts_at", "sale_ends_at").
+			Where("id = ?", productID).Updates(product)
+		if result.Error != nil {
+			pr.log.Error("Failed to update product", zap.Uint("productID", productID), zap.Error(result.Error))
+			return result.Error
+		}
 
-	if result.RowsAffected == 0 {
-		pr.log.Warn("No record found to update", zap.Uint("productID", productID))
-		return fmt.Errorf("no record found with shipping_id %d", productID)
+		if product.Price != 0 && product.Price != current.Price {
+			current.Price = product.Price
+			history := domain.NewPriceHistory(current, domain.PriceManual, time.Now())
+			if err := tx.Create(&history).Error; err != nil {
+				pr.log.Error("Failed to record product price", zap.Uint("productID", productID), zap.Error(err))
+				return err
+			}
+		}
+
+		// a new primary category is linked like any other assigned category
+		if product.CategoryID != nil {
+			link := domain.ProductCategory{ProductID: int(productID), CategoryID: *product.CategoryID}
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&link).Error; err != nil {
+				pr.log.Error("Failed to link product category", zap.Uint("productID", productID), zap.Error(err))
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	pr.log.Info("Successfully updated product", zap.Uint("productID", productID))
@@ -150,7 +404,10 @@ func (pr *productRepo) DeleteProduct(id int) error {
 
 	pr.log.Info("Deleting product", zap.Int("productID", id))
 
-	result := pr.db.Delete(&domain.Product{}, id)
+	// deleting archives the product, its variants stay for the order history
+	result := pr.db.Model(&domain.Product{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"status": domain.ProductArchived, "deleted_at": time.Now()})
 	if result.Error != nil {
 		pr.log.Error("Failed to delete product", zap.Int("productID", id), zap.Error(result.Error))
 		return result.Error
@@ -164,3 +421,150 @@ func (pr *productRepo) DeleteProduct(id int) error {
 	pr.log.Info("Successfully deleted product", zap.Int("productID", id))
 	return nil
 }
+
+// AssignCategory links the product to a category. The first category of a
+// product, or one assigned as primary, becomes its primary category.
+func (pr *productRepo) AssignCategory(productID int, assignment domain.CategoryAssignment) error {
+	pr.log.Info("Assigning category", zap.Int("productID", productID), zap.Uint("categoryID", assignment.CategoryID))
+
+	err := pr.db.Transaction(func(tx *gorm.DB) error {
+		var product domain.Product
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&product, productID).Error; err != nil {
+			return fmt.Errorf("product not found")
+		}
+
+		var category domain.Category
+		if err := tx.First(&category, assignment.CategoryID).Error; err != nil {
+			return fmt.Errorf("category not found")
+		}
+
+		link := domain.ProductCategory{ProductID: product.ID, CategoryID: category.ID}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&link).Error; err != nil {
+			return err
+		}
+
+		if assignment.Primary || product.CategoryID == nil {
+			return tx.Model(&product).Update("category_id", category.ID).Error
+		}
+		return nil
+	})
+
+	if err != nil {
+		pr.log.Error("Failed to assign category", zap.Int("productID", productID), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// UnassignCategory unlinks the product from a category. When it was the
+// primary category the next linked one takes its place.
+func (pr *productRepo) UnassignCategory(productID int, categoryID uint) error {
+	pr.log.Info("Unassigning category", zap.Int("productID", productID), zap.Uint("categoryID", categoryID))
+
+	err := pr.db.Transaction(func(tx *gorm.DB) error {
+		var product domain.Product
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&product, productID).Error; err != nil {
+			return fmt.Errorf("product not found")
+		}
+
+		result := tx.Where("product_id = ? AND category_id = ?", productID, categoryID).Delete(&domain.ProductCategory{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("category is not assigned to the product")
+		}
+
+		if product.CategoryID == nil || *product.CategoryID != categoryID {
+			return nil
+		}
+		return tx.Model(&product).
+			Update("category_id", gorm.Expr("(SELECT MIN(category_id) FROM product_categories WHERE product_id = ?)", productID)).Error
+	})
+
+	if err != nil {
+		pr.log.Error("Failed to unassign category", zap.Int("productID", productID), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// ShowAdminProducts lists the products in every status, or in the given one.
+// Archived products are only listed when asked for.
+func (pr *productRepo) ShowAdminProducts(page, limit int, status domain.ProductStatus) (*[]domain.Product, int, int, error) {
+	pr.log.Info("Fetching admin products", zap.Int("page", page), zap.Int("limit", limit), zap.String("status", string(status)))
+
+	query := pr.db.Model(&domain.Product{})
+	switch status {
+	case "":
+	case domain.ProductArchived:
+		query = query.Unscoped().Where("products.status = ?", status)
+	default:
+		query = query.Where("products.status = ?", status)
+	}
+	query = query.Session(&gorm.Session{})
+
+	productList := []domain.Product{}
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		pr.log.Error("Error counting products", zap.Error(err))
+		return nil, 0, 0, err
+	}
+
+	if err := query.Scopes(helper.Paginate(uint(page), uint(limit))).
+		Preload("ProductVariant").
+		Preload("Image", helper.ImagesInOrder).
+		Order("products.id DESC").
+		Find(&productList).Error; err != nil {
+		pr.log.Error("Error fetching products", zap.Error(err))
+		return nil, 0, 0, err
+	}
+
+	totalPages := int(math.Ceil(float64(count) / float64(limit)))
+	return &productList, int(count), totalPages, nil
+}
+
+// ChangeStatus moves a product to another status. Archiving soft deletes the
+// product and leaving the archive restores it.
+func (pr *productRepo) ChangeStatus(id int, change domain.ProductStatusChange) (*domain.Product, error) {
+	pr.log.Info("Changing product status", zap.Int("productID", id), zap.String("status", string(change.Status)))
+
+	var product domain.Product
+	err := pr.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).First(&product, id).Error; err != nil {
+			return fmt.Errorf("product not found")
+		}
+		if err := change.Apply(&product, time.Now()); err != nil {
+			return err
+		}
+
+		var deletedAt interface{}
+		if product.Status == domain.ProductArchived {
+			deletedAt = time.Now()
+		}
+		return tx.Unscoped().Model(&product).UpdateColumns(map[string]interface{}{
+			"status":     product.Status,
+			"publish_at": product.PublishAt,
+			"deleted_at": deletedAt,
+		}).Error
+	})
+
+	if err != nil {
+		pr.log.Error("Failed to change product status", zap.Int("productID", id), zap.Error(err))
+		return nil, err
+	}
+	return &product, nil
+}
+
+// ActivateScheduled makes the scheduled products whose publish time has come
+// active and returns how many there were.
+func (pr *productRepo) ActivateScheduled(now time.Time) (int64, error) {
+	result := pr.db.Model(&domain.Product{}).
+		Where("status = ? AND publish_at <= ?", domain.ProductScheduled, now).
+		UpdateColumn("status", domain.ProductActive)
+	if result.Error != nil {
+		pr.log.Error("Failed to activate scheduled products", zap.Error(result.Error))
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
@@ -2,6 +2,7 @@ package productrepository
 
 import (
 	"project/domain"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -18,6 +19,14 @@ func (pr *ProductRepoMock) ShowAllProduct(page, limit int) (*[]domain.Product, i
 	return nil, 0, 0, args.Error(3)
 }
 
+func (pr *ProductRepoMock) SearchProducts(page, limit int, search domain.ProductSearch) (*[]domain.Product, *domain.ProductFacets, int, int, error) {
+	args := pr.Called(page, limit, search)
+	if products := args.Get(0); products != nil {
+		return products.(*[]domain.Product), args.Get(1).(*domain.ProductFacets), args.Int(2), args.Int(3), args.Error(4)
+	}
+	return nil, nil, 0, 0, args.Error(4)
+}
+
 func (pr *ProductRepoMock) GetProductByID(id int) (*domain.Product, error) {
 	args := pr.Called(id)
 	if product, ok := args.Get(0).(*domain.Product); ok {
@@ -26,6 +35,14 @@ func (pr *ProductRepoMock) GetProductByID(id int) (*domain.Product, error) {
 	return nil, args.Error(1)
 }
 
+func (pr *ProductRepoMock) GetActiveProductByID(id int) (*domain.Product, error) {
+	args := pr.Called(id)
+	if product, ok := args.Get(0).(*domain.Product); ok {
+		return product, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 func (pr *ProductRepoMock) CreateProduct(product *domain.Product) error {
 	args := pr.Called(product)
 	return args.Error(0)
@@ -40,3 +57,34 @@ func (pr *ProductRepoMock) UpdateProduct(productID uint, product *domain.Product
 	args := pr.Called(productID, product)
 	return args.Error(0)
 }
+
+func (pr *ProductRepoMock) AssignCategory(productID int, assignment domain.CategoryAssignment) error {
+	args := pr.Called(productID, assignment)
+	return args.Error(0)
+}
+
+func (pr *ProductRepoMock) UnassignCategory(productID int, categoryID uint) error {
+	args := pr.Called(productID, categoryID)
+	return args.Error(0)
+}
+
+func (pr *ProductRepoMock) ShowAdminProducts(page, limit int, status domain.ProductStatus) (*[]domain.Product, int, int, error) {
+	args := pr.Called(page, limit, status)
+	if products := args.Get(0); products != nil {
+		return products.(*[]domain.Product), args.Int(1), args.Int(2), args.Error(3)
+	}
+	return nil, 0, 0, args.Error(3)
+}
+
+func (pr *ProductRepoMock) ChangeStatus(id int, change domain.ProductStatusChange) (*domain.Product, error) {
+	args := pr.Called(id, change)
+	if product, ok := args.Get(0).(*domain.Product); ok {
+		return product, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (pr *ProductRepoMock) ActivateScheduled(now time.Time) (int64, error) {
+	args := pr.Called(now)
+	return args.Get(0).(int64), args.Error(1)
+}
@@ -25,12 +25,12 @@ func TestShowAllProduct(t *testing.T) {
 	t.Run("Successfully show all products", func(t *testing.T) {
 		page, limit := 1, 2
 
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "products" WHERE "products"."deleted_at" IS NULL`)).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "products" WHERE products.status = $1 AND "products"."deleted_at" IS NULL`)).
 			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
 
 		// Mock data query with pagination
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "products" WHERE "products"."deleted_at" IS NULL LIMIT $1`)).
-			WithArgs(2).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "products" WHERE products.status = $1 AND "products"."deleted_at" IS NULL LIMIT $2`)).
+			WithArgs(domain.ProductActive, 2).
 			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
 				AddRow(1, "Product A").
 				AddRow(2, "Product B"))
@@ -62,7 +62,7 @@ func TestShowAllProduct(t *testing.T) {
 		page, limit := 1, 2
 
 		// Mock count query
-		mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "products" WHERE "products"."deleted_at" IS NULL`)).
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "products" WHERE products.status = $1 AND "products"."deleted_at" IS NULL`)).
 			WillReturnError(fmt.Errorf("database error"))
 
 		// Call the repository method
@@ -92,6 +92,16 @@ func TestGetProductByID(t *testing.T) {
 			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
 				AddRow(1, "Product A"))
 
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "product_categories" WHERE "product_categories"."product_id" = $1`)).
+			WithArgs(productID).
+			WillReturnRows(sqlmock.NewRows([]string{"product_id", "category_id"}).
+				AddRow(1, 2))
+
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "categories" WHERE "categories"."id" = $1`)).
+			WithArgs(2).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+				AddRow(2, "Livestock"))
+
 		mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "images" WHERE "images"."product_id" = $1`)).
 			WithArgs(productID).
 			WillReturnRows(sqlmock.NewRows([]string{"id", "product_id"}).
@@ -104,6 +114,12 @@ func TestGetProductByID(t *testing.T) {
 				AddRow(1, 1).
 				AddRow(2, 1))
 
+		mock.ExpectQuery(regexp.QuoteMeta(`SELECT ROUND(reviews.rating)::int AS rating, COUNT(*) AS count FROM "reviews" JOIN order_items ON order_items.id = reviews.order_item_id JOIN product_variants ON product_variants.id = order_items.variant_id WHERE product_variants.product_id = $1 AND reviews.status = $2 GROUP BY ROUND(reviews.rating)`)).
+			WithArgs(productID, domain.ReviewApproved).
+			WillReturnRows(sqlmock.NewRows([]string{"rating", "count"}).
+				AddRow(5, 3).
+				AddRow(2, 1))
+
 		product, err := productRepo.GetProductByID(productID)
 
 		assert.NoError(t, err)
@@ -112,6 +128,10 @@ func TestGetProductByID(t *testing.T) {
 		assert.Equal(t, "Product A", product.Name)
 		assert.Len(t, product.ProductVariant, 2)
 		assert.Len(t, product.Image, 2)
+		assert.Len(t, product.Categories, 1)
+		assert.Equal(t, 4, product.Rating.Count)
+		assert.Equal(t, 4.25, product.Rating.Average)
+		assert.Equal(t, 3, product.Rating.Distribution[5])
 	})
 
 	t.Run("Failed to get product by ID due to not found", func(t *testing.T) {
@@ -129,6 +149,19 @@ func TestGetProductByID(t *testing.T) {
 	})
 }
 
+// expectLegacyOptions expects the size and color of the created variant to be
+// linked to the existing Size and Color option types.
+func expectLegacyOptions(mock sqlmock.Sqlmock) {
+	for id := 1; id <= 2; id++ {
+		mock.ExpectQuery(`SELECT \* FROM "option_types"`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(id))
+		mock.ExpectExec(`INSERT INTO option_values`).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`DELETE FROM variant_option_values`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`INSERT INTO variant_option_values`).WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectExec(`UPDATE product_variants SET option_key`).WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
 func TestCreateProduct(t *testing.T) {
 	db, mock := helper.SetupTestDB()
 	defer func() { _ = mock.ExpectationsWereMet() }()
@@ -144,6 +177,12 @@ func TestCreateProduct(t *testing.T) {
 			Description: "High-quality product",
 			CreatedAt:   time.Now(),
 			UpdatedAt:   time.Now(),
+			ProductVariant: []*domain.ProductVariant{
+				{Size: "M", Color: "Red"},
+			},
+			Image: []*domain.Image{
+				{URLPath: "https://cdn.example.com/a.png"},
+			},
 		}
 
 		// Mock database queries
@@ -153,7 +192,13 @@ func TestCreateProduct(t *testing.T) {
 				product.Name,
 				product.SKUProduct,
 				product.Price,
+				nil,
+				nil,
+				nil,
 				product.Description,
+				product.CategoryID,
+				domain.ProductActive,
+				sqlmock.AnyArg(),
 				sqlmock.AnyArg(),
 				sqlmock.AnyArg(),
 				sqlmock.AnyArg(),
@@ -164,6 +209,10 @@ func TestCreateProduct(t *testing.T) {
 			WithArgs(
 				1,
 				sqlmock.AnyArg(),
+				"M",
+				"Red",
+				sqlmock.AnyArg(),
+				sqlmock.AnyArg(),
 				sqlmock.AnyArg(),
 				sqlmock.AnyArg(),
 				sqlmock.AnyArg(),
@@ -172,6 +221,8 @@ func TestCreateProduct(t *testing.T) {
 			).
 			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 
+		expectLegacyOptions(mock)
+
 		mock.ExpectQuery(`INSERT INTO "images"`).
 			WithArgs(
 				1,
@@ -179,9 +230,20 @@ func TestCreateProduct(t *testing.T) {
 				sqlmock.AnyArg(),
 				sqlmock.AnyArg(),
 				sqlmock.AnyArg(),
+				sqlmock.AnyArg(),
+				sqlmock.AnyArg(),
+				0,
+				true,
+				sqlmock.AnyArg(),
+				sqlmock.AnyArg(),
+				sqlmock.AnyArg(),
 			).
 			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 
+		mock.ExpectQuery(`INSERT INTO "price_histories"`).
+			WithArgs(1, product.Price, nil, nil, nil, domain.PriceCreated, sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
 		mock.ExpectCommit()
 
 		err := productRepo.CreateProduct(product)
@@ -198,6 +260,12 @@ func TestCreateProduct(t *testing.T) {
 			Description: "High-quality product",
 			CreatedAt:   time.Now(),
 			UpdatedAt:   time.Now(),
+			ProductVariant: []*domain.ProductVariant{
+				{Size: "M", Color: "Red"},
+			},
+			Image: []*domain.Image{
+				{URLPath: "https://cdn.example.com/a.png"},
+			},
 		}
 
 		mock.ExpectBegin()
@@ -206,7 +274,13 @@ func TestCreateProduct(t *testing.T) {
 				product.Name,
 				product.SKUProduct,
 				product.Price,
+				nil,
+				nil,
+				nil,
 				product.Description,
+				product.CategoryID,
+				domain.ProductActive,
+				sqlmock.AnyArg(),
 				sqlmock.AnyArg(),
 				sqlmock.AnyArg(),
 				sqlmock.AnyArg(),
@@ -229,6 +303,12 @@ func TestCreateProduct(t *testing.T) {
 			Description: "High-quality product",
 			CreatedAt:   time.Now(),
 			UpdatedAt:   time.Now(),
+			ProductVariant: []*domain.ProductVariant{
+				{Size: "M", Color: "Red"},
+			},
+			Image: []*domain.Image{
+				{URLPath: "https://cdn.example.com/a.png"},
+			},
 		}
 
 		mock.ExpectBegin()
@@ -237,7 +317,13 @@ func TestCreateProduct(t *testing.T) {
 				product.Name,
 				product.SKUProduct,
 				product.Price,
+				nil,
+				nil,
+				nil,
 				product.Description,
+				product.CategoryID,
+				domain.ProductActive,
+				sqlmock.AnyArg(),
 				sqlmock.AnyArg(),
 				sqlmock.AnyArg(),
 				sqlmock.AnyArg(),
@@ -248,6 +334,10 @@ func TestCreateProduct(t *testing.T) {
 			WithArgs(
 				1,
 				sqlmock.AnyArg(),
+				"M",
+				"Red",
+				sqlmock.AnyArg(),
+				sqlmock.AnyArg(),
 				sqlmock.AnyArg(),
 				sqlmock.AnyArg(),
 				sqlmock.AnyArg(),
@@ -272,6 +362,12 @@ func TestCreateProduct(t *testing.T) {
 			Description: "High-quality product",
 			CreatedAt:   time.Now(),
 			UpdatedAt:   time.Now(),
+			ProductVariant: []*domain.ProductVariant{
+				{Size: "M", Color: "Red"},
+			},
+			Image: []*domain.Image{
+				{URLPath: "https://cdn.example.com/a.png"},
+			},
 		}
 
 		mock.ExpectBegin()
@@ -280,7 +376,13 @@ func TestCreateProduct(t *testing.T) {
 				product.Name,
 				product.SKUProduct,
 				product.Price,
+				nil,
+				nil,
+				nil,
 				product.Description,
+				product.CategoryID,
+				domain.ProductActive,
+				sqlmock.AnyArg(),
 				sqlmock.AnyArg(),
 				sqlmock.AnyArg(),
 				sqlmock.AnyArg(),
@@ -291,6 +393,10 @@ func TestCreateProduct(t *testing.T) {
 			WithArgs(
 				1,
 				sqlmock.AnyArg(),
+				"M",
+				"Red",
+				sqlmock.AnyArg(),
+				sqlmock.AnyArg(),
 				sqlmock.AnyArg(),
 				sqlmock.AnyArg(),
 				sqlmock.AnyArg(),
@@ -299,6 +405,8 @@ func TestCreateProduct(t *testing.T) {
 			).
 			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 
+		expectLegacyOptions(mock)
+
 		mock.ExpectQuery(`INSERT INTO "images"`).
 			WithArgs(
 				1,
@@ -306,6 +414,13 @@ func TestCreateProduct(t *testing.T) {
 				sqlmock.AnyArg(),
 				sqlmock.AnyArg(),
 				sqlmock.AnyArg(),
+				sqlmock.AnyArg(),
+				sqlmock.AnyArg(),
+				0,
+				true,
+				sqlmock.AnyArg(),
+				sqlmock.AnyArg(),
+				sqlmock.AnyArg(),
 			).
 			WillReturnError(fmt.Errorf("failed to insert image"))
 
@@ -329,9 +444,9 @@ func TestDeleteProduct(t *testing.T) {
 		productID := 1
 
 		mock.ExpectBegin()
-		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "products" SET "deleted_at"=$1 WHERE "products"."id" = $2 AND "products"."deleted_at" IS NULL`)).
-			WithArgs(sqlmock.AnyArg(), productID).    // AnyArg untuk timestamp, dan ID produk
-			WillReturnResult(sqlmock.NewResult(0, 1)) // Simulasi penghapusan sukses
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "products" SET "deleted_at"=$1,"status"=$2,"updated_at"=$3 WHERE id = $4 AND "products"."deleted_at" IS NULL`)).
+			WithArgs(sqlmock.AnyArg(), domain.ProductArchived, sqlmock.AnyArg(), productID). // AnyArg untuk timestamp, dan ID produk
+			WillReturnResult(sqlmock.NewResult(0, 1))                                        // Simulasi penghapusan sukses
 
 		mock.ExpectCommit()
 
@@ -345,9 +460,9 @@ func TestDeleteProduct(t *testing.T) {
 
 		mock.ExpectBegin()
 
-		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "products" SET "deleted_at"=$1 WHERE "products"."id" = $2 AND "products"."deleted_at" IS NULL`)).
-			WithArgs(sqlmock.AnyArg(), productID).    // `deleted_at` timestamp dan ID produk
-			WillReturnResult(sqlmock.NewResult(0, 0)) // 0 rows affected (no product found)
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "products" SET "deleted_at"=$1,"status"=$2,"updated_at"=$3 WHERE id = $4 AND "products"."deleted_at" IS NULL`)).
+			WithArgs(sqlmock.AnyArg(), domain.ProductArchived, sqlmock.AnyArg(), productID). // `deleted_at` timestamp dan ID produk
+			WillReturnResult(sqlmock.NewResult(0, 0))                                        // 0 rows affected (no product found)
 
 		mock.ExpectCommit()
 
@@ -362,8 +477,8 @@ func TestDeleteProduct(t *testing.T) {
 
 		mock.ExpectBegin()
 
-		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "products" SET "deleted_at"=$1 WHERE "products"."id" = $2 AND "products"."deleted_at" IS NULL`)).
-			WithArgs(sqlmock.AnyArg(), productID). // `deleted_at` timestamp dan ID produk
+		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "products" SET "deleted_at"=$1,"status"=$2,"updated_at"=$3 WHERE id = $4 AND "products"."deleted_at" IS NULL`)).
+			WithArgs(sqlmock.AnyArg(), domain.ProductArchived, sqlmock.AnyArg(), productID). // `deleted_at` timestamp dan ID produk
 			WillReturnError(fmt.Errorf("database error"))
 
 		mock.ExpectRollback()
@@ -383,6 +498,9 @@ func TestUpdateProduct(t *testing.T) {
 	log := *zap.NewNop()
 	productRepo := productrepository.NewProductRepo(db, &log)
 
+	lockProduct := regexp.QuoteMeta(`SELECT * FROM "products" WHERE "products"."id" = $1 AND "products"."deleted_at" IS NULL ORDER BY "products"."id" LIMIT $2 FOR UPDATE`)
+	updateProduct := regexp.QuoteMeta(`UPDATE "products" SET "name"=$1,"sku_product"=$2,"price"=$3,"description"=$4,"updated_at"=$5 WHERE id = $6 AND "products"."deleted_at" IS NULL`)
+
 	t.Run("Successfully update a product", func(t *testing.T) {
 		productID := uint(1)
 		product := &domain.Product{
@@ -393,7 +511,10 @@ func TestUpdateProduct(t *testing.T) {
 		}
 
 		mock.ExpectBegin()
-		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "products" SET "name"=$1,"sku_product"=$2,"price"=$3,"description"=$4,"updated_at"=$5 WHERE id = $6 AND "products"."deleted_at" IS NULL`)).
+		mock.ExpectQuery(lockProduct).
+			WithArgs(productID, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "price"}).AddRow(1, 100))
+		mock.ExpectExec(updateProduct).
 			WithArgs(
 				product.Name,
 				product.SKUProduct,
@@ -403,14 +524,41 @@ func TestUpdateProduct(t *testing.T) {
 				productID,
 			).
 			WillReturnResult(sqlmock.NewResult(1, 1)) // 1 row affected
+		mock.ExpectQuery(`INSERT INTO "price_histories"`).
+			WithArgs(1, product.Price, nil, nil, nil, domain.PriceManual, sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectCommit()
+
+		err := productRepo.UpdateProduct(productID, product)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Same price isn't recorded", func(t *testing.T) {
+		productID := uint(1)
+		product := &domain.Product{
+			Name:        "Updated Product",
+			SKUProduct:  "SKI-2022",
+			Price:       150,
+			Description: "Updated description",
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockProduct).
+			WithArgs(productID, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "price"}).AddRow(1, 150))
+		mock.ExpectExec(updateProduct).
+			WithArgs(product.Name, product.SKUProduct, product.Price, product.Description, sqlmock.AnyArg(), productID).
+			WillReturnResult(sqlmock.NewResult(1, 1))
 		mock.ExpectCommit()
 
 		err := productRepo.UpdateProduct(productID, product)
 
 		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
-	t.Run("Failed to update product - No rows affected", func(t *testing.T) {
+	t.Run("Failed to update product - Product not found", func(t *testing.T) {
 		productID := uint(2)
 		product := &domain.Product{
 			Name:        "Another Product",
@@ -420,17 +568,10 @@ func TestUpdateProduct(t *testing.T) {
 		}
 
 		mock.ExpectBegin()
-		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "products" SET "name"=$1,"sku_product"=$2,"price"=$3,"description"=$4,"updated_at"=$5 WHERE id = $6 AND "products"."deleted_at" IS NULL`)).
-			WithArgs(
-				product.Name,
-				product.SKUProduct,
-				product.Price,
-				product.Description,
-				sqlmock.AnyArg(), // Updated at timestamp
-				productID,
-			).
-			WillReturnResult(sqlmock.NewResult(0, 0))
-		mock.ExpectCommit()
+		mock.ExpectQuery(lockProduct).
+			WithArgs(productID, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "price"}))
+		mock.ExpectRollback()
 
 		err := productRepo.UpdateProduct(productID, product)
 
@@ -448,7 +589,10 @@ func TestUpdateProduct(t *testing.T) {
 		}
 
 		mock.ExpectBegin()
-		mock.ExpectExec(regexp.QuoteMeta(`UPDATE "products" SET "name"=$1,"sku_product"=$2,"price"=$3,"description"=$4,"updated_at"=$5 WHERE id = $6 AND "products"."deleted_at" IS NULL`)).
+		mock.ExpectQuery(lockProduct).
+			WithArgs(productID, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "price"}).AddRow(3, 300))
+		mock.ExpectExec(updateProduct).
 			WithArgs(
 				product.Name,
 				product.SKUProduct,
@@ -466,3 +610,23 @@ func TestUpdateProduct(t *testing.T) {
 		assert.EqualError(t, err, "database error")
 	})
 }
+
+func TestActivateScheduled(t *testing.T) {
+	db, mock := helper.SetupTestDB()
+	defer func() { _ = mock.ExpectationsWereMet() }()
+
+	log := *zap.NewNop()
+	productRepo := productrepository.NewProductRepo(db, &log)
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "products" SET "status"=$1 WHERE (status = $2 AND publish_at <= $3) AND "products"."deleted_at" IS NULL`)).
+		WithArgs(domain.ProductActive, domain.ProductScheduled, now).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	count, err := productRepo.ActivateScheduled(now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+}
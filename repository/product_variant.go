@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"project/database"
+	"project/domain"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RepositoryProductVariant interface {
+	FindByProduct(productId int) ([]domain.ProductVariant, error)
+	FindById(productId, id int) (domain.ProductVariant, error)
+	Insert(productId int, variants []domain.ProductVariant) error
+	Update(variant *domain.ProductVariant) error
+	Delete(variant *domain.ProductVariant) error
+}
+
+type repositoryProductVariant struct {
+	db  *gorm.DB
+	log *zap.Logger
+}
+
+func NewRepositoryProductVariant(db *gorm.DB, log *zap.Logger) RepositoryProductVariant {
+	return &repositoryProductVariant{db, log}
+}
+
+func (repo *repositoryProductVariant) FindByProduct(productId int) ([]domain.ProductVariant, error) {
+	if err := repo.db.First(&domain.Product{}, productId).Error; err != nil {
+		return nil, errors.New("product not found")
+	}
+
+	variants := []domain.ProductVariant{}
+	if err := repo.db.Preload("OptionValues.OptionType").Where("product_id = ?", productId).Order("id").Find(&variants).Error; err != nil {
+		repo.log.Error("Error fetching variants", zap.Int("product_id", productId), zap.Error(err))
+		return nil, errors.New("internal server error")
+	}
+	return variants, nil
+}
+
+func (repo *repositoryProductVariant) FindById(productId, id int) (domain.ProductVariant, error) {
+	variant := domain.ProductVariant{}
+	if err := repo.db.Preload("OptionValues.OptionType").Where("product_id = ?", productId).First(&variant, id).Error; err != nil {
+		return domain.ProductVariant{}, errors.New("variant not found")
+	}
+	return variant, nil
+}
+
+// Insert adds variants to a product and records their opening stock in the
+// stock ledger, all in one transaction.
+func (repo *repositoryProductVariant) Insert(productId int, variants []domain.ProductVariant) error {
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		var product domain.Product
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&product, productId).Error; err != nil {
+			return errors.New("product not found")
+		}
+
+		for i := range variants {
+			variant := &variants[i]
+			variant.ProductID = product.ID
+			if err := resolveOptions(tx, variant); err != nil {
+				return err
+			}
+			if err := uniqueVariant(tx, *variant); err != nil {
+				return err
+			}
+			if err := tx.Omit("OptionValues").Create(variant).Error; err != nil {
+				return err
+			}
+			if err := linkOptions(tx, variant); err != nil {
+				return err
+			}
+
+			if variant.Stock > 0 {
+				stock := domain.Stock{
+					ProductVariantId: variant.ID,
+					Description:      "Stok awal",
+					Qty:              variant.Stock,
+				}
+				if err := tx.Create(&stock).Error; err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		repo.log.Error("Error creating variants", zap.Int("product_id", productId), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// Update saves the editable fields, the stock only changes through the ledger.
+func (repo *repositoryProductVariant) Update(variant *domain.ProductVariant) error {
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := resolveOptions(tx, variant); err != nil {
+			return err
+		}
+		if err := uniqueVariant(tx, *variant); err != nil {
+			return err
+		}
+		if err := tx.Model(variant).Omit("OptionValues").
+			Select("sku", "size", "color", "price", "weight", "option_key").
+			Updates(variant).Error; err != nil {
+			return err
+		}
+		return linkOptions(tx, variant)
+	})
+
+	if err != nil {
+		repo.log.Error("Error updating variant", zap.Int("id", variant.ID), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (repo *repositoryProductVariant) Delete(variant *domain.ProductVariant) error {
+	if err := repo.db.Delete(variant).Error; err != nil {
+		repo.log.Error("Error deleting variant", zap.Int("id", variant.ID), zap.Error(err))
+		return errors.New("failed to delete variant")
+	}
+	return nil
+}
+
+// resolveOptions loads the option values chosen for a variant, checks they
+// apply to the product categories and derives the option key and the size and
+// color columns from them. Variants without option values keep their size and
+// color as given.
+func resolveOptions(tx *gorm.DB, variant *domain.ProductVariant) error {
+	if len(variant.OptionValues) == 0 {
+		variant.OptionKey = ""
+		return nil
+	}
+
+	ids := make([]uint, 0, len(variant.OptionValues))
+	for _, value := range variant.OptionValues {
+		ids = append(ids, value.ID)
+	}
+
+	values := []*domain.OptionValue{}
+	if err := tx.Preload("OptionType").Where("id IN ?", ids).Find(&values).Error; err != nil {
+		return err
+	}
+	if len(values) != len(ids) {
+		return errors.New("option value not found")
+	}
+
+	var allowed int64
+	if err := tx.Model(&domain.OptionValue{}).
+		Joins("JOIN option_types ot ON ot.id = option_values.option_type_id").
+		Where("option_values.id IN ?", ids).
+		Where("ot.category_id IS NULL OR ot.category_id IN (?)", productCategoryAncestors(tx, variant.ProductID)).
+		Count(&allowed).Error; err != nil {
+		return err
+	}
+	if int(allowed) != len(ids) {
+		return errors.New("option values must belong to the product categories")
+	}
+
+	key, err := domain.OptionKey(values)
+	if err != nil {
+		return err
+	}
+	variant.OptionValues = values
+	variant.OptionKey = key
+	variant.SyncLegacyOptions()
+	return nil
+}
+
+// linkOptions stores the links to the chosen option values, or converts the
+// size and color of a variant without them.
+func linkOptions(tx *gorm.DB, variant *domain.ProductVariant) error {
+	if len(variant.OptionValues) == 0 {
+		if err := database.LinkLegacyOptions(tx, variant.ID); err != nil {
+			return err
+		}
+	} else {
+		if err := tx.Where("product_variant_id = ?", variant.ID).Delete(&domain.VariantOptionValue{}).Error; err != nil {
+			return err
+		}
+		for _, value := range variant.OptionValues {
+			link := domain.VariantOptionValue{ProductVariantID: variant.ID, OptionValueID: value.ID}
+			if err := tx.Create(&link).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Preload("OptionValues.OptionType").First(variant, variant.ID).Error
+}
+
+// uniqueVariant checks that no other live variant of the product has the same
+// option values, or the same size and color when it has none, and that the
+// SKU isn't used by another variant.
+func uniqueVariant(tx *gorm.DB, variant domain.ProductVariant) error {
+	var count int64
+	if variant.OptionKey != "" {
+		if err := tx.Model(&domain.ProductVariant{}).
+			Where("product_id = ? AND id <> ? AND option_key = ?", variant.ProductID, variant.ID, variant.OptionKey).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return errors.New("a variant with the same option values already exists")
+		}
+	} else {
+		if err := tx.Model(&domain.ProductVariant{}).
+			Where("product_id = ? AND id <> ?", variant.ProductID, variant.ID).
+			Where("LOWER(size) = LOWER(?) AND LOWER(color) = LOWER(?)", variant.Size, variant.Color).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return fmt.Errorf("variant with size %q and color %q already exists", variant.Size, variant.Color)
+		}
+	}
+
+	if variant.SKU == "" {
+		return nil
+	}
+	if err := tx.Model(&domain.ProductVariant{}).
+		Where("sku = ? AND id <> ?", variant.SKU, variant.ID).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("sku %s is already used", variant.SKU)
+	}
+	return nil
+}
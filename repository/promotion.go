@@ -2,10 +2,15 @@ package repository
 
 import (
 	"errors"
+	"fmt"
+	"math"
 	"project/domain"
+	"project/helper"
+	"time"
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type RepositoryPromotion interface {
@@ -13,6 +18,9 @@ type RepositoryPromotion interface {
 	FindById(id uint) (domain.Promotion, error)
 	Insert(stock *domain.Promotion) error
 	Delete(stock *domain.Promotion) error
+	UpdateSegment(promotion *domain.Promotion) error
+	Evaluate(quote domain.PromotionQuote) (domain.PromotionEvaluation, error)
+	FindUsages(id, page, limit uint) ([]domain.PromotionUsage, int, int, error)
 }
 
 type repositoryPromotion struct {
@@ -49,8 +57,175 @@ func (repo *repositoryPromotion) Insert(promotion *domain.Promotion) error {
 	return nil
 }
 func (repo *repositoryPromotion) Delete(promotion *domain.Promotion) error {
-	if err := repo.db.Exec("DELETE FROM promotions WHERE id = ? RETURNING *", promotion.ID).Scan(&promotion).Error; err != nil {
+	if err := repo.db.Clauses(clause.Returning{}).Delete(promotion).Error; err != nil {
 		return errors.New(" Invalid ID")
 	}
 	return nil
 }
+func (repo *repositoryPromotion) UpdateSegment(promotion *domain.Promotion) error {
+	if err := repo.db.Model(promotion).Update("segment_id", promotion.SegmentID).Error; err != nil {
+		repo.log.Error("Error updating promotion segment", zap.Uint("id", promotion.ID), zap.Error(err))
+		return errors.New(" Internal Server Error")
+	}
+	return nil
+}
+
+// Evaluate prices the quoted items and computes the discount they would get,
+// nothing is reserved until an order is created.
+func (repo *repositoryPromotion) Evaluate(quote domain.PromotionQuote) (domain.PromotionEvaluation, error) {
+	now := time.Now()
+	cart := domain.Cart{CustomerID: quote.CustomerID}
+	for _, item := range quote.Lines() {
+		var variant domain.ProductVariant
+		if err := repo.db.First(&variant, item.VariantID).Error; err != nil {
+			return domain.PromotionEvaluation{}, fmt.Errorf("variant %d not found", item.VariantID)
+		}
+		line, err := cartLine(repo.db, variant, item.Quantity, now)
+		if err != nil {
+			return domain.PromotionEvaluation{}, err
+		}
+		cart.Lines = append(cart.Lines, line)
+	}
+
+	promotion, err := findPromotion(repo.db, repo.log, cart, quote.VoucherCode, now)
+	if err != nil {
+		return domain.PromotionEvaluation{}, err
+	}
+	var discount float64
+	if promotion.ID != 0 {
+		used, err := promotionUsed(repo.db, promotion.ID, cart.CustomerID)
+		if err != nil {
+			repo.log.Error("Error counting promotion usages", zap.Uint("id", promotion.ID), zap.Error(err))
+			return domain.PromotionEvaluation{}, errors.New(" Internal Server Error")
+		}
+		if discount, err = promotion.Evaluate(cart, used, now); err != nil {
+			return domain.PromotionEvaluation{}, err
+		}
+	}
+	return domain.NewPromotionEvaluation(cart, promotion, discount), nil
+}
+
+func (repo *repositoryPromotion) FindUsages(id, page, limit uint) ([]domain.PromotionUsage, int, int, error) {
+	var count int64
+	if err := repo.db.Model(&domain.PromotionUsage{}).Where("promotion_id = ?", id).Count(&count).Error; err != nil {
+		repo.log.Error("Error counting promotion usages", zap.Uint("id", id), zap.Error(err))
+		return nil, 0, 0, errors.New(" Internal Server Error")
+	}
+
+	usages := []domain.PromotionUsage{}
+	if err := repo.db.Where("promotion_id = ?", id).Order("created_at DESC, id DESC").
+		Scopes(helper.Paginate(page, limit)).Find(&usages).Error; err != nil {
+		repo.log.Error("Error fetching promotion usages", zap.Uint("id", id), zap.Error(err))
+		return nil, 0, 0, errors.New(" Internal Server Error")
+	}
+
+	pages := int(math.Ceil(float64(count) / float64(limit)))
+	return usages, int(count), pages, nil
+}
+
+// cartLine prices a variant with the product and categories promotions may
+// target it by, only variants of active products can be bought.
+func cartLine(tx *gorm.DB, variant domain.ProductVariant, quantity uint, now time.Time) (domain.CartLine, error) {
+	var product domain.Product
+	if err := tx.First(&product, variant.ProductID).Error; err != nil {
+		return domain.CartLine{}, fmt.Errorf("product of variant %d not found", variant.ID)
+	}
+	if product.Status != domain.ProductActive {
+		return domain.CartLine{}, fmt.Errorf("product of variant %d is not available", variant.ID)
+	}
+	categoryIds := []uint{}
+	if err := tx.Model(&domain.ProductCategory{}).Where("product_id = ?", product.ID).Pluck("category_id", &categoryIds).Error; err != nil {
+		return domain.CartLine{}, err
+	}
+	return domain.CartLine{
+		VariantID:   uint(variant.ID),
+		ProductID:   product.ID,
+		CategoryIDs: categoryIds,
+		Quantity:    quantity,
+		UnitPrice:   variant.PriceFor(product, now),
+	}, nil
+}
+
+// findPromotion finds the promotion a cart gets, the voucher when a code is
+// given and otherwise the direct discount taking the most off. A zero
+// promotion is returned when no direct discount applies.
+func findPromotion(tx *gorm.DB, log *zap.Logger, cart domain.Cart, voucherCode string, now time.Time) (domain.Promotion, error) {
+	if voucherCode != "" {
+		var promotion domain.Promotion
+		err := tx.Where("voucher_code = ? AND type = ?", voucherCode, domain.Voucher).First(&promotion).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.Promotion{}, errors.New("invalid voucher code")
+		}
+		if err != nil {
+			log.Error("Error fetching voucher", zap.String("voucher_code", voucherCode), zap.Error(err))
+			return domain.Promotion{}, errors.New(" Internal Server Error")
+		}
+		available, err := availableTo(tx, []domain.Promotion{promotion}, cart.CustomerID)
+		if err != nil {
+			log.Error("Error checking voucher segment", zap.Uint("id", promotion.ID), zap.Error(err))
+			return domain.Promotion{}, errors.New(" Internal Server Error")
+		}
+		if len(available) == 0 {
+			return domain.Promotion{}, errors.New("voucher is not available for this customer")
+		}
+		return promotion, nil
+	}
+
+	var promotions []domain.Promotion
+	if err := tx.Where("type = ? AND status = ?", domain.Discount, domain.Active).Find(&promotions).Error; err != nil {
+		log.Error("Error fetching discounts", zap.Error(err))
+		return domain.Promotion{}, errors.New(" Internal Server Error")
+	}
+	candidates, err := availableTo(tx, promotions, cart.CustomerID)
+	if err != nil {
+		log.Error("Error checking discount segments", zap.Uint("customer_id", cart.CustomerID), zap.Error(err))
+		return domain.Promotion{}, errors.New(" Internal Server Error")
+	}
+
+	var usages []struct {
+		PromotionID uint
+		Count       int
+	}
+	if err := tx.Model(&domain.PromotionUsage{}).Select("promotion_id, COUNT(*) AS count").
+		Where("customer_id = ?", cart.CustomerID).Group("promotion_id").Scan(&usages).Error; err != nil {
+		log.Error("Error counting promotion usages", zap.Uint("customer_id", cart.CustomerID), zap.Error(err))
+		return domain.Promotion{}, errors.New(" Internal Server Error")
+	}
+	used := map[uint]int{}
+	for _, usage := range usages {
+		used[usage.PromotionID] = usage.Count
+	}
+
+	best, _ := domain.BestPromotion(candidates, cart, used, now)
+	return best, nil
+}
+
+// availableTo keeps the promotions a customer may use, promotions restricted
+// to a segment are only available to its members.
+func availableTo(tx *gorm.DB, promotions []domain.Promotion, customerId uint) ([]domain.Promotion, error) {
+	segmentIds := []uint{}
+	for _, promotion := range promotions {
+		if promotion.SegmentID != nil {
+			segmentIds = append(segmentIds, *promotion.SegmentID)
+		}
+	}
+	member, err := segmentsOf(tx, customerId, segmentIds)
+	if err != nil {
+		return nil, err
+	}
+
+	available := []domain.Promotion{}
+	for _, promotion := range promotions {
+		if promotion.SegmentID == nil || member[*promotion.SegmentID] {
+			available = append(available, promotion)
+		}
+	}
+	return available, nil
+}
+
+// promotionUsed counts the orders a customer has used a promotion on.
+func promotionUsed(tx *gorm.DB, promotionId, customerId uint) (int, error) {
+	var count int64
+	err := tx.Model(&domain.PromotionUsage{}).Where("promotion_id = ? AND customer_id = ?", promotionId, customerId).Count(&count).Error
+	return int(count), err
+}
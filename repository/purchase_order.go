@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"project/domain"
+	"project/helper"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RepositoryPurchaseOrder interface {
+	FindAll(page, limit uint, status string) (int, int, []domain.PurchaseOrder, error)
+	FindById(id uint) (domain.PurchaseOrder, error)
+	Insert(po *domain.PurchaseOrder) error
+	UpdateItems(po *domain.PurchaseOrder) error
+	UpdateStatus(po *domain.PurchaseOrder) error
+	Receive(id uint, receipt domain.GoodsReceipt) (domain.PurchaseOrder, error)
+	FindOpen() ([]domain.OpenPurchaseOrder, error)
+}
+
+type repositoryPurchaseOrder struct {
+	db  *gorm.DB
+	log *zap.Logger
+}
+
+func NewRepositoryPurchaseOrder(db *gorm.DB, log *zap.Logger) RepositoryPurchaseOrder {
+	return &repositoryPurchaseOrder{db, log}
+}
+
+func (repo *repositoryPurchaseOrder) FindAll(page, limit uint, status string) (int, int, []domain.PurchaseOrder, error) {
+	query := repo.db.Model(&domain.PurchaseOrder{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		repo.log.Error("Error counting purchase orders", zap.Error(err))
+		return 0, 0, nil, err
+	}
+	pages := int(math.Ceil(float64(count) / float64(limit)))
+
+	var purchaseOrders []domain.PurchaseOrder
+	result := query.Scopes(helper.Paginate(page, limit)).
+		Preload("Supplier").
+		Preload("Items").
+		Order("created_at DESC").
+		Find(&purchaseOrders)
+	if result.Error != nil {
+		repo.log.Error("Error fetching purchase orders", zap.Error(result.Error))
+		return 0, 0, nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return 0, 0, nil, errors.New("purchase order not found")
+	}
+	return int(count), pages, purchaseOrders, nil
+}
+
+func (repo *repositoryPurchaseOrder) FindById(id uint) (domain.PurchaseOrder, error) {
+	var po domain.PurchaseOrder
+	if err := repo.db.Preload("Supplier").Preload("Items").First(&po, id).Error; err != nil {
+		return domain.PurchaseOrder{}, errors.New("purchase order not found")
+	}
+	return po, nil
+}
+
+func (repo *repositoryPurchaseOrder) Insert(po *domain.PurchaseOrder) error {
+	po.Status = domain.PurchaseDraft
+	if err := repo.db.Omit("Supplier").Create(po).Error; err != nil {
+		repo.log.Error("Error creating purchase order", zap.Error(err))
+		return errors.New("failed to create purchase order")
+	}
+	return nil
+}
+
+// UpdateItems replaces the supplier, note and line items of a draft purchase order.
+func (repo *repositoryPurchaseOrder) UpdateItems(po *domain.PurchaseOrder) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		var current domain.PurchaseOrder
+		if err := tx.First(&current, po.ID).Error; err != nil {
+			return errors.New("purchase order not found")
+		}
+		if current.Status != domain.PurchaseDraft {
+			return errors.New("only draft purchase orders can be edited")
+		}
+
+		if err := tx.Where("purchase_order_id = ?", po.ID).Delete(&domain.PurchaseOrderItem{}).Error; err != nil {
+			return err
+		}
+		for i := range po.Items {
+			po.Items[i].ID = 0
+			po.Items[i].PurchaseOrderID = po.ID
+			po.Items[i].ReceivedQuantity = 0
+		}
+		if err := tx.Create(&po.Items).Error; err != nil {
+			repo.log.Error("Error creating purchase order items", zap.Error(err))
+			return errors.New("failed to update purchase order")
+		}
+
+		po.Status = current.Status
+		po.CreatedAt = current.CreatedAt
+		return tx.Model(po).Updates(map[string]interface{}{
+			"supplier_id": po.SupplierID,
+			"note":        po.Note,
+		}).Error
+	})
+}
+
+func (repo *repositoryPurchaseOrder) UpdateStatus(po *domain.PurchaseOrder) error {
+	return repo.db.Model(po).Update("status", po.Status).Error
+}
+
+// Receive books a (partial) delivery inside a single transaction: the purchase
+// order items are updated and every received line is written to the stock
+// ledger before the variant stock is increased.
+func (repo *repositoryPurchaseOrder) Receive(id uint, receipt domain.GoodsReceipt) (domain.PurchaseOrder, error) {
+	var po domain.PurchaseOrder
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Items").First(&po, id).Error; err != nil {
+			return errors.New("purchase order not found")
+		}
+
+		if err := po.Receive(receipt); err != nil {
+			return err
+		}
+
+		for _, line := range receipt.Items {
+			item := po.Item(line.ItemID)
+			stock := domain.Stock{
+				ProductVariantId: item.VariantID,
+				Description:      fmt.Sprintf("Penerimaan PO #%d", po.ID),
+				Qty:              int(line.Quantity),
+			}
+			if err := tx.Create(&stock).Error; err != nil {
+				return err
+			}
+
+			if err := tx.Model(&domain.ProductVariant{}).
+				Where("id = ?", item.VariantID).
+				UpdateColumn("stock", gorm.Expr("stock + ?", line.Quantity)).Error; err != nil {
+				return err
+			}
+
+			if err := tx.Model(item).Update("received_quantity", item.ReceivedQuantity).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&po).Update("status", po.Status).Error
+	})
+
+	if err != nil {
+		repo.log.Error("Error receiving purchase order", zap.Uint("id", id), zap.Error(err))
+		return domain.PurchaseOrder{}, err
+	}
+	return po, nil
+}
+
+func (repo *repositoryPurchaseOrder) FindOpen() ([]domain.OpenPurchaseOrder, error) {
+	var report []domain.OpenPurchaseOrder
+	err := repo.db.Table("purchase_orders as po").
+		Select(`po.id, s.name as supplier_name, po.status,
+			SUM(i.quantity) as ordered_quantity,
+			SUM(i.received_quantity) as received_quantity,
+			SUM((i.quantity - i.received_quantity) * i.unit_cost) as outstanding_value,
+			po.created_at`).
+		Joins("JOIN suppliers as s ON s.id = po.supplier_id").
+		Joins("JOIN purchase_order_items as i ON i.purchase_order_id = po.id").
+		Where("po.status IN ?", []domain.PurchaseOrderStatus{domain.PurchaseSent, domain.PurchasePartiallyReceived}).
+		Group("po.id, s.name").
+		Order("po.created_at").
+		Scan(&report).Error
+	if err != nil {
+		repo.log.Error("Error fetching open purchase orders", zap.Error(err))
+		return nil, err
+	}
+	return report, nil
+}
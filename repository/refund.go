@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"project/domain"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RepositoryRefund interface {
+	FindByOrder(orderId uint) ([]domain.Refund, error)
+	FindById(id uint) (domain.Refund, error)
+	Insert(orderId uint, request domain.NewRefund) (domain.Refund, error)
+	UpdateStatus(refund *domain.Refund) error
+	Pay(id uint, paidAt time.Time) (domain.Refund, error)
+}
+
+type repositoryRefund struct {
+	db  *gorm.DB
+	log *zap.Logger
+}
+
+func NewRepositoryRefund(db *gorm.DB, log *zap.Logger) RepositoryRefund {
+	return &repositoryRefund{db, log}
+}
+
+func (repo *repositoryRefund) FindByOrder(orderId uint) ([]domain.Refund, error) {
+	refunds := []domain.Refund{}
+	if err := repo.db.Preload("Items").Where("order_id = ?", orderId).Order("created_at").Find(&refunds).Error; err != nil {
+		repo.log.Error("Error fetching refunds", zap.Uint("order_id", orderId), zap.Error(err))
+		return nil, errors.New("internal server error")
+	}
+	return refunds, nil
+}
+
+func (repo *repositoryRefund) FindById(id uint) (domain.Refund, error) {
+	var refund domain.Refund
+	if err := repo.db.Preload("Items").First(&refund, id).Error; err != nil {
+		return domain.Refund{}, errors.New("refund not found")
+	}
+	return refund, nil
+}
+
+// Insert validates the request against the order, its payments and earlier
+// refunds while the order row is locked, so concurrent requests can't refund
+// more than was paid.
+func (repo *repositoryRefund) Insert(orderId uint, request domain.NewRefund) (domain.Refund, error) {
+	var refund domain.Refund
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		var order domain.Order
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, orderId).Error; err != nil {
+			return errors.New("order not found")
+		}
+		if err := tx.Where("order_id = ?", orderId).Find(&order.Items).Error; err != nil {
+			return err
+		}
+
+		var received, refundedAmount float64
+		if err := tx.Model(&domain.Payment{}).
+			Select("COALESCE(SUM(amount), 0)").
+			Where("order_id = ? AND status IN ?", orderId, receivedStatuses).
+			Scan(&received).Error; err != nil {
+			return err
+		}
+		if received == 0 {
+			return errors.New("order has no payments to refund")
+		}
+		if err := tx.Model(&domain.Refund{}).
+			Select("COALESCE(SUM(amount), 0)").
+			Where("order_id = ? AND status <> ?", orderId, domain.RefundRejected).
+			Scan(&refundedAmount).Error; err != nil {
+			return err
+		}
+
+		var quantities []struct {
+			OrderItemID uint
+			Quantity    uint
+		}
+		if err := tx.Table("refund_items as ri").
+			Select("ri.order_item_id, SUM(ri.quantity) as quantity").
+			Joins("JOIN refunds as r ON r.id = ri.refund_id").
+			Where("r.order_id = ? AND r.status <> ?", orderId, domain.RefundRejected).
+			Group("ri.order_item_id").
+			Scan(&quantities).Error; err != nil {
+			return err
+		}
+		refunded := map[uint]uint{}
+		for _, quantity := range quantities {
+			refunded[quantity.OrderItemID] = quantity.Quantity
+		}
+
+		var err error
+		refund, err = order.NewRefund(request, refunded, received-refundedAmount)
+		if err != nil {
+			return err
+		}
+		return tx.Create(&refund).Error
+	})
+
+	if err != nil {
+		repo.log.Error("Error creating refund", zap.Uint("order_id", orderId), zap.Error(err))
+		return domain.Refund{}, err
+	}
+	return refund, nil
+}
+
+func (repo *repositoryRefund) UpdateStatus(refund *domain.Refund) error {
+	return repo.db.Model(refund).Updates(map[string]interface{}{
+		"status": refund.Status,
+		"note":   refund.Note,
+	}).Error
+}
+
+// Pay books an approved refund: returned items go back into stock through the
+// stock ledger when requested, and the payments of the order are marked as
+// refunded once everything that was paid has been given back.
+func (repo *repositoryRefund) Pay(id uint, paidAt time.Time) (domain.Refund, error) {
+	var refund domain.Refund
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Items").First(&refund, id).Error; err != nil {
+			return errors.New("refund not found")
+		}
+		if err := refund.Pay(paidAt); err != nil {
+			return err
+		}
+		if err := tx.Model(&refund).Updates(map[string]interface{}{
+			"status":  refund.Status,
+			"paid_at": refund.PaidAt,
+		}).Error; err != nil {
+			return err
+		}
+
+		var order domain.Order
+		if err := tx.First(&order, refund.OrderID).Error; err != nil {
+			return errors.New("order not found")
+		}
+		if refund.Restock && order.StockDeducted() {
+			for _, item := range refund.Items {
+				stock := domain.Stock{
+					ProductVariantId: int(item.VariantID),
+					Description:      fmt.Sprintf("Retur pesanan #%d", refund.OrderID),
+					Qty:              int(item.Quantity),
+				}
+				if err := tx.Create(&stock).Error; err != nil {
+					return err
+				}
+				if err := tx.Model(&domain.ProductVariant{}).
+					Where("id = ?", item.VariantID).
+					UpdateColumn("stock", gorm.Expr("stock + ?", item.Quantity)).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		var received, refunded float64
+		if err := tx.Model(&domain.Payment{}).
+			Select("COALESCE(SUM(amount), 0)").
+			Where("order_id = ? AND status IN ?", refund.OrderID, receivedStatuses).
+			Scan(&received).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&domain.Refund{}).
+			Select("COALESCE(SUM(amount), 0)").
+			Where("order_id = ? AND status = ?", refund.OrderID, domain.RefundPaid).
+			Scan(&refunded).Error; err != nil {
+			return err
+		}
+		if refunded < received {
+			return nil
+		}
+		return tx.Model(&domain.Payment{}).
+			Where("order_id = ? AND status = ?", refund.OrderID, domain.PaymentPaid).
+			Update("status", domain.PaymentRefunded).Error
+	})
+
+	if err != nil {
+		repo.log.Error("Error paying refund", zap.Uint("id", id), zap.Error(err))
+		return domain.Refund{}, err
+	}
+	return refund, nil
+}
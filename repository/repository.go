@@ -22,6 +22,21 @@ type Repository struct {
 	Stock         RepositoryStock
 	Promotion     RepositoryPromotion
 	Banner        RepositoryBanner
+	Supplier      RepositorySupplier
+	PurchaseOrder RepositoryPurchaseOrder
+	Shipment      RepositoryShipment
+	Payment       RepositoryPayment
+	Refund        RepositoryRefund
+	BulkJob       RepositoryBulkJob
+	OrderNote     RepositoryOrderNote
+	Customer      RepositoryCustomer
+	Segment       RepositorySegment
+	Variant       RepositoryProductVariant
+	Option        RepositoryOption
+	Image         RepositoryProductImage
+	Trash         RepositoryTrash
+	Price         RepositoryPrice
+	Review        RepositoryReview
 }
 
 func NewRepository(db *gorm.DB, cacher database.Cacher, config config.Config, log *zap.Logger) Repository {
@@ -30,11 +45,26 @@ func NewRepository(db *gorm.DB, cacher database.Cacher, config config.Config, lo
 		Product:       productrepository.NewProductRepo(db, log),
 		Dashboard:     dashboardrepository.NewDashboardRepo(db, log),
 		Auth:          *NewAuthRepository(db, cacher, config.AppSecret),
-		Order:         *NewOrderRepository(db),
+		Order:         *NewOrderRepository(db, log),
 		PasswordReset: *NewPasswordResetRepository(db),
 		User:          *NewUserRepository(db),
 		Stock:         NewRepositoryStock(db, log),
 		Promotion:     NewRepositoryPromotion(db, log),
 		Banner:        *NewRepositoryBanner(db, log),
+		Supplier:      NewRepositorySupplier(db, log),
+		PurchaseOrder: NewRepositoryPurchaseOrder(db, log),
+		Shipment:      NewRepositoryShipment(db, log),
+		Payment:       NewRepositoryPayment(db, log),
+		Refund:        NewRepositoryRefund(db, log),
+		BulkJob:       NewRepositoryBulkJob(cacher),
+		OrderNote:     NewRepositoryOrderNote(db, log),
+		Customer:      NewRepositoryCustomer(db, log),
+		Segment:       NewRepositorySegment(db, log),
+		Variant:       NewRepositoryProductVariant(db, log),
+		Option:        NewRepositoryOption(db, log),
+		Image:         NewRepositoryProductImage(db, log),
+		Trash:         NewRepositoryTrash(db, log),
+		Price:         NewRepositoryPrice(db, log),
+		Review:        NewRepositoryReview(db, log),
 	}
 }
@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"errors"
+	"math"
+	"project/domain"
+	"project/helper"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type RepositoryReview interface {
+	FindAll(filter domain.ReviewFilter, page, limit uint) ([]domain.Review, int, int, error)
+	FindById(id uint) (domain.Review, error)
+	Insert(review *domain.Review) error
+	UpdateStatus(review *domain.Review) error
+	UpdateReply(review *domain.Review) error
+	FindUnscored(limit int) ([]domain.Review, error)
+	UpdateScore(review *domain.Review) error
+	FindScored(since time.Time) ([]domain.ScoredReview, error)
+	ReplaceSentiments(summaries []domain.ProductSentiment) error
+	FindDecliningSentiment(filter domain.SentimentDeclineFilter, page, limit uint) ([]domain.ProductSentiment, int, int, error)
+}
+
+type repositoryReview struct {
+	db  *gorm.DB
+	log *zap.Logger
+}
+
+func NewRepositoryReview(db *gorm.DB, log *zap.Logger) RepositoryReview {
+	return &repositoryReview{db, log}
+}
+
+// reviewedVariant joins the reviews to the variant they are of.
+func reviewedVariant(db *gorm.DB) *gorm.DB {
+	return db.Model(&domain.Review{}).
+		Joins("JOIN order_items ON order_items.id = reviews.order_item_id").
+		Joins("JOIN product_variants ON product_variants.id = order_items.variant_id")
+}
+
+// withProduct adds the product of the reviewed variant to the reviews.
+func withProduct(db *gorm.DB) *gorm.DB {
+	return reviewedVariant(db).Select("reviews.*, product_variants.product_id")
+}
+
+func filterReviews(filter domain.ReviewFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("reviews.status = ?", filter.Status)
+		if filter.ProductID != nil {
+			db = db.Where("product_variants.product_id = ?", *filter.ProductID)
+		}
+		if filter.Rating != nil {
+			db = db.Where("ROUND(reviews.rating) = ?", *filter.Rating)
+		}
+		if filter.DateFrom != "" {
+			db = db.Where("reviews.created_at >= ?", helper.Date(filter.DateFrom))
+		}
+		if filter.DateTo != "" {
+			db = db.Where("reviews.created_at < ?", helper.Date(filter.DateTo).AddDate(0, 0, 1))
+		}
+		return db
+	}
+}
+
+func (repo *repositoryReview) FindAll(filter domain.ReviewFilter, page, limit uint) ([]domain.Review, int, int, error) {
+	var count int64
+	if err := repo.db.Scopes(reviewedVariant, filterReviews(filter)).Count(&count).Error; err != nil {
+		repo.log.Error("Error counting reviews", zap.Error(err))
+		return nil, 0, 0, errors.New("internal server error")
+	}
+
+	reviews := []domain.Review{}
+	if err := repo.db.Scopes(withProduct, filterReviews(filter), helper.Paginate(page, limit)).
+		Order("reviews.created_at DESC, reviews.id DESC").
+		Find(&reviews).Error; err != nil {
+		repo.log.Error("Error fetching reviews", zap.Error(err))
+		return nil, 0, 0, errors.New("internal server error")
+	}
+
+	pages := int(math.Ceil(float64(count) / float64(limit)))
+	return reviews, int(count), pages, nil
+}
+
+func (repo *repositoryReview) FindById(id uint) (domain.Review, error) {
+	var review domain.Review
+	if err := repo.db.Scopes(withProduct).Where("reviews.id = ?", id).Take(&review).Error; err != nil {
+		return domain.Review{}, errors.New("review not found")
+	}
+	return review, nil
+}
+
+// Insert adds a review of an item of a delivered or completed order, every
+// item is reviewed once.
+func (repo *repositoryReview) Insert(review *domain.Review) error {
+	var item struct {
+		ProductID int
+		Status    domain.Status
+	}
+	if err := repo.db.Table("order_items").
+		Select("product_variants.product_id, orders.status").
+		Joins("JOIN orders ON orders.id = order_items.order_id").
+		Joins("JOIN product_variants ON product_variants.id = order_items.variant_id").
+		Where("order_items.id = ?", review.OrderItemID).
+		Take(&item).Error; err != nil {
+		return errors.New("order item not found")
+	}
+	if item.Status != domain.Delivered && item.Status != domain.Completed {
+		return errors.New("only items of delivered orders can be reviewed")
+	}
+
+	if err := repo.db.Create(review).Error; err != nil {
+		if helper.UniqueViolation(err) {
+			return errors.New("order item has already been reviewed")
+		}
+		repo.log.Error("Error creating review", zap.Uint("order_item_id", review.OrderItemID), zap.Error(err))
+		return errors.New("internal server error")
+	}
+	review.ProductID = item.ProductID
+	return nil
+}
+
+func (repo *repositoryReview) UpdateStatus(review *domain.Review) error {
+	if err := repo.db.Model(review).Update("status", review.Status).Error; err != nil {
+		repo.log.Error("Error moderating review", zap.Uint("id", review.ID), zap.Error(err))
+		return errors.New("internal server error")
+	}
+	return nil
+}
+
+func (repo *repositoryReview) UpdateReply(review *domain.Review) error {
+	now := time.Now()
+	review.RepliedAt = &now
+	if err := repo.db.Model(review).Updates(map[string]interface{}{"reply": review.Reply, "replied_at": review.RepliedAt}).Error; err != nil {
+		repo.log.Error("Error replying to review", zap.Uint("id", review.ID), zap.Error(err))
+		return errors.New("internal server error")
+	}
+	return nil
+}
+
+// FindUnscored returns the reviews the analyzer hasn't scored yet, the oldest
+// first.
+func (repo *repositoryReview) FindUnscored(limit int) ([]domain.Review, error) {
+	reviews := []domain.Review{}
+	if err := repo.db.Where("sentiment IS NULL").Order("id").Limit(limit).Find(&reviews).Error; err != nil {
+		repo.log.Error("Error fetching unscored reviews", zap.Error(err))
+		return nil, errors.New("internal server error")
+	}
+	return reviews, nil
+}
+
+func (repo *repositoryReview) UpdateScore(review *domain.Review) error {
+	if err := repo.db.Model(review).Select("sentiment", "keywords").UpdateColumns(review).Error; err != nil {
+		repo.log.Error("Error scoring review", zap.Uint("id", review.ID), zap.Error(err))
+		return errors.New("internal server error")
+	}
+	return nil
+}
+
+// FindScored returns the scored reviews created since a moment with the
+// product they are of, hidden reviews are left out.
+func (repo *repositoryReview) FindScored(since time.Time) ([]domain.ScoredReview, error) {
+	reviews := []domain.ScoredReview{}
+	if err := repo.db.Scopes(reviewedVariant).
+		Select("product_variants.product_id, reviews.sentiment, reviews.keywords, reviews.created_at").
+		Where("reviews.status <> ? AND reviews.sentiment IS NOT NULL AND reviews.created_at >= ?", domain.ReviewHidden, since).
+		Find(&reviews).Error; err != nil {
+		repo.log.Error("Error fetching scored reviews", zap.Error(err))
+		return nil, errors.New("internal server error")
+	}
+	return reviews, nil
+}
+
+// ReplaceSentiments swaps the sentiment summaries of every product for new ones.
+func (repo *repositoryReview) ReplaceSentiments(summaries []domain.ProductSentiment) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&domain.ProductSentiment{}).Error; err != nil {
+			return err
+		}
+		if len(summaries) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(summaries, 500).Error
+	})
+}
+
+// FindDecliningSentiment returns the products whose sentiment dropped the
+// most between the two latest windows.
+func (repo *repositoryReview) FindDecliningSentiment(filter domain.SentimentDeclineFilter, page, limit uint) ([]domain.ProductSentiment, int, int, error) {
+	query := repo.db.Model(&domain.ProductSentiment{}).
+		Joins("JOIN products ON products.id = product_sentiments.product_id AND products.deleted_at IS NULL").
+		Where("product_sentiments.change <= ?", -filter.MinDrop).
+		Where("product_sentiments.reviews >= ? AND product_sentiments.previous_reviews >= ?", filter.MinReviews, filter.MinReviews).
+		Session(&gorm.Session{})
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		repo.log.Error("Error counting declining sentiment", zap.Error(err))
+		return nil, 0, 0, errors.New("internal server error")
+	}
+
+	summaries := []domain.ProductSentiment{}
+	if err := query.Select("product_sentiments.*, products.name").
+		Order("product_sentiments.change, product_sentiments.product_id").
+		Scopes(helper.Paginate(page, limit)).
+		Find(&summaries).Error; err != nil {
+		repo.log.Error("Error fetching declining sentiment", zap.Error(err))
+		return nil, 0, 0, errors.New("internal server error")
+	}
+
+	pages := int(math.Ceil(float64(count) / float64(limit)))
+	return summaries, int(count), pages, nil
+}
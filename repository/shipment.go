@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"errors"
+	"project/domain"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RepositoryShipment interface {
+	FindByOrder(orderId uint) ([]domain.Shipment, error)
+	FindInTransit() ([]domain.Shipment, error)
+	Insert(shipment *domain.Shipment) error
+	MarkDelivered(shipment *domain.Shipment, deliveredAt time.Time) error
+}
+
+type repositoryShipment struct {
+	db  *gorm.DB
+	log *zap.Logger
+}
+
+func NewRepositoryShipment(db *gorm.DB, log *zap.Logger) RepositoryShipment {
+	return &repositoryShipment{db, log}
+}
+
+func (repo *repositoryShipment) FindByOrder(orderId uint) ([]domain.Shipment, error) {
+	shipments := []domain.Shipment{}
+	if err := repo.db.Where("order_id = ?", orderId).Order("shipped_at").Find(&shipments).Error; err != nil {
+		repo.log.Error("Error fetching shipments", zap.Uint("order_id", orderId), zap.Error(err))
+		return nil, errors.New("internal server error")
+	}
+	return shipments, nil
+}
+
+func (repo *repositoryShipment) FindInTransit() ([]domain.Shipment, error) {
+	var shipments []domain.Shipment
+	if err := repo.db.Where("status = ?", domain.ShipmentInTransit).Find(&shipments).Error; err != nil {
+		repo.log.Error("Error fetching in-transit shipments", zap.Error(err))
+		return nil, err
+	}
+	return shipments, nil
+}
+
+// Insert stores the shipment and ships its order with the carrier tracking
+// number in the same transaction.
+func (repo *repositoryShipment) Insert(shipment *domain.Shipment) error {
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		var order domain.Order
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, shipment.OrderID).Error; err != nil {
+			return errors.New("order not found")
+		}
+
+		if err := domain.CanShip(order.Status); err != nil {
+			return err
+		}
+		// further parcels of an order that already shipped keep its first
+		// tracking number
+		if order.Status == domain.Processed {
+			if err := order.Ship(domain.OrderConfirmation{Accept: true, TrackingNumber: &shipment.TrackingNumber}); err != nil {
+				return err
+			}
+		}
+
+		shipment.Status = domain.ShipmentInTransit
+		if err := tx.Create(shipment).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&order).Updates(map[string]interface{}{
+			"status":          order.Status,
+			"tracking_number": order.TrackingNumber,
+		}).Error
+	})
+
+	if err != nil {
+		repo.log.Error("Error creating shipment", zap.Uint("order_id", shipment.OrderID), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// MarkDelivered closes the shipment and advances its order to delivered in
+// the same transaction, the shipment is only changed once both are stored.
+// An order that can no longer be delivered, like a canceled one, keeps its
+// status so the shipment doesn't stay in transit.
+func (repo *repositoryShipment) MarkDelivered(shipment *domain.Shipment, deliveredAt time.Time) error {
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		var order domain.Order
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, shipment.OrderID).Error; err != nil {
+			return errors.New("order not found")
+		}
+
+		if err := tx.Model(&domain.Shipment{}).Where("id = ?", shipment.ID).Updates(map[string]interface{}{
+			"status":       domain.ShipmentDelivered,
+			"delivered_at": deliveredAt,
+		}).Error; err != nil {
+			return err
+		}
+
+		// an order can have several parcels, it is only delivered once the
+		// last one arrives
+		var remaining int64
+		if err := tx.Model(&domain.Shipment{}).
+			Where("order_id = ? AND status = ?", order.ID, domain.ShipmentInTransit).
+			Count(&remaining).Error; err != nil {
+			return err
+		}
+		if remaining > 0 || order.Status != domain.Completed {
+			return nil
+		}
+
+		if err := order.Deliver(); err != nil {
+			return err
+		}
+		return tx.Model(&order).Update("status", order.Status).Error
+	})
+
+	if err != nil {
+		return err
+	}
+	shipment.Status = domain.ShipmentDelivered
+	shipment.DeliveredAt = &deliveredAt
+	return nil
+}
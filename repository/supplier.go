@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"errors"
+	"project/domain"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type RepositorySupplier interface {
+	FindAll() ([]domain.Supplier, error)
+	FindById(id uint) (domain.Supplier, error)
+	Insert(supplier *domain.Supplier) error
+	Update(supplier *domain.Supplier) error
+	Delete(supplier *domain.Supplier) error
+}
+
+type repositorySupplier struct {
+	db  *gorm.DB
+	log *zap.Logger
+}
+
+func NewRepositorySupplier(db *gorm.DB, log *zap.Logger) RepositorySupplier {
+	return &repositorySupplier{db, log}
+}
+
+func (repo *repositorySupplier) FindAll() ([]domain.Supplier, error) {
+	suppliers := []domain.Supplier{}
+	if err := repo.db.Order("name").Find(&suppliers).Error; err != nil {
+		repo.log.Error("Error fetching suppliers", zap.Error(err))
+		return nil, errors.New("internal server error")
+	}
+	return suppliers, nil
+}
+
+func (repo *repositorySupplier) FindById(id uint) (domain.Supplier, error) {
+	supplier := domain.Supplier{}
+	if err := repo.db.First(&supplier, id).Error; err != nil {
+		return domain.Supplier{}, errors.New("supplier not found")
+	}
+	return supplier, nil
+}
+
+func (repo *repositorySupplier) Insert(supplier *domain.Supplier) error {
+	if err := repo.db.Create(supplier).Error; err != nil {
+		repo.log.Error("Error creating supplier", zap.Error(err))
+		return errors.New("failed to create supplier")
+	}
+	return nil
+}
+
+func (repo *repositorySupplier) Update(supplier *domain.Supplier) error {
+	result := repo.db.Model(supplier).Where("id = ?", supplier.ID).Updates(supplier)
+	if result.Error != nil {
+		repo.log.Error("Error updating supplier", zap.Error(result.Error))
+		return errors.New("failed to update supplier")
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("supplier not found")
+	}
+	return nil
+}
+
+func (repo *repositorySupplier) Delete(supplier *domain.Supplier) error {
+	var count int64
+	repo.db.Model(&domain.PurchaseOrder{}).Where("supplier_id = ?", supplier.ID).Count(&count)
+	if count > 0 {
+		return errors.New("supplier still has purchase orders")
+	}
+
+	result := repo.db.Delete(supplier)
+	if result.Error != nil {
+		repo.log.Error("Error deleting supplier", zap.Error(result.Error))
+		return errors.New("failed to delete supplier")
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("supplier not found")
+	}
+	return nil
+}
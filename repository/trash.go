@@ -0,0 +1,270 @@
+package repository
+
+import (
+	"errors"
+	"math"
+	"project/domain"
+	"project/helper"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RepositoryTrash interface {
+	FindDeleted(entity domain.TrashEntity, page, limit int) ([]domain.TrashItem, int, int, error)
+	FindDeletedBefore(entity domain.TrashEntity, before time.Time) ([]uint, error)
+	Restore(entity domain.TrashEntity, id uint) error
+	Purge(entity domain.TrashEntity, id uint) ([]string, error)
+}
+
+type repositoryTrash struct {
+	db  *gorm.DB
+	log *zap.Logger
+}
+
+func NewRepositoryTrash(db *gorm.DB, log *zap.Logger) RepositoryTrash {
+	return &repositoryTrash{db, log}
+}
+
+// trashTable is where the rows of a trash entity live and the expression
+// naming a row in the trash listing.
+type trashTable struct {
+	table string
+	name  string
+}
+
+var trashTables = map[domain.TrashEntity]trashTable{
+	domain.TrashProducts:   {"products", "name"},
+	domain.TrashVariants:   {"product_variants", "COALESCE(NULLIF(sku, ''), CONCAT_WS(' ', size, color))"},
+	domain.TrashImages:     {"images", "COALESCE(NULLIF(alt_text, ''), url_path)"},
+	domain.TrashBanners:    {"banners", "title"},
+	domain.TrashCategories: {"categories", "name"},
+	domain.TrashPromotions: {"promotions", "name"},
+}
+
+var errNotInTrash = errors.New("not found in the trash")
+
+// usedVariants selects the variants orders or purchase orders refer to.
+const usedVariants = `(SELECT variant_id FROM order_items UNION SELECT variant_id FROM purchase_order_items)`
+
+func (repo *repositoryTrash) FindDeleted(entity domain.TrashEntity, page, limit int) ([]domain.TrashItem, int, int, error) {
+	table, ok := trashTables[entity]
+	if !ok {
+		return nil, 0, 0, errors.New("unknown trash entity")
+	}
+
+	query := repo.db.Table(table.table).Where("deleted_at IS NOT NULL").Session(&gorm.Session{})
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		repo.log.Error("Error counting trash", zap.String("entity", string(entity)), zap.Error(err))
+		return nil, 0, 0, errors.New("internal server error")
+	}
+
+	items := []domain.TrashItem{}
+	if err := query.Select("id, " + table.name + " AS name, deleted_at").
+		Order("deleted_at DESC, id").
+		Scopes(helper.Paginate(uint(page), uint(limit))).
+		Scan(&items).Error; err != nil {
+		repo.log.Error("Error fetching trash", zap.String("entity", string(entity)), zap.Error(err))
+		return nil, 0, 0, errors.New("internal server error")
+	}
+
+	totalPages := int(math.Ceil(float64(count) / float64(limit)))
+	return items, int(count), totalPages, nil
+}
+
+func (repo *repositoryTrash) FindDeletedBefore(entity domain.TrashEntity, before time.Time) ([]uint, error) {
+	table, ok := trashTables[entity]
+	if !ok {
+		return nil, errors.New("unknown trash entity")
+	}
+
+	ids := []uint{}
+	if err := repo.db.Table(table.table).Where("deleted_at < ?", before).Order("id").Pluck("id", &ids).Error; err != nil {
+		repo.log.Error("Error fetching expired trash", zap.String("entity", string(entity)), zap.Error(err))
+		return nil, errors.New("internal server error")
+	}
+	return ids, nil
+}
+
+// Restore takes a row out of the trash. Variants and images need their product
+// restored first, restored images go last and restored products come back as
+// drafts. A category whose parent is gone is restored at the root.
+func (repo *repositoryTrash) Restore(entity domain.TrashEntity, id uint) error {
+	table, ok := trashTables[entity]
+	if !ok {
+		return errors.New("unknown trash entity")
+	}
+
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		var row struct {
+			ID        uint
+			ProductID *int
+			ParentID  *uint
+		}
+		columns := "id"
+		switch entity {
+		case domain.TrashVariants, domain.TrashImages:
+			columns = "id, product_id"
+		case domain.TrashCategories:
+			columns = "id, parent_id"
+		}
+		if err := tx.Table(table.table).Select(columns).
+			Where("id = ? AND deleted_at IS NOT NULL", id).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Take(&row).Error; err != nil {
+			return errNotInTrash
+		}
+
+		updates := map[string]interface{}{"deleted_at": nil}
+		switch entity {
+		case domain.TrashProducts:
+			updates["status"] = domain.ProductDraft
+		case domain.TrashVariants, domain.TrashImages:
+			if err := tx.First(&domain.Product{}, *row.ProductID).Error; err != nil {
+				return errors.New("restore the product first")
+			}
+			if entity == domain.TrashImages {
+				updates["position"] = gorm.Expr("(SELECT COALESCE(MAX(position) + 1, 0) FROM images WHERE product_id = ? AND deleted_at IS NULL)", *row.ProductID)
+				updates["is_primary"] = gorm.Expr("NOT EXISTS (SELECT 1 FROM images WHERE product_id = ? AND is_primary AND deleted_at IS NULL)", *row.ProductID)
+			}
+		case domain.TrashCategories:
+			if row.ParentID != nil {
+				if err := tx.First(&domain.Category{}, *row.ParentID).Error; err != nil {
+					row.ParentID = nil
+					updates["parent_id"] = nil
+				}
+			}
+			siblings := tx.Model(&domain.Category{}).Select("COALESCE(MAX(position) + 1, 0)")
+			if row.ParentID == nil {
+				siblings = siblings.Where("parent_id IS NULL")
+			} else {
+				siblings = siblings.Where("parent_id = ?", *row.ParentID)
+			}
+			updates["position"] = siblings
+		}
+
+		if err := tx.Table(table.table).Where("id = ?", id).UpdateColumns(updates).Error; err != nil {
+			repo.log.Error("Error restoring from trash", zap.String("entity", string(entity)), zap.Uint("id", id), zap.Error(err))
+			return errors.New("failed to restore, it conflicts with an existing row")
+		}
+		return nil
+	})
+}
+
+// Purge permanently deletes a row from the trash together with the rows that
+// belong to it, a product takes its variants, images and prices along. Rows orders
+// still refer to are kept and ErrStillReferenced is returned. It returns the
+// files of the purged images, to be removed from storage once committed.
+func (repo *repositoryTrash) Purge(entity domain.TrashEntity, id uint) ([]string, error) {
+	table, ok := trashTables[entity]
+	if !ok {
+		return nil, errors.New("unknown trash entity")
+	}
+
+	fileIds := []string{}
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		var found int64
+		if err := tx.Table(table.table).Where("id = ? AND deleted_at IS NOT NULL", id).Count(&found).Error; err != nil {
+			return err
+		}
+		if found == 0 {
+			return errNotInTrash
+		}
+
+		var images []domain.Image
+		switch entity {
+		case domain.TrashProducts:
+			variants := tx.Unscoped().Model(&domain.ProductVariant{}).Select("id").Where("product_id = ?", id)
+			if err := stillReferenced(tx.Unscoped().Model(&domain.ProductVariant{}).Where("product_id = ? AND id IN "+usedVariants, id)); err != nil {
+				return err
+			}
+			if err := tx.Unscoped().Where("product_id = ?", id).Find(&images).Error; err != nil {
+				return err
+			}
+			if err := purgeVariants(tx, variants); err != nil {
+				return err
+			}
+			if err := tx.Where("product_id = ?", id).Delete(&domain.ProductCategory{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Unscoped().Where("product_id = ?", id).Delete(&domain.Image{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Unscoped().Where("product_id = ?", id).Delete(&domain.ProductVariant{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("product_id = ?", id).Delete(&domain.PriceHistory{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("product_id = ?", id).Delete(&domain.ScheduledPrice{}).Error; err != nil {
+				return err
+			}
+		case domain.TrashVariants:
+			if err := stillReferenced(tx.Table("product_variants").Where("id = ? AND id IN "+usedVariants, id)); err != nil {
+				return err
+			}
+			if err := purgeVariants(tx, []uint{id}); err != nil {
+				return err
+			}
+		case domain.TrashImages:
+			if err := tx.Unscoped().Where("id = ?", id).Find(&images).Error; err != nil {
+				return err
+			}
+		case domain.TrashCategories:
+			if err := stillReferenced(tx.Model(&domain.OptionType{}).Where("category_id = ?", id)); err != nil {
+				return err
+			}
+		case domain.TrashPromotions:
+			if err := stillReferenced(tx.Table("orders").Where("promotion_id = ?", id)); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Exec("DELETE FROM "+table.table+" WHERE id = ?", id).Error; err != nil {
+			return err
+		}
+
+		for _, image := range images {
+			fileIds = append(fileIds, image.FileIDs()...)
+		}
+		return nil
+	})
+
+	if err != nil {
+		repo.log.Error("Error purging from trash", zap.String("entity", string(entity)), zap.Uint("id", id), zap.Error(err))
+		if errors.Is(err, domain.ErrStillReferenced) || errors.Is(err, errNotInTrash) {
+			return nil, err
+		}
+		return nil, errors.New("failed to purge")
+	}
+	return fileIds, nil
+}
+
+// stillReferenced fails with ErrStillReferenced when the query finds a row.
+func stillReferenced(query *gorm.DB) error {
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return domain.ErrStillReferenced
+	}
+	return nil
+}
+
+// purgeVariants removes what refers to the variants before they are deleted,
+// their option values, their stock ledger and the images attached to them.
+func purgeVariants(tx *gorm.DB, variants interface{}) error {
+	if err := tx.Where("product_variant_id IN (?)", variants).Delete(&domain.VariantOptionValue{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("product_variant_id IN (?)", variants).Delete(&domain.Stock{}).Error; err != nil {
+		return err
+	}
+	return tx.Unscoped().Model(&domain.Image{}).Where("product_variant_id IN (?)", variants).
+		UpdateColumn("product_variant_id", nil).Error
+}
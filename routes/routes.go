@@ -1,11 +1,10 @@
 package routes
 
 import (
-	"log"
 	"net/http"
-	"project/helper"
+	"project/handler"
 	"project/infra"
-	"sync"
+	"project/storage"
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
@@ -26,7 +25,12 @@ func NewRoutes(ctx infra.ServiceContext) *http.Server {
 		category.GET("/", ctx.Ctl.Category.ShowAllCategory)
 		category.POST("/", ctx.Ctl.Category.CreateCategory)
 		category.DELETE("/:id", ctx.Middleware.OnlyAdmin(), ctx.Ctl.Category.DeleteCategory)
+		category.GET("/tree", ctx.Ctl.Category.ShowCategoryTree)
 		category.GET("/:id", ctx.Ctl.Category.GetCategoryByID)
+		category.PUT("/:id/move", ctx.Ctl.Category.MoveCategory)
+		category.GET("/:id/options", ctx.Ctl.Option.GetByCategory)
+		category.POST("/:id/options", ctx.Ctl.Option.Create)
+		category.GET("/:id/products", ctx.Ctl.Category.ShowCategoryProducts)
 		category.PUT("/:id", ctx.Ctl.Category.UpdateCategory)
 	}
 
@@ -42,17 +46,108 @@ func NewRoutes(ctx infra.ServiceContext) *http.Server {
 	products := r.Group("/products")
 	{
 		products.GET("/", ctx.Ctl.Product.ShowAllProduct)
+		products.GET("/search", ctx.Ctl.Product.SearchProducts)
+		products.GET("/admin", ctx.Middleware.OnlyAdmin(), ctx.Ctl.Product.ShowAdminProducts)
 		products.POST("/", ctx.Ctl.Product.CreateProduct)
 		products.GET("/:id", ctx.Ctl.Product.GetProductByID)
 		products.DELETE("/:id", ctx.Middleware.OnlyAdmin(), ctx.Ctl.Product.DeleteProduct)
 		products.PUT("/:id", ctx.Ctl.Product.UpdateProduct)
+		products.PUT("/:id/status", ctx.Middleware.OnlyAdmin(), ctx.Ctl.Product.ChangeStatus)
+		products.POST("/:id/categories", ctx.Ctl.Product.AssignCategory)
+		products.GET("/:id/price-history", ctx.Ctl.Price.GetHistory)
+		products.GET("/:id/price-schedule", ctx.Ctl.Price.GetScheduled)
+		products.POST("/:id/price-schedule", ctx.Middleware.OnlyAdmin(), ctx.Ctl.Price.Schedule)
+		products.DELETE("/:id/price-schedule/:scheduleId", ctx.Middleware.OnlyAdmin(), ctx.Ctl.Price.CancelScheduled)
+		products.PUT("/:id/sale", ctx.Middleware.OnlyAdmin(), ctx.Ctl.Price.SetSale)
+		products.GET("/:id/variants", ctx.Ctl.Variant.GetByProduct)
+		products.POST("/:id/variants", ctx.Ctl.Variant.Create)
+		products.POST("/:id/variants/generate", ctx.Ctl.Variant.Generate)
+		products.PUT("/:id/variants/:variantId", ctx.Ctl.Variant.Update)
+		products.DELETE("/:id/variants/:variantId", ctx.Middleware.OnlyAdmin(), ctx.Ctl.Variant.Delete)
+		products.DELETE("/:id/categories/:categoryId", ctx.Middleware.OnlyAdmin(), ctx.Ctl.Product.UnassignCategory)
+		products.GET("/:id/images", ctx.Ctl.Image.GetByProduct)
+		products.POST("/:id/images", ctx.Ctl.Image.Upload)
+		products.PUT("/:id/images/order", ctx.Ctl.Image.Reorder)
+		products.PUT("/:id/images/:imageId", ctx.Ctl.Image.Update)
+		products.PUT("/:id/images/:imageId/primary", ctx.Ctl.Image.SetPrimary)
+		products.DELETE("/:id/images/:imageId", ctx.Middleware.OnlyAdmin(), ctx.Ctl.Image.Delete)
+	}
+
+	options := r.Group("/options")
+	{
+		options.POST("/:id/values", ctx.Ctl.Option.AddValue)
+		options.DELETE("/:id", ctx.Middleware.OnlyAdmin(), ctx.Ctl.Option.Delete)
+		options.DELETE("/values/:valueId", ctx.Middleware.OnlyAdmin(), ctx.Ctl.Option.DeleteValue)
+	}
+
+	trash := r.Group("/trash", ctx.Middleware.OnlyAdmin())
+	{
+		trash.GET("/:entity", ctx.Ctl.Trash.GetAll)
+		trash.DELETE("/:entity", ctx.Ctl.Trash.Empty)
+		trash.POST("/:entity/:id/restore", ctx.Ctl.Trash.Restore)
+		trash.DELETE("/:entity/:id", ctx.Ctl.Trash.Purge)
+	}
+
+	review := r.Group("/reviews")
+	{
+		review.GET("/", ctx.Middleware.Identify(), ctx.Ctl.Review.GetAll)
+		review.POST("/", ctx.Ctl.Review.Create)
+		review.GET("/:id", ctx.Middleware.Identify(), ctx.Ctl.Review.GetById)
+		review.PUT("/:id/status", ctx.Middleware.OnlyAdmin(), ctx.Ctl.Review.Moderate)
+		review.PUT("/:id/reply", ctx.Middleware.OnlyAdmin(), ctx.Ctl.Review.Reply)
 	}
 
 	order := r.Group("/orders")
 	{
 		order.GET("/", ctx.Ctl.OrderHandler.All)
-		order.GET("/:id", ctx.Ctl.OrderHandler.Get)
+		order.POST("/", ctx.Ctl.OrderHandler.Create)
+		order.POST("/documents", ctx.Ctl.OrderHandler.Documents)
+		order.POST("/bulk", ctx.Ctl.BulkOrder.Run)
+		order.GET("/bulk/:id", ctx.Ctl.BulkOrder.GetJob)
+		order.GET("/:id", ctx.Middleware.Identify(), ctx.Ctl.OrderHandler.Get)
+		order.GET("/:id/invoice.pdf", ctx.Ctl.OrderHandler.Invoice)
+		order.GET("/:id/packing-slip.pdf", ctx.Ctl.OrderHandler.PackingSlip)
 		order.PUT("/:id", ctx.Ctl.OrderHandler.Update)
+		order.GET("/:id/shipments", ctx.Ctl.Shipment.GetByOrder)
+		order.POST("/:id/shipments", ctx.Ctl.Shipment.Create)
+		order.GET("/:id/payments", ctx.Ctl.Payment.GetByOrder)
+		order.POST("/:id/payments", ctx.Ctl.Payment.Create)
+		order.GET("/:id/refunds", ctx.Ctl.Refund.GetByOrder)
+		order.POST("/:id/refunds", ctx.Ctl.Refund.Create)
+		order.GET("/:id/notes", ctx.Middleware.Authentication(), ctx.Ctl.OrderNote.GetByOrder)
+		order.POST("/:id/notes", ctx.Middleware.Authentication(), ctx.Ctl.OrderNote.Create)
+		order.PUT("/:id/notes/:noteId", ctx.Middleware.Authentication(), ctx.Ctl.OrderNote.Edit)
+		order.DELETE("/:id/notes/:noteId", ctx.Middleware.Authentication(), ctx.Ctl.OrderNote.Delete)
+	}
+
+	customer := r.Group("/customers")
+	{
+		customer.GET("/", ctx.Ctl.Customer.GetAll)
+		customer.POST("/", ctx.Ctl.Customer.Create)
+		customer.GET("/:id", ctx.Ctl.Customer.GetById)
+		customer.PUT("/:id", ctx.Ctl.Customer.Edit)
+		customer.DELETE("/:id", ctx.Middleware.OnlyAdmin(), ctx.Ctl.Customer.Delete)
+		customer.GET("/:id/orders", ctx.Ctl.Customer.GetOrders)
+		customer.GET("/segments", ctx.Ctl.Segment.GetAll)
+		customer.POST("/segments", ctx.Ctl.Segment.Create)
+		customer.GET("/segments/:id", ctx.Ctl.Segment.GetById)
+		customer.PUT("/segments/:id", ctx.Ctl.Segment.Edit)
+		customer.DELETE("/segments/:id", ctx.Middleware.OnlyAdmin(), ctx.Ctl.Segment.Delete)
+		customer.GET("/segments/:id/members", ctx.Ctl.Segment.GetMembers)
+	}
+
+	refund := r.Group("/refunds")
+	{
+		refund.GET("/:id", ctx.Ctl.Refund.GetById)
+		refund.POST("/:id/approve", ctx.Middleware.OnlyAdmin(), ctx.Ctl.Refund.Approve)
+		refund.POST("/:id/reject", ctx.Middleware.OnlyAdmin(), ctx.Ctl.Refund.Reject)
+		refund.POST("/:id/pay", ctx.Middleware.OnlyAdmin(), ctx.Ctl.Refund.Pay)
+	}
+
+	payment := r.Group("/payments")
+	{
+		payment.POST("/webhook/:provider", ctx.Ctl.Payment.Webhook)
+		payment.GET("/reconciliation", ctx.Ctl.Payment.Reconciliation)
 	}
 
 	dashboard := r.Group("dashboard")
@@ -61,6 +156,7 @@ func NewRoutes(ctx infra.ServiceContext) *http.Server {
 		dashboard.GET("/summary", ctx.Ctl.Dashboard.GetSummary)
 		dashboard.GET("/bestSeller", ctx.Ctl.Dashboard.GetBestSeller)
 		dashboard.GET("/revenue", ctx.Ctl.Dashboard.GetMonthlyRevenue)
+		dashboard.GET("/decliningSentiment", ctx.Ctl.Review.GetDecliningSentiment)
 	}
 
 	stock := r.Group("/stock")
@@ -75,19 +171,52 @@ func NewRoutes(ctx infra.ServiceContext) *http.Server {
 		promotion.GET("/", ctx.Ctl.Promotion.GetAll)
 		promotion.GET("/:id", ctx.Ctl.Promotion.GetById)
 		promotion.POST("/", ctx.Ctl.Promotion.Create)
+		promotion.POST("/evaluate", ctx.Middleware.OnlyAdmin(), ctx.Ctl.Promotion.Evaluate)
+		promotion.GET("/:id/usage", ctx.Middleware.OnlyAdmin(), ctx.Ctl.Promotion.GetUsages)
 		promotion.DELETE("/:id", ctx.Middleware.OnlyAdmin(), ctx.Ctl.Promotion.Delete)
+		promotion.PUT("/:id/segment", ctx.Ctl.Promotion.Restrict)
 
 	}
 
+	supplier := r.Group("/suppliers")
+	{
+		supplier.GET("/", ctx.Ctl.Supplier.GetAll)
+		supplier.POST("/", ctx.Ctl.Supplier.Create)
+		supplier.GET("/:id", ctx.Ctl.Supplier.GetById)
+		supplier.PUT("/:id", ctx.Ctl.Supplier.Edit)
+		supplier.DELETE("/:id", ctx.Middleware.OnlyAdmin(), ctx.Ctl.Supplier.Delete)
+	}
+
+	purchaseOrder := r.Group("/purchase-orders")
+	{
+		purchaseOrder.GET("/", ctx.Ctl.PurchaseOrder.GetAll)
+		purchaseOrder.POST("/", ctx.Ctl.PurchaseOrder.Create)
+		purchaseOrder.GET("/open", ctx.Ctl.PurchaseOrder.GetOpen)
+		purchaseOrder.GET("/:id", ctx.Ctl.PurchaseOrder.GetById)
+		purchaseOrder.PUT("/:id", ctx.Ctl.PurchaseOrder.Edit)
+		purchaseOrder.POST("/:id/send", ctx.Ctl.PurchaseOrder.Send)
+		purchaseOrder.POST("/:id/receive", ctx.Ctl.PurchaseOrder.Receive)
+		purchaseOrder.POST("/:id/close", ctx.Ctl.PurchaseOrder.Close)
+	}
+
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	r.POST("/cdn-upload", func(c *gin.Context) {
-		form, _ := c.MultipartForm()
-		files := form.File["images[]"]
+	if ctx.Cfg.StorageConfig.Driver == "local" {
+		r.Static(storage.LocalPath, ctx.Cfg.StorageConfig.LocalDir)
+	}
 
-		var wg sync.WaitGroup
-		responses, _ := helper.Upload(&wg, files)
-		log.Println(responses)
+	r.POST("/cdn-upload", func(c *gin.Context) {
+		form, err := c.MultipartForm()
+		if err != nil {
+			handler.BadResponse(c, "Bad Request (Body)", http.StatusBadRequest)
+			return
+		}
+		objects, err := storage.UploadAll(c.Request.Context(), ctx.Svc.Storage, form.File["images[]"])
+		if err != nil {
+			handler.BadResponse(c, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		handler.GoodResponseWithData(c, "files uploaded", http.StatusCreated, objects)
 	})
 
 	return &http.Server{
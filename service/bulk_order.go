@@ -0,0 +1,102 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"project/domain"
+	"project/repository"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type ServiceBulkOrder interface {
+	Run(request domain.BulkOrderRequest) (domain.BulkJob, error)
+	GetJob(id string) (domain.BulkJob, error)
+}
+
+type serviceBulkOrder struct {
+	orders repository.OrderRepository
+	jobs   repository.RepositoryBulkJob
+	log    *zap.Logger
+}
+
+func NewServiceBulkOrder(orders repository.OrderRepository, jobs repository.RepositoryBulkJob, log *zap.Logger) ServiceBulkOrder {
+	return &serviceBulkOrder{orders, jobs, log}
+}
+
+// Run applies the action to every order. Synchronous runs return the finished
+// job, asynchronous ones return right away and report their progress through
+// GetJob.
+func (s *serviceBulkOrder) Run(request domain.BulkOrderRequest) (domain.BulkJob, error) {
+	if err := request.Validate(); err != nil {
+		return domain.BulkJob{}, err
+	}
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return domain.BulkJob{}, err
+	}
+	job := domain.BulkJob{
+		ID:        hex.EncodeToString(id),
+		Action:    request.Action,
+		Status:    domain.BulkJobRunning,
+		Total:     len(request.OrderIDs),
+		Results:   []domain.BulkOrderResult{},
+		CreatedAt: time.Now(),
+	}
+
+	if !request.Async {
+		return s.run(job, request, false), nil
+	}
+
+	if err := s.jobs.Save(job); err != nil {
+		s.log.Error("Error saving bulk job", zap.String("id", job.ID), zap.Error(err))
+		return domain.BulkJob{}, err
+	}
+	// the background run works on its own copy, the caller only gets the job
+	// as it was saved and follows its progress through GetJob
+	go s.run(job.Clone(), request, true)
+	return job, nil
+}
+
+func (s *serviceBulkOrder) run(job domain.BulkJob, request domain.BulkOrderRequest, track bool) domain.BulkJob {
+	for _, orderId := range request.OrderIDs {
+		job.Record(orderId, s.apply(orderId, request))
+		if track {
+			s.save(job)
+		}
+	}
+	job.Finish(time.Now())
+	if track {
+		s.save(job)
+	}
+	s.log.Info("Bulk order action finished",
+		zap.String("id", job.ID),
+		zap.String("action", string(job.Action)),
+		zap.Int("succeeded", job.Succeeded),
+		zap.Int("failed", job.Failed))
+	return job
+}
+
+func (s *serviceBulkOrder) apply(orderId uint, request domain.BulkOrderRequest) error {
+	order, err := s.orders.Get(orderId)
+	if err != nil {
+		return err
+	}
+	confirmation, err := request.Confirmation(orderId, domain.Status(order.Status))
+	if err != nil {
+		return err
+	}
+	return s.orders.Update(orderId, confirmation)
+}
+
+func (s *serviceBulkOrder) save(job domain.BulkJob) {
+	if err := s.jobs.Save(job); err != nil {
+		s.log.Error("Error saving bulk job", zap.String("id", job.ID), zap.Error(err))
+	}
+}
+
+func (s *serviceBulkOrder) GetJob(id string) (domain.BulkJob, error) {
+	return s.jobs.FindById(id)
+}
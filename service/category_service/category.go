@@ -10,9 +10,12 @@ import (
 type CategoryService interface {
 	ShowAllCategory(page, limit int) (*[]domain.Category, int, int, error)
 	CreateCategory(category *domain.Category) error
-	DeleteCategory(id int) error
+	DeleteCategory(id int, options domain.CategoryDelete) error
 	GetCategoryByID(id int) (*domain.Category, error)
 	UpdateCategory(id int, category *domain.Category) error
+	ShowCategoryProducts(id, page, limit int, subtree bool) (*[]domain.Product, int, int, error)
+	ShowCategoryTree() ([]*domain.Category, error)
+	MoveCategory(id int, move domain.CategoryMove) (*domain.Category, error)
 }
 
 type categoryService struct {
@@ -34,9 +37,13 @@ func (cs *categoryService) ShowAllCategory(page, limit int) (*[]domain.Category,
 	return categories, count, totalPage, nil
 }
 
-func (cs *categoryService) DeleteCategory(id int) error {
+func (cs *categoryService) DeleteCategory(id int, options domain.CategoryDelete) error {
 
-	if err := cs.repo.Category.DeleteCategory(id); err != nil {
+	if err := options.Validate(uint(id)); err != nil {
+		return err
+	}
+
+	if err := cs.repo.Category.DeleteCategory(id, options); err != nil {
 		return err
 	}
 
@@ -70,3 +77,28 @@ func (cs *categoryService) UpdateCategory(id int, category *domain.Category) err
 
 	return nil
 }
+
+func (cs *categoryService) ShowCategoryProducts(id, page, limit int, subtree bool) (*[]domain.Product, int, int, error) {
+
+	products, count, totalPage, err := cs.repo.Category.ShowCategoryProducts(id, page, limit, subtree)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	return products, count, totalPage, nil
+}
+
+func (cs *categoryService) ShowCategoryTree() ([]*domain.Category, error) {
+
+	return cs.repo.Category.ShowCategoryTree()
+}
+
+func (cs *categoryService) MoveCategory(id int, move domain.CategoryMove) (*domain.Category, error) {
+
+	category, err := cs.repo.Category.MoveCategory(id, move)
+	if err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
@@ -85,11 +85,11 @@ func TestDeleteCategory(t *testing.T) {
 	t.Run("Successfully delete category", func(t *testing.T) {
 		id := 1
 
-		mockRepo.On("DeleteCategory", id).
+		mockRepo.On("DeleteCategory", id, domain.CategoryDelete{}).
 			Return(nil).
 			Once()
 
-		err := service.DeleteCategory(id)
+		err := service.DeleteCategory(id, domain.CategoryDelete{})
 
 		assert.NoError(t, err)
 		mockRepo.AssertExpectations(t)
@@ -98,11 +98,11 @@ func TestDeleteCategory(t *testing.T) {
 	t.Run("Failed to delete category - Category not found", func(t *testing.T) {
 		id := 999 // Assume this ID does not exist
 
-		mockRepo.On("DeleteCategory", id).
+		mockRepo.On("DeleteCategory", id, domain.CategoryDelete{}).
 			Return(fmt.Errorf("category not found")).
 			Once()
 
-		err := service.DeleteCategory(id)
+		err := service.DeleteCategory(id, domain.CategoryDelete{})
 
 		assert.Error(t, err)
 		assert.EqualError(t, err, "category not found")
@@ -112,16 +112,25 @@ func TestDeleteCategory(t *testing.T) {
 	t.Run("Failed to delete category - Repository error", func(t *testing.T) {
 		id := 1
 
-		mockRepo.On("DeleteCategory", id).
+		mockRepo.On("DeleteCategory", id, domain.CategoryDelete{}).
 			Return(fmt.Errorf("database error")).
 			Once()
 
-		err := service.DeleteCategory(id)
+		err := service.DeleteCategory(id, domain.CategoryDelete{})
 
 		assert.Error(t, err)
 		assert.EqualError(t, err, "database error")
 		mockRepo.AssertExpectations(t)
 	})
+
+	t.Run("Failed to delete category - Reassign without force", func(t *testing.T) {
+		id, target := 1, uint(2)
+
+		err := service.DeleteCategory(id, domain.CategoryDelete{ReassignTo: &target})
+
+		assert.EqualError(t, err, "reassign_to needs force=true")
+		mockRepo.AssertNotCalled(t, "DeleteCategory", id, domain.CategoryDelete{ReassignTo: &target})
+	})
 }
 
 func TestGetCategoryByID(t *testing.T) {
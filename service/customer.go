@@ -0,0 +1,66 @@
+package service
+
+import (
+	"project/domain"
+	"project/repository"
+)
+
+type ServiceCustomer interface {
+	GetAll(page, limit uint, filter domain.CustomerFilter) (int, int, []domain.Customer, error)
+	GetById(id uint) (domain.CustomerDetail, error)
+	Create(customer *domain.Customer) error
+	Edit(customer *domain.Customer) error
+	Delete(id uint) error
+	GetOrders(id, page, limit uint, filter domain.OrderFilter) (int, int, []domain.OrderTotal, error)
+}
+
+type serviceCustomer struct {
+	repo   repository.RepositoryCustomer
+	orders repository.OrderRepository
+}
+
+func NewServiceCustomer(repo repository.RepositoryCustomer, orders repository.OrderRepository) ServiceCustomer {
+	return &serviceCustomer{repo, orders}
+}
+
+func (s *serviceCustomer) GetAll(page, limit uint, filter domain.CustomerFilter) (int, int, []domain.Customer, error) {
+	return s.repo.FindAll(page, limit, filter)
+}
+
+func (s *serviceCustomer) GetById(id uint) (domain.CustomerDetail, error) {
+	customer, err := s.repo.FindById(id)
+	if err != nil {
+		return domain.CustomerDetail{}, err
+	}
+	metrics, err := s.repo.Metrics(id)
+	if err != nil {
+		return domain.CustomerDetail{}, err
+	}
+	return domain.CustomerDetail{Customer: customer, Metrics: metrics}, nil
+}
+
+func (s *serviceCustomer) Create(customer *domain.Customer) error {
+	if err := customer.SetDefaultAddress(); err != nil {
+		return err
+	}
+	return s.repo.Insert(customer)
+}
+
+func (s *serviceCustomer) Edit(customer *domain.Customer) error {
+	if err := customer.SetDefaultAddress(); err != nil {
+		return err
+	}
+	return s.repo.Update(customer)
+}
+
+func (s *serviceCustomer) Delete(id uint) error {
+	return s.repo.Delete(id)
+}
+
+func (s *serviceCustomer) GetOrders(id, page, limit uint, filter domain.OrderFilter) (int, int, []domain.OrderTotal, error) {
+	if _, err := s.repo.FindById(id); err != nil {
+		return 0, 0, nil, err
+	}
+	filter.CustomerID = id
+	return s.orders.All(page, limit, filter)
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"project/domain"
+	"project/repository"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type ServiceSegment interface {
+	GetAll() ([]domain.CustomerSegment, error)
+	GetById(id uint) (domain.CustomerSegment, error)
+	Create(segment *domain.CustomerSegment) error
+	Edit(segment *domain.CustomerSegment) error
+	Delete(id uint) error
+	GetMembers(id, page, limit uint) (int, int, []domain.SegmentMember, error)
+	ComputeRFM()
+}
+
+type serviceSegment struct {
+	repo repository.RepositorySegment
+	log  *zap.Logger
+}
+
+func NewServiceSegment(repo repository.RepositorySegment, log *zap.Logger) ServiceSegment {
+	return &serviceSegment{repo, log}
+}
+
+func (s *serviceSegment) GetAll() ([]domain.CustomerSegment, error) {
+	return s.repo.FindAll()
+}
+
+func (s *serviceSegment) GetById(id uint) (domain.CustomerSegment, error) {
+	return s.repo.FindById(id)
+}
+
+func (s *serviceSegment) Create(segment *domain.CustomerSegment) error {
+	if _, err := domain.ParseSegmentRule(segment.Rule); err != nil {
+		return err
+	}
+	return s.repo.Insert(segment)
+}
+
+func (s *serviceSegment) Edit(segment *domain.CustomerSegment) error {
+	if _, err := domain.ParseSegmentRule(segment.Rule); err != nil {
+		return err
+	}
+	return s.repo.Update(segment)
+}
+
+func (s *serviceSegment) Delete(id uint) error {
+	return s.repo.Delete(id)
+}
+
+func (s *serviceSegment) GetMembers(id, page, limit uint) (int, int, []domain.SegmentMember, error) {
+	segment, err := s.repo.FindById(id)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	return s.repo.FindMembers(segment, page, limit)
+}
+
+// ComputeRFM scores every customer on its completed orders, segments are
+// evaluated against the latest scores.
+func (s *serviceSegment) ComputeRFM() {
+	rows, err := s.repo.RFMStats()
+	if err != nil {
+		return
+	}
+
+	domain.ScoreRFM(rows, time.Now())
+	if err := s.repo.SaveRFM(rows); err != nil {
+		s.log.Error("Error saving RFM scores", zap.Error(err))
+		return
+	}
+	s.log.Info("RFM scores computed", zap.Int("customers", len(rows)))
+}
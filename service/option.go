@@ -0,0 +1,55 @@
+package service
+
+import (
+	"project/domain"
+	"project/repository"
+)
+
+type ServiceOption interface {
+	GetByCategory(categoryId uint) ([]domain.OptionType, error)
+	Create(categoryId uint, newType domain.NewOptionType) (domain.OptionType, error)
+	AddValue(optionTypeId uint, newValue domain.NewOptionValue) (domain.OptionValue, error)
+	Delete(id uint) error
+	DeleteValue(id uint) error
+}
+
+type serviceOption struct {
+	repo repository.RepositoryOption
+}
+
+func NewServiceOption(repo repository.RepositoryOption) ServiceOption {
+	return &serviceOption{repo}
+}
+
+// GetByCategory returns the option types a product of the category can use:
+// the global ones and those of the category and its ancestors.
+func (s *serviceOption) GetByCategory(categoryId uint) ([]domain.OptionType, error) {
+	return s.repo.FindByCategory(categoryId)
+}
+
+func (s *serviceOption) Create(categoryId uint, newType domain.NewOptionType) (domain.OptionType, error) {
+	optionType, err := newType.OptionType(&categoryId)
+	if err != nil {
+		return domain.OptionType{}, err
+	}
+	if err := s.repo.Insert(&optionType); err != nil {
+		return domain.OptionType{}, err
+	}
+	return optionType, nil
+}
+
+func (s *serviceOption) AddValue(optionTypeId uint, newValue domain.NewOptionValue) (domain.OptionValue, error) {
+	value := domain.OptionValue{OptionTypeID: optionTypeId, Value: newValue.Value}
+	if err := s.repo.InsertValue(&value); err != nil {
+		return domain.OptionValue{}, err
+	}
+	return value, nil
+}
+
+func (s *serviceOption) Delete(id uint) error {
+	return s.repo.Delete(id)
+}
+
+func (s *serviceOption) DeleteValue(id uint) error {
+	return s.repo.DeleteValue(id)
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"project/domain"
+	"project/repository"
+)
+
+type ServiceOrderNote interface {
+	GetByOrder(orderId uint, visibility domain.NoteVisibility) ([]domain.OrderNote, error)
+	Create(orderId, authorId uint, newNote domain.NewOrderNote) (domain.OrderNote, error)
+	Edit(orderId, id, authorId uint, edit domain.NewOrderNote) (domain.OrderNote, error)
+	Delete(orderId, id, authorId uint) error
+}
+
+type serviceOrderNote struct {
+	repo   repository.RepositoryOrderNote
+	orders repository.OrderRepository
+}
+
+func NewServiceOrderNote(repo repository.RepositoryOrderNote, orders repository.OrderRepository) ServiceOrderNote {
+	return &serviceOrderNote{repo, orders}
+}
+
+func (s *serviceOrderNote) GetByOrder(orderId uint, visibility domain.NoteVisibility) ([]domain.OrderNote, error) {
+	if _, err := s.orders.Get(orderId); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByOrder(orderId, visibility)
+}
+
+func (s *serviceOrderNote) Create(orderId, authorId uint, newNote domain.NewOrderNote) (domain.OrderNote, error) {
+	if _, err := s.orders.Get(orderId); err != nil {
+		return domain.OrderNote{}, err
+	}
+	note := domain.OrderNote{
+		OrderID:    orderId,
+		AuthorID:   authorId,
+		Visibility: newNote.Visibility,
+		Content:    newNote.Content,
+	}
+	if err := s.repo.Insert(&note); err != nil {
+		return domain.OrderNote{}, err
+	}
+	return note, nil
+}
+
+func (s *serviceOrderNote) Edit(orderId, id, authorId uint, edit domain.NewOrderNote) (domain.OrderNote, error) {
+	note, err := s.repo.FindById(orderId, id)
+	if err != nil {
+		return domain.OrderNote{}, err
+	}
+	if err := note.Edit(authorId, edit); err != nil {
+		return domain.OrderNote{}, err
+	}
+	if err := s.repo.Update(&note); err != nil {
+		return domain.OrderNote{}, err
+	}
+	return note, nil
+}
+
+func (s *serviceOrderNote) Delete(orderId, id, authorId uint) error {
+	note, err := s.repo.FindById(orderId, id)
+	if err != nil {
+		return err
+	}
+	if note.AuthorID != authorId {
+		return domain.ErrNotNoteAuthor
+	}
+	return s.repo.Delete(&note)
+}
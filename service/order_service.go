@@ -1,26 +1,39 @@
 package service
 
 import (
+	"project/config"
 	"project/domain"
+	"project/helper"
 	"project/repository"
 )
 
 type OrderService interface {
-	All(page, limit uint) (int, int, []domain.OrderTotal, error)
+	All(page, limit uint, filter domain.OrderFilter) (int, int, []domain.OrderTotal, error)
+	Create(newOrder domain.NewOrder) (domain.OrderTotal, error)
 	Update(orderId uint, confirmation domain.OrderConfirmation) error
 	Get(orderId uint) (domain.OrderTotal, error)
+	Documents(document domain.OrderDocument, orderIds []uint) ([]byte, error)
 }
 
 type orderService struct {
-	repo repository.OrderRepository
+	repo  repository.OrderRepository
+	store config.StoreConfig
 }
 
-func NewOrderService(repo repository.OrderRepository) OrderService {
-	return &orderService{repo: repo}
+func NewOrderService(repo repository.OrderRepository, store config.StoreConfig) OrderService {
+	return &orderService{repo: repo, store: store}
 }
 
-func (s *orderService) All(page, limit uint) (int, int, []domain.OrderTotal, error) {
-	return s.repo.All(page, limit)
+func (s *orderService) All(page, limit uint, filter domain.OrderFilter) (int, int, []domain.OrderTotal, error) {
+	return s.repo.All(page, limit, filter)
+}
+
+func (s *orderService) Create(newOrder domain.NewOrder) (domain.OrderTotal, error) {
+	order, err := s.repo.Create(newOrder)
+	if err != nil {
+		return domain.OrderTotal{}, err
+	}
+	return s.repo.Get(order.ID)
 }
 
 func (s *orderService) Update(orderId uint, confirmation domain.OrderConfirmation) error {
@@ -30,3 +43,17 @@ func (s *orderService) Update(orderId uint, confirmation domain.OrderConfirmatio
 func (s *orderService) Get(orderId uint) (domain.OrderTotal, error) {
 	return s.repo.Get(orderId)
 }
+
+func (s *orderService) Documents(document domain.OrderDocument, orderIds []uint) ([]byte, error) {
+	if document == domain.Invoice {
+		if err := s.repo.AssignInvoiceNumbers(orderIds); err != nil {
+			return nil, err
+		}
+	}
+
+	orders, err := s.repo.FindByIds(orderIds)
+	if err != nil {
+		return nil, err
+	}
+	return helper.OrderDocumentPDF(s.store, document, orders)
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"errors"
+	"project/domain"
+	"project/payment"
+	"project/repository"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type ServicePayment interface {
+	GetByOrder(orderId uint) ([]domain.Payment, error)
+	Create(orderId uint, newPayment domain.NewPayment) (domain.Payment, error)
+	HandleWebhook(provider string, payload []byte, signature string) (domain.Payment, error)
+	Reconcile() ([]domain.PaymentMismatch, error)
+	ReconcileDaily()
+}
+
+type servicePayment struct {
+	repo      repository.RepositoryPayment
+	orders    repository.OrderRepository
+	providers payment.Registry
+	log       *zap.Logger
+}
+
+func NewServicePayment(repo repository.RepositoryPayment, orders repository.OrderRepository, providers payment.Registry, log *zap.Logger) ServicePayment {
+	return &servicePayment{repo, orders, providers, log}
+}
+
+func (s *servicePayment) GetByOrder(orderId uint) ([]domain.Payment, error) {
+	if _, err := s.orders.Get(orderId); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByOrder(orderId)
+}
+
+// Create charges the outstanding amount of an order with the chosen provider.
+func (s *servicePayment) Create(orderId uint, newPayment domain.NewPayment) (domain.Payment, error) {
+	order, err := s.orders.Get(orderId)
+	if err != nil {
+		return domain.Payment{}, err
+	}
+	if domain.Status(order.Status) == domain.Canceled {
+		return domain.Payment{}, errors.New("canceled orders can't be paid")
+	}
+
+	paid, err := s.repo.PaidAmount(orderId)
+	if err != nil {
+		return domain.Payment{}, err
+	}
+	outstanding := float64(order.Total) - paid
+	if outstanding <= 0 {
+		return domain.Payment{}, errors.New("order is already paid")
+	}
+
+	provider, err := s.providers.Get(newPayment.Provider)
+	if err != nil {
+		return domain.Payment{}, err
+	}
+
+	method := newPayment.Method
+	if method == "" {
+		method = order.PaymentMethod
+	}
+	charge, err := provider.CreateCharge(payment.ChargeRequest{OrderID: order.ID, Amount: outstanding, Method: method})
+	if err != nil {
+		s.log.Error("Error creating charge", zap.Uint("order_id", orderId), zap.String("provider", provider.Name()), zap.Error(err))
+		return domain.Payment{}, err
+	}
+
+	record := domain.Payment{
+		OrderID:           order.ID,
+		Amount:            outstanding,
+		Method:            method,
+		Provider:          provider.Name(),
+		ProviderReference: charge.Reference,
+		PaymentURL:        charge.PaymentURL,
+	}
+	if err := s.repo.Insert(&record); err != nil {
+		return domain.Payment{}, err
+	}
+	return record, nil
+}
+
+// HandleWebhook settles a payment from a signed provider notification. Once an
+// order is fully paid it is processed, which also deducts its stock. Repeated
+// notifications for a payment that is already paid only retry processing the
+// order, in case it failed the first time.
+func (s *servicePayment) HandleWebhook(provider string, payload []byte, signature string) (domain.Payment, error) {
+	gateway, err := s.providers.Get(provider)
+	if err != nil {
+		return domain.Payment{}, err
+	}
+	event, err := gateway.ParseWebhook(payload, signature)
+	if err != nil {
+		return domain.Payment{}, err
+	}
+
+	record, err := s.repo.FindByReference(gateway.Name(), event.Reference)
+	if err != nil {
+		return domain.Payment{}, err
+	}
+	if record.Status == domain.PaymentPaid && event.Status == payment.Paid {
+		if err := s.processPaidOrder(record.OrderID); err != nil {
+			s.log.Error("Error processing paid order", zap.Uint("order_id", record.OrderID), zap.Error(err))
+			return domain.Payment{}, err
+		}
+		return record, nil
+	}
+
+	if event.Status == payment.Paid {
+		err = record.MarkPaid(event.Amount, time.Now())
+	} else {
+		err = record.MarkFailed()
+	}
+	if err != nil {
+		return domain.Payment{}, err
+	}
+	if err := s.repo.UpdateStatus(&record); err != nil {
+		return domain.Payment{}, err
+	}
+	s.log.Info("Payment updated", zap.Uint("id", record.ID), zap.String("status", string(record.Status)))
+
+	if record.Status == domain.PaymentPaid {
+		if err := s.processPaidOrder(record.OrderID); err != nil {
+			s.log.Error("Error processing paid order", zap.Uint("order_id", record.OrderID), zap.Error(err))
+			return domain.Payment{}, err
+		}
+	}
+	return record, nil
+}
+
+func (s *servicePayment) processPaidOrder(orderId uint) error {
+	paid, err := s.repo.PaidAmount(orderId)
+	if err != nil {
+		return err
+	}
+	return s.orders.ProcessPaid(orderId, paid)
+}
+
+func (s *servicePayment) Reconcile() ([]domain.PaymentMismatch, error) {
+	return s.repo.FindMismatches()
+}
+
+// ReconcileDaily logs every order whose paid amount differs from its total.
+func (s *servicePayment) ReconcileDaily() {
+	mismatches, err := s.repo.FindMismatches()
+	if err != nil {
+		return
+	}
+
+	for _, mismatch := range mismatches {
+		s.log.Warn("Payment mismatch",
+			zap.Uint("order_id", mismatch.OrderID),
+			zap.String("status", mismatch.Status),
+			zap.Float64("total", mismatch.Total),
+			zap.Float64("paid", mismatch.Paid),
+			zap.Float64("difference", mismatch.Difference))
+	}
+	s.log.Info("Payments reconciled", zap.Int("mismatches", len(mismatches)))
+}
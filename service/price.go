@@ -0,0 +1,67 @@
+package service
+
+import (
+	"project/domain"
+	"project/repository"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type ServicePrice interface {
+	GetHistory(productId int) ([]domain.PriceHistory, error)
+	GetScheduled(productId int) ([]domain.ScheduledPrice, error)
+	Schedule(productId int, scheduled domain.ScheduledPrice) (domain.ScheduledPrice, error)
+	CancelScheduled(productId int, id uint) error
+	SetSale(productId int, sale domain.ProductSale) (domain.Product, error)
+	ApplyScheduled()
+}
+
+type servicePrice struct {
+	repo repository.RepositoryPrice
+	log  *zap.Logger
+}
+
+func NewServicePrice(repo repository.RepositoryPrice, log *zap.Logger) ServicePrice {
+	return &servicePrice{repo, log}
+}
+
+func (s *servicePrice) GetHistory(productId int) ([]domain.PriceHistory, error) {
+	return s.repo.FindHistory(productId)
+}
+
+func (s *servicePrice) GetScheduled(productId int) ([]domain.ScheduledPrice, error) {
+	return s.repo.FindScheduled(productId)
+}
+
+func (s *servicePrice) Schedule(productId int, scheduled domain.ScheduledPrice) (domain.ScheduledPrice, error) {
+	if err := scheduled.Validate(time.Now()); err != nil {
+		return domain.ScheduledPrice{}, err
+	}
+	scheduled.ID, scheduled.ProductID, scheduled.AppliedAt = 0, productId, nil
+	if err := s.repo.InsertScheduled(&scheduled); err != nil {
+		return domain.ScheduledPrice{}, err
+	}
+	return scheduled, nil
+}
+
+func (s *servicePrice) CancelScheduled(productId int, id uint) error {
+	return s.repo.DeleteScheduled(productId, id)
+}
+
+func (s *servicePrice) SetSale(productId int, sale domain.ProductSale) (domain.Product, error) {
+	return s.repo.SetSale(productId, sale)
+}
+
+// ApplyScheduled applies the price changes that are due, it runs from the
+// cron.
+func (s *servicePrice) ApplyScheduled() {
+	applied, err := s.repo.ApplyScheduled(time.Now())
+	if err != nil {
+		s.log.Error("Failed to apply scheduled prices", zap.Error(err))
+		return
+	}
+	if applied > 0 {
+		s.log.Info("Applied scheduled prices", zap.Int("count", applied))
+	}
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"project/domain"
+	"project/repository"
+)
+
+type ServiceProductImage interface {
+	GetByProduct(productId int) ([]domain.Image, error)
+	Upload(productId int, images []*domain.Image) ([]*domain.Image, error)
+	Update(productId, id int, input domain.ImageUpdate) (domain.Image, error)
+	Reorder(productId int, order domain.ImageOrder) ([]domain.Image, error)
+	SetPrimary(productId, id int) (domain.Image, error)
+	Delete(productId, id int) error
+}
+
+type serviceProductImage struct {
+	repo repository.RepositoryProductImage
+}
+
+func NewServiceProductImage(repo repository.RepositoryProductImage) ServiceProductImage {
+	return &serviceProductImage{repo}
+}
+
+func (s *serviceProductImage) GetByProduct(productId int) ([]domain.Image, error) {
+	return s.repo.FindByProduct(productId)
+}
+
+// Upload stores the uploaded images after the existing ones of the product.
+func (s *serviceProductImage) Upload(productId int, images []*domain.Image) ([]*domain.Image, error) {
+	if err := s.repo.Insert(productId, images); err != nil {
+		return nil, err
+	}
+	return images, nil
+}
+
+func (s *serviceProductImage) Update(productId, id int, input domain.ImageUpdate) (domain.Image, error) {
+	image, err := s.repo.FindById(productId, id)
+	if err != nil {
+		return domain.Image{}, err
+	}
+
+	input.Apply(&image)
+	if err := s.repo.Update(&image); err != nil {
+		return domain.Image{}, err
+	}
+	return image, nil
+}
+
+func (s *serviceProductImage) Reorder(productId int, order domain.ImageOrder) ([]domain.Image, error) {
+	return s.repo.Reorder(productId, order)
+}
+
+func (s *serviceProductImage) SetPrimary(productId, id int) (domain.Image, error) {
+	image, err := s.repo.FindById(productId, id)
+	if err != nil {
+		return domain.Image{}, err
+	}
+	if err := s.repo.SetPrimary(&image); err != nil {
+		return domain.Image{}, err
+	}
+	return image, nil
+}
+
+// Delete moves the image to the trash, its files and renditions are removed
+// from storage once it's purged.
+func (s *serviceProductImage) Delete(productId, id int) error {
+	image, err := s.repo.FindById(productId, id)
+	if err != nil {
+		return err
+	}
+	return s.repo.Delete(&image)
+}
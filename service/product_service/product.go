@@ -3,16 +3,23 @@ package productservice
 import (
 	"project/domain"
 	"project/repository"
+	"time"
 
 	"go.uber.org/zap"
 )
 
 type ProductService interface {
 	ShowAllProduct(page, limit int) (*[]domain.Product, int, int, error)
+	SearchProducts(page, limit int, search domain.ProductSearch) (*[]domain.Product, *domain.ProductFacets, int, int, error)
 	GetProductByID(id int) (*domain.Product, error)
 	CreateProduct(product *domain.Product) error
 	DeleteProduct(id int) error
 	UpdateProduct(productID uint, product *domain.Product) error
+	AssignCategory(productID int, assignment domain.CategoryAssignment) error
+	UnassignCategory(productID int, categoryID uint) error
+	ShowAdminProducts(page, limit int, status domain.ProductStatus) (*[]domain.Product, int, int, error)
+	ChangeStatus(id int, change domain.ProductStatusChange) (*domain.Product, error)
+	PublishScheduled()
 }
 
 type productService struct {
@@ -37,10 +44,23 @@ func (ps *productService) ShowAllProduct(page, limit int) (*[]domain.Product, in
 	return products, count, totalPages, nil
 }
 
+func (ps *productService) SearchProducts(page, limit int, search domain.ProductSearch) (*[]domain.Product, *domain.ProductFacets, int, int, error) {
+	ps.log.Info("Searching products", zap.Int("page", page), zap.Int("limit", limit), zap.String("query", search.Query))
+
+	products, facets, count, totalPages, err := ps.repo.Product.SearchProducts(page, limit, search)
+	if err != nil {
+		ps.log.Error("Error searching products", zap.Error(err))
+		return nil, nil, 0, 0, err
+	}
+
+	ps.log.Info("Successfully searched products", zap.Int("count", count), zap.Int("totalPages", totalPages))
+	return products, facets, count, totalPages, nil
+}
+
 func (ps *productService) GetProductByID(id int) (*domain.Product, error) {
 	ps.log.Info("Fetching product by ID", zap.Int("id", id))
 
-	product, err := ps.repo.Product.GetProductByID(id)
+	product, err := ps.repo.Product.GetActiveProductByID(id)
 	if err != nil {
 		ps.log.Error("Error fetching product", zap.Error(err))
 		return nil, err
@@ -87,3 +107,58 @@ func (ps *productService) UpdateProduct(productID uint, product *domain.Product)
 	ps.log.Info("Successfully updated product", zap.Uint("productID", productID))
 	return nil
 }
+
+func (ps *productService) AssignCategory(productID int, assignment domain.CategoryAssignment) error {
+	ps.log.Info("Assigning category", zap.Int("productID", productID), zap.Uint("categoryID", assignment.CategoryID))
+
+	if err := ps.repo.Product.AssignCategory(productID, assignment); err != nil {
+		ps.log.Error("Error assigning category", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (ps *productService) UnassignCategory(productID int, categoryID uint) error {
+	ps.log.Info("Unassigning category", zap.Int("productID", productID), zap.Uint("categoryID", categoryID))
+
+	if err := ps.repo.Product.UnassignCategory(productID, categoryID); err != nil {
+		ps.log.Error("Error unassigning category", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (ps *productService) ShowAdminProducts(page, limit int, status domain.ProductStatus) (*[]domain.Product, int, int, error) {
+	ps.log.Info("Fetching admin products", zap.Int("page", page), zap.Int("limit", limit), zap.String("status", string(status)))
+
+	products, count, totalPages, err := ps.repo.Product.ShowAdminProducts(page, limit, status)
+	if err != nil {
+		ps.log.Error("Error fetching admin products", zap.Error(err))
+		return nil, 0, 0, err
+	}
+	return products, count, totalPages, nil
+}
+
+func (ps *productService) ChangeStatus(id int, change domain.ProductStatusChange) (*domain.Product, error) {
+	ps.log.Info("Changing product status", zap.Int("id", id), zap.String("status", string(change.Status)))
+
+	product, err := ps.repo.Product.ChangeStatus(id, change)
+	if err != nil {
+		ps.log.Error("Error changing product status", zap.Error(err))
+		return nil, err
+	}
+	return product, nil
+}
+
+// PublishScheduled activates the scheduled products whose publish time has
+// passed, it runs from the cron.
+func (ps *productService) PublishScheduled() {
+	count, err := ps.repo.Product.ActivateScheduled(time.Now())
+	if err != nil {
+		ps.log.Error("Error publishing scheduled products", zap.Error(err))
+		return
+	}
+	if count > 0 {
+		ps.log.Info("Published scheduled products", zap.Int64("count", count))
+	}
+}
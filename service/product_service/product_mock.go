@@ -0,0 +1,15 @@
+package productservice
+
+import (
+	productrepository "project/repository/product_repository"
+)
+
+// ProductServiceMock mocks the product service, it's the repository mock with
+// the methods only the service has.
+type ProductServiceMock struct {
+	productrepository.ProductRepoMock
+}
+
+func (ps *ProductServiceMock) PublishScheduled() {
+	ps.Called()
+}
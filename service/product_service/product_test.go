@@ -91,7 +91,7 @@ func TestGetProductByID(t *testing.T) {
 			Name: "Product A",
 		}
 
-		mockRepo.On("GetProductByID", productID).
+		mockRepo.On("GetActiveProductByID", productID).
 			Return(mockProduct, nil).
 			Once()
 
@@ -107,7 +107,7 @@ func TestGetProductByID(t *testing.T) {
 	t.Run("Failed to get product by ID - Product not found", func(t *testing.T) {
 		productID := 2
 
-		mockRepo.On("GetProductByID", productID).
+		mockRepo.On("GetActiveProductByID", productID).
 			Return(nil, fmt.Errorf("product not found")).
 			Once()
 
@@ -122,7 +122,7 @@ func TestGetProductByID(t *testing.T) {
 	t.Run("Failed to get product by ID - Database error", func(t *testing.T) {
 		productID := 3
 
-		mockRepo.On("GetProductByID", productID).
+		mockRepo.On("GetActiveProductByID", productID).
 			Return(nil, fmt.Errorf("database error")).
 			Once()
 
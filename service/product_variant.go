@@ -0,0 +1,84 @@
+package service
+
+import (
+	"errors"
+	"project/domain"
+	"project/repository"
+	productrepository "project/repository/product_repository"
+)
+
+type ServiceProductVariant interface {
+	GetByProduct(productId int) ([]domain.ProductVariant, error)
+	Create(productId int, newVariant domain.NewProductVariant) (domain.ProductVariant, error)
+	Generate(productId int, generator domain.VariantGenerator) ([]domain.ProductVariant, error)
+	Update(productId, id int, input domain.ProductVariantInput) (domain.ProductVariant, error)
+	Delete(productId, id int) error
+}
+
+type serviceProductVariant struct {
+	repo     repository.RepositoryProductVariant
+	products productrepository.ProductRepo
+}
+
+func NewServiceProductVariant(repo repository.RepositoryProductVariant, products productrepository.ProductRepo) ServiceProductVariant {
+	return &serviceProductVariant{repo, products}
+}
+
+func (s *serviceProductVariant) GetByProduct(productId int) ([]domain.ProductVariant, error) {
+	return s.repo.FindByProduct(productId)
+}
+
+func (s *serviceProductVariant) Create(productId int, newVariant domain.NewProductVariant) (domain.ProductVariant, error) {
+	variant := domain.ProductVariant{Stock: newVariant.Stock}
+	newVariant.Apply(&variant)
+
+	variants := []domain.ProductVariant{variant}
+	if err := s.repo.Insert(productId, variants); err != nil {
+		return domain.ProductVariant{}, err
+	}
+	return variants[0], nil
+}
+
+// Generate creates the size and color combinations the product doesn't have
+// yet.
+func (s *serviceProductVariant) Generate(productId int, generator domain.VariantGenerator) ([]domain.ProductVariant, error) {
+	product, err := s.products.GetProductByID(productId)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := []domain.ProductVariant{}
+	for _, variant := range product.ProductVariant {
+		existing = append(existing, *variant)
+	}
+
+	variants := generator.Variants(*product, existing)
+	if len(variants) == 0 {
+		return nil, errors.New("all size and color combinations already exist")
+	}
+	if err := s.repo.Insert(productId, variants); err != nil {
+		return nil, err
+	}
+	return variants, nil
+}
+
+func (s *serviceProductVariant) Update(productId, id int, input domain.ProductVariantInput) (domain.ProductVariant, error) {
+	variant, err := s.repo.FindById(productId, id)
+	if err != nil {
+		return domain.ProductVariant{}, err
+	}
+
+	input.Apply(&variant)
+	if err := s.repo.Update(&variant); err != nil {
+		return domain.ProductVariant{}, err
+	}
+	return variant, nil
+}
+
+func (s *serviceProductVariant) Delete(productId, id int) error {
+	variant, err := s.repo.FindById(productId, id)
+	if err != nil {
+		return err
+	}
+	return s.repo.Delete(&variant)
+}
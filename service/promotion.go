@@ -1,6 +1,7 @@
 package service
 
 import (
+	"errors"
 	"project/domain"
 	"project/repository"
 )
@@ -10,14 +11,18 @@ type ServicePromotion interface {
 	GetById(id uint) (domain.Promotion, error)
 	Create(promotion *domain.Promotion) error
 	Delete(promotion *domain.Promotion) error
+	Restrict(id uint, segmentId *uint) (domain.Promotion, error)
+	Evaluate(quote domain.PromotionQuote) (domain.PromotionEvaluation, error)
+	GetUsages(id, page, limit uint) ([]domain.PromotionUsage, int, int, error)
 }
 
 type servicePromotion struct {
-	repo repository.RepositoryPromotion
+	repo     repository.RepositoryPromotion
+	segments repository.RepositorySegment
 }
 
-func NewServicePromotion(repo repository.RepositoryPromotion) ServicePromotion {
-	return &servicePromotion{repo: repo}
+func NewServicePromotion(repo repository.RepositoryPromotion, segments repository.RepositorySegment) ServicePromotion {
+	return &servicePromotion{repo: repo, segments: segments}
 }
 
 func (s *servicePromotion) GetAll() ([]domain.Promotion, error) {
@@ -27,8 +32,53 @@ func (s *servicePromotion) GetById(id uint) (domain.Promotion, error) {
 	return s.repo.FindById(id)
 }
 func (s *servicePromotion) Create(promotion *domain.Promotion) error {
+	if err := promotion.Validate(); err != nil {
+		return err
+	}
+	if promotion.SegmentID != nil {
+		if _, err := s.segments.FindById(*promotion.SegmentID); err != nil {
+			return err
+		}
+	}
 	return s.repo.Insert(promotion)
 }
 func (s *servicePromotion) Delete(promotion *domain.Promotion) error {
 	return s.repo.Delete(promotion)
 }
+
+// Restrict limits the promotion to a segment, a nil segment opens it to every customer again.
+func (s *servicePromotion) Restrict(id uint, segmentId *uint) (domain.Promotion, error) {
+	promotion, err := s.repo.FindById(id)
+	if err != nil {
+		return domain.Promotion{}, err
+	}
+	if promotion.ID == 0 {
+		return domain.Promotion{}, errors.New("promotion not found")
+	}
+	if segmentId != nil {
+		if _, err := s.segments.FindById(*segmentId); err != nil {
+			return domain.Promotion{}, err
+		}
+	}
+	promotion.SegmentID = segmentId
+	if err := s.repo.UpdateSegment(&promotion); err != nil {
+		return domain.Promotion{}, err
+	}
+	return promotion, nil
+}
+
+// Evaluate computes the discount a cart would get without using the promotion up.
+func (s *servicePromotion) Evaluate(quote domain.PromotionQuote) (domain.PromotionEvaluation, error) {
+	return s.repo.Evaluate(quote)
+}
+
+func (s *servicePromotion) GetUsages(id, page, limit uint) ([]domain.PromotionUsage, int, int, error) {
+	promotion, err := s.repo.FindById(id)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if promotion.ID == 0 {
+		return nil, 0, 0, errors.New("promotion not found")
+	}
+	return s.repo.FindUsages(id, page, limit)
+}
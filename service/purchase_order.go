@@ -0,0 +1,87 @@
+package service
+
+import (
+	"project/domain"
+	"project/repository"
+
+	"go.uber.org/zap"
+)
+
+type ServicePurchaseOrder interface {
+	GetAll(page, limit uint, status string) (int, int, []domain.PurchaseOrder, error)
+	GetById(id uint) (domain.PurchaseOrder, error)
+	Create(po *domain.PurchaseOrder) error
+	Edit(po *domain.PurchaseOrder) error
+	Send(id uint) (domain.PurchaseOrder, error)
+	Close(id uint) (domain.PurchaseOrder, error)
+	Receive(id uint, receipt domain.GoodsReceipt) (domain.PurchaseOrder, error)
+	GetOpen() ([]domain.OpenPurchaseOrder, error)
+}
+
+type servicePurchaseOrder struct {
+	repo repository.RepositoryPurchaseOrder
+	log  *zap.Logger
+}
+
+func NewServicePurchaseOrder(repo repository.RepositoryPurchaseOrder, log *zap.Logger) ServicePurchaseOrder {
+	return &servicePurchaseOrder{repo, log}
+}
+
+func (s *servicePurchaseOrder) GetAll(page, limit uint, status string) (int, int, []domain.PurchaseOrder, error) {
+	return s.repo.FindAll(page, limit, status)
+}
+
+func (s *servicePurchaseOrder) GetById(id uint) (domain.PurchaseOrder, error) {
+	return s.repo.FindById(id)
+}
+
+func (s *servicePurchaseOrder) Create(po *domain.PurchaseOrder) error {
+	return s.repo.Insert(po)
+}
+
+func (s *servicePurchaseOrder) Edit(po *domain.PurchaseOrder) error {
+	return s.repo.UpdateItems(po)
+}
+
+func (s *servicePurchaseOrder) Send(id uint) (domain.PurchaseOrder, error) {
+	po, err := s.repo.FindById(id)
+	if err != nil {
+		return domain.PurchaseOrder{}, err
+	}
+	if err := po.Send(); err != nil {
+		return domain.PurchaseOrder{}, err
+	}
+	if err := s.repo.UpdateStatus(&po); err != nil {
+		return domain.PurchaseOrder{}, err
+	}
+	s.log.Info("Purchase order sent", zap.Uint("id", id))
+	return po, nil
+}
+
+func (s *servicePurchaseOrder) Close(id uint) (domain.PurchaseOrder, error) {
+	po, err := s.repo.FindById(id)
+	if err != nil {
+		return domain.PurchaseOrder{}, err
+	}
+	if err := po.Close(); err != nil {
+		return domain.PurchaseOrder{}, err
+	}
+	if err := s.repo.UpdateStatus(&po); err != nil {
+		return domain.PurchaseOrder{}, err
+	}
+	s.log.Info("Purchase order closed", zap.Uint("id", id))
+	return po, nil
+}
+
+func (s *servicePurchaseOrder) Receive(id uint, receipt domain.GoodsReceipt) (domain.PurchaseOrder, error) {
+	po, err := s.repo.Receive(id, receipt)
+	if err != nil {
+		return domain.PurchaseOrder{}, err
+	}
+	s.log.Info("Purchase order received", zap.Uint("id", id), zap.String("status", string(po.Status)))
+	return po, nil
+}
+
+func (s *servicePurchaseOrder) GetOpen() ([]domain.OpenPurchaseOrder, error) {
+	return s.repo.FindOpen()
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"project/domain"
+	"project/repository"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type ServiceRefund interface {
+	GetByOrder(orderId uint) ([]domain.Refund, error)
+	GetById(id uint) (domain.Refund, error)
+	Create(orderId uint, request domain.NewRefund) (domain.Refund, error)
+	Approve(id uint, decision domain.RefundDecision) (domain.Refund, error)
+	Reject(id uint, decision domain.RefundDecision) (domain.Refund, error)
+	Pay(id uint) (domain.Refund, error)
+}
+
+type serviceRefund struct {
+	repo   repository.RepositoryRefund
+	orders repository.OrderRepository
+	log    *zap.Logger
+}
+
+func NewServiceRefund(repo repository.RepositoryRefund, orders repository.OrderRepository, log *zap.Logger) ServiceRefund {
+	return &serviceRefund{repo, orders, log}
+}
+
+func (s *serviceRefund) GetByOrder(orderId uint) ([]domain.Refund, error) {
+	if _, err := s.orders.Get(orderId); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByOrder(orderId)
+}
+
+func (s *serviceRefund) GetById(id uint) (domain.Refund, error) {
+	return s.repo.FindById(id)
+}
+
+func (s *serviceRefund) Create(orderId uint, request domain.NewRefund) (domain.Refund, error) {
+	return s.repo.Insert(orderId, request)
+}
+
+func (s *serviceRefund) Approve(id uint, decision domain.RefundDecision) (domain.Refund, error) {
+	return s.decide(id, decision, (*domain.Refund).Approve)
+}
+
+func (s *serviceRefund) Reject(id uint, decision domain.RefundDecision) (domain.Refund, error) {
+	return s.decide(id, decision, (*domain.Refund).Reject)
+}
+
+func (s *serviceRefund) decide(id uint, decision domain.RefundDecision, transition func(*domain.Refund, domain.RefundDecision) error) (domain.Refund, error) {
+	refund, err := s.repo.FindById(id)
+	if err != nil {
+		return domain.Refund{}, err
+	}
+	if err := transition(&refund, decision); err != nil {
+		return domain.Refund{}, err
+	}
+	if err := s.repo.UpdateStatus(&refund); err != nil {
+		s.log.Error("Error updating refund", zap.Uint("id", id), zap.Error(err))
+		return domain.Refund{}, err
+	}
+	s.log.Info("Refund reviewed", zap.Uint("id", id), zap.String("status", string(refund.Status)))
+	return refund, nil
+}
+
+func (s *serviceRefund) Pay(id uint) (domain.Refund, error) {
+	return s.repo.Pay(id, time.Now())
+}
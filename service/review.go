@@ -0,0 +1,117 @@
+package service
+
+import (
+	"project/analyzer"
+	"project/domain"
+	"project/repository"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type ServiceReview interface {
+	GetAll(filter domain.ReviewFilter, page, limit uint) ([]domain.Review, int, int, error)
+	GetById(id uint) (domain.Review, error)
+	Create(newReview domain.NewReview) (domain.Review, error)
+	Moderate(id uint, moderation domain.ReviewModeration) (domain.Review, error)
+	Reply(id uint, reply domain.ReviewReply) (domain.Review, error)
+	GetDecliningSentiment(filter domain.SentimentDeclineFilter, page, limit uint) ([]domain.ProductSentiment, int, int, error)
+	SummarizeSentiment()
+}
+
+type serviceReview struct {
+	repo       repository.RepositoryReview
+	analyzer   analyzer.Analyzer
+	windowDays int
+	log        *zap.Logger
+}
+
+func NewServiceReview(repo repository.RepositoryReview, analyzer analyzer.Analyzer, windowDays int, log *zap.Logger) ServiceReview {
+	return &serviceReview{repo, analyzer, windowDays, log}
+}
+
+func (s *serviceReview) GetAll(filter domain.ReviewFilter, page, limit uint) ([]domain.Review, int, int, error) {
+	return s.repo.FindAll(filter, page, limit)
+}
+
+func (s *serviceReview) GetById(id uint) (domain.Review, error) {
+	return s.repo.FindById(id)
+}
+
+func (s *serviceReview) Create(newReview domain.NewReview) (domain.Review, error) {
+	review := newReview.Review()
+	s.score(&review)
+	if err := s.repo.Insert(&review); err != nil {
+		return domain.Review{}, err
+	}
+	return review, nil
+}
+
+func (s *serviceReview) Moderate(id uint, moderation domain.ReviewModeration) (domain.Review, error) {
+	review, err := s.repo.FindById(id)
+	if err != nil {
+		return domain.Review{}, err
+	}
+	review.Status = moderation.Status
+	if err := s.repo.UpdateStatus(&review); err != nil {
+		return domain.Review{}, err
+	}
+	return review, nil
+}
+
+// Reply adds the merchant's reply to a review or replaces it.
+func (s *serviceReview) Reply(id uint, reply domain.ReviewReply) (domain.Review, error) {
+	review, err := s.repo.FindById(id)
+	if err != nil {
+		return domain.Review{}, err
+	}
+	review.Reply = reply.Reply
+	if err := s.repo.UpdateReply(&review); err != nil {
+		return domain.Review{}, err
+	}
+	return review, nil
+}
+
+func (s *serviceReview) score(review *domain.Review) {
+	result := s.analyzer.Analyze(review.Comment)
+	review.Sentiment = &result.Score
+	review.Keywords = result.Keywords
+}
+
+func (s *serviceReview) GetDecliningSentiment(filter domain.SentimentDeclineFilter, page, limit uint) ([]domain.ProductSentiment, int, int, error) {
+	return s.repo.FindDecliningSentiment(filter, page, limit)
+}
+
+// SummarizeSentiment scores the reviews that weren't analyzed yet and sums up
+// the sentiment and keywords of every product over the latest window against
+// the window before it.
+func (s *serviceReview) SummarizeSentiment() {
+	scored := 0
+	for {
+		reviews, err := s.repo.FindUnscored(500)
+		if err != nil || len(reviews) == 0 {
+			break
+		}
+		for i := range reviews {
+			s.score(&reviews[i])
+			if err := s.repo.UpdateScore(&reviews[i]); err != nil {
+				return
+			}
+		}
+		scored += len(reviews)
+	}
+
+	now := time.Now()
+	split := now.AddDate(0, 0, -s.windowDays)
+	reviews, err := s.repo.FindScored(split.AddDate(0, 0, -s.windowDays))
+	if err != nil {
+		return
+	}
+
+	summaries := domain.SummarizeSentiment(reviews, split, now)
+	if err := s.repo.ReplaceSentiments(summaries); err != nil {
+		s.log.Error("Error saving product sentiment", zap.Error(err))
+		return
+	}
+	s.log.Info("Review sentiment summarized", zap.Int("scored", scored), zap.Int("products", len(summaries)))
+}
@@ -1,10 +1,16 @@
 package service
 
 import (
+	"project/analyzer"
+	"project/carrier"
+	"project/config"
+	"project/imaging"
+	"project/payment"
 	"project/repository"
 	categoryservice "project/service/category_service"
 	dashboardservice "project/service/dashboard_service"
 	productservice "project/service/product_service"
+	"project/storage"
 
 	"go.uber.org/zap"
 )
@@ -20,19 +26,54 @@ type Service struct {
 	Stock         ServiceStock
 	Promotion     ServicePromotion
 	Banner        ServiceBanner
+	Supplier      ServiceSupplier
+	PurchaseOrder ServicePurchaseOrder
+	Shipment      ServiceShipment
+	Payment       ServicePayment
+	Refund        ServiceRefund
+	BulkOrder     ServiceBulkOrder
+	OrderNote     ServiceOrderNote
+	Customer      ServiceCustomer
+	Segment       ServiceSegment
+	Variant       ServiceProductVariant
+	Option        ServiceOption
+	Image         ServiceProductImage
+	Trash         ServiceTrash
+	Price         ServicePrice
+	Review        ServiceReview
+	Storage       storage.Backend
+	Images        *imaging.Pipeline
 }
 
-func NewService(repo repository.Repository, log *zap.Logger) Service {
+func NewService(repo repository.Repository, cfg config.Config, carriers carrier.Registry, providers payment.Registry, backend storage.Backend, reviews analyzer.Analyzer, log *zap.Logger) Service {
+	images := imaging.NewPipeline(backend, imaging.Limits(cfg.ImageConfig))
 	return Service{
 		Auth:          NewAuthService(repo.Auth),
-		Order:         NewOrderService(repo.Order),
+		Order:         NewOrderService(repo.Order, cfg.StoreConfig),
 		PasswordReset: NewPasswordResetService(repo.PasswordReset),
 		User:          NewUserService(repo.User),
 		Category:      categoryservice.NewCategoryService(&repo, log),
 		Product:       productservice.NewProductService(&repo, log),
 		Dashboard:     dashboardservice.NewDashboardService(&repo, log),
 		Stock:         NewServiceStock(repo.Stock, log),
-		Promotion:     NewServicePromotion(repo.Promotion),
+		Promotion:     NewServicePromotion(repo.Promotion, repo.Segment),
 		Banner:        NewServiceBanner(repo.Banner),
+		Supplier:      NewServiceSupplier(repo.Supplier),
+		PurchaseOrder: NewServicePurchaseOrder(repo.PurchaseOrder, log),
+		Shipment:      NewServiceShipment(repo.Shipment, repo.Order, carriers, log),
+		Payment:       NewServicePayment(repo.Payment, repo.Order, providers, log),
+		Refund:        NewServiceRefund(repo.Refund, repo.Order, log),
+		BulkOrder:     NewServiceBulkOrder(repo.Order, repo.BulkJob, log),
+		OrderNote:     NewServiceOrderNote(repo.OrderNote, repo.Order),
+		Customer:      NewServiceCustomer(repo.Customer, repo.Order),
+		Segment:       NewServiceSegment(repo.Segment, log),
+		Variant:       NewServiceProductVariant(repo.Variant, repo.Product),
+		Option:        NewServiceOption(repo.Option),
+		Image:         NewServiceProductImage(repo.Image),
+		Trash:         NewServiceTrash(repo.Trash, images, cfg.TrashRetentionDays, log),
+		Price:         NewServicePrice(repo.Price, log),
+		Review:        NewServiceReview(repo.Review, reviews, cfg.SentimentWindowDays, log),
+		Storage:       backend,
+		Images:        images,
 	}
 }
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"project/carrier"
+	"project/domain"
+	"project/repository"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type ServiceShipment interface {
+	GetByOrder(orderId uint) ([]domain.Shipment, error)
+	Create(orderId uint, newShipment domain.NewShipment) (domain.Shipment, error)
+	PollInTransit()
+}
+
+type serviceShipment struct {
+	repo     repository.RepositoryShipment
+	orders   repository.OrderRepository
+	carriers carrier.Registry
+	log      *zap.Logger
+}
+
+func NewServiceShipment(repo repository.RepositoryShipment, orders repository.OrderRepository, carriers carrier.Registry, log *zap.Logger) ServiceShipment {
+	return &serviceShipment{repo, orders, carriers, log}
+}
+
+func (s *serviceShipment) GetByOrder(orderId uint) ([]domain.Shipment, error) {
+	if _, err := s.orders.Get(orderId); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByOrder(orderId)
+}
+
+// Create buys a label from the chosen carrier and ships the order with it.
+func (s *serviceShipment) Create(orderId uint, newShipment domain.NewShipment) (domain.Shipment, error) {
+	order, err := s.orders.Get(orderId)
+	if err != nil {
+		return domain.Shipment{}, err
+	}
+	if err := domain.CanShip(domain.Status(order.Status)); err != nil {
+		return domain.Shipment{}, err
+	}
+
+	provider, err := s.carriers.Get(newShipment.Carrier)
+	if err != nil {
+		return domain.Shipment{}, err
+	}
+
+	label, err := provider.CreateLabel(carrier.LabelRequest{
+		OrderID:   order.ID,
+		Service:   newShipment.Service,
+		Weight:    newShipment.Weight,
+		Recipient: order.CustomerName,
+		Address:   order.CustomerAddress,
+	})
+	if err != nil {
+		s.log.Error("Error creating shipping label", zap.Uint("order_id", orderId), zap.String("carrier", provider.Name()), zap.Error(err))
+		return domain.Shipment{}, err
+	}
+
+	shipment := domain.Shipment{
+		OrderID:        order.ID,
+		Carrier:        provider.Name(),
+		Service:        newShipment.Service,
+		TrackingNumber: label.TrackingNumber,
+		Weight:         newShipment.Weight,
+		Cost:           label.Cost,
+		ShippedAt:      time.Now(),
+	}
+	if err := s.repo.Insert(&shipment); err != nil {
+		return domain.Shipment{}, err
+	}
+	return shipment, nil
+}
+
+// PollInTransit asks the carriers for the status of every in-transit shipment
+// and marks the delivered ones. Errors are logged so a single failing carrier
+// doesn't stop the rest of the run.
+func (s *serviceShipment) PollInTransit() {
+	shipments, err := s.repo.FindInTransit()
+	if err != nil {
+		return
+	}
+
+	delivered := 0
+	for i := range shipments {
+		shipment := &shipments[i]
+		provider, err := s.carriers.Get(shipment.Carrier)
+		if err != nil {
+			s.log.Warn("Skipping shipment", zap.Uint("id", shipment.ID), zap.Error(err))
+			continue
+		}
+
+		status, err := provider.Track(shipment.TrackingNumber)
+		if err != nil {
+			s.log.Error("Error tracking shipment", zap.Uint("id", shipment.ID), zap.Error(err))
+			continue
+		}
+		if status.Status != carrier.Delivered {
+			continue
+		}
+
+		deliveredAt := time.Now()
+		if status.DeliveredAt != nil {
+			deliveredAt = *status.DeliveredAt
+		}
+		if err := s.repo.MarkDelivered(shipment, deliveredAt); err != nil {
+			s.log.Error("Error marking shipment delivered", zap.Uint("id", shipment.ID), zap.Error(err))
+			continue
+		}
+		delivered++
+	}
+
+	s.log.Info("Shipments polled", zap.Int("in_transit", len(shipments)), zap.Int("delivered", delivered))
+}
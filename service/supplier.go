@@ -0,0 +1,38 @@
+package service
+
+import (
+	"project/domain"
+	"project/repository"
+)
+
+type ServiceSupplier interface {
+	GetAll() ([]domain.Supplier, error)
+	GetById(id uint) (domain.Supplier, error)
+	Create(supplier *domain.Supplier) error
+	Edit(supplier *domain.Supplier) error
+	Delete(supplier *domain.Supplier) error
+}
+
+type serviceSupplier struct {
+	repo repository.RepositorySupplier
+}
+
+func NewServiceSupplier(repo repository.RepositorySupplier) ServiceSupplier {
+	return &serviceSupplier{repo: repo}
+}
+
+func (s *serviceSupplier) GetAll() ([]domain.Supplier, error) {
+	return s.repo.FindAll()
+}
+func (s *serviceSupplier) GetById(id uint) (domain.Supplier, error) {
+	return s.repo.FindById(id)
+}
+func (s *serviceSupplier) Create(supplier *domain.Supplier) error {
+	return s.repo.Insert(supplier)
+}
+func (s *serviceSupplier) Edit(supplier *domain.Supplier) error {
+	return s.repo.Update(supplier)
+}
+func (s *serviceSupplier) Delete(supplier *domain.Supplier) error {
+	return s.repo.Delete(supplier)
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"project/domain"
+	"project/imaging"
+	"project/repository"
+	"project/storage"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type ServiceTrash interface {
+	GetAll(entity domain.TrashEntity, page, limit int) ([]domain.TrashItem, int, int, error)
+	Restore(entity domain.TrashEntity, id uint) error
+	Purge(entity domain.TrashEntity, id uint) error
+	Empty(entity domain.TrashEntity) (domain.TrashPurge, error)
+	PurgeExpired()
+}
+
+type serviceTrash struct {
+	repo      repository.RepositoryTrash
+	images    *imaging.Pipeline
+	retention time.Duration
+	log       *zap.Logger
+}
+
+func NewServiceTrash(repo repository.RepositoryTrash, images *imaging.Pipeline, retentionDays int, log *zap.Logger) ServiceTrash {
+	return &serviceTrash{repo, images, time.Duration(retentionDays) * 24 * time.Hour, log}
+}
+
+func (s *serviceTrash) GetAll(entity domain.TrashEntity, page, limit int) ([]domain.TrashItem, int, int, error) {
+	return s.repo.FindDeleted(entity, page, limit)
+}
+
+func (s *serviceTrash) Restore(entity domain.TrashEntity, id uint) error {
+	return s.repo.Restore(entity, id)
+}
+
+// Purge permanently deletes a row from the trash, the files of purged images
+// are removed from storage after the rows are gone when the backend supports
+// it. Files that can't be removed are only logged, the row stays purged.
+func (s *serviceTrash) Purge(entity domain.TrashEntity, id uint) error {
+	fileIds, err := s.repo.Purge(entity, id)
+	if err != nil || len(fileIds) == 0 {
+		return err
+	}
+
+	err = s.images.Delete(context.Background(), fileIds...)
+	if errors.Is(err, storage.ErrDeleteUnsupported) {
+		s.log.Warn("Storage can't delete files, keeping them", zap.Strings("file_ids", fileIds))
+	} else if err != nil {
+		s.log.Error("Error removing files of purged images", zap.String("entity", string(entity)), zap.Uint("id", id), zap.Strings("file_ids", fileIds), zap.Error(err))
+	}
+	return nil
+}
+
+// Empty purges everything in the trash of an entity.
+func (s *serviceTrash) Empty(entity domain.TrashEntity) (domain.TrashPurge, error) {
+	return s.purgeBefore(entity, time.Now())
+}
+
+// PurgeExpired purges the rows that have been in the trash longer than the
+// retention period, it runs from the cron.
+func (s *serviceTrash) PurgeExpired() {
+	before := time.Now().Add(-s.retention)
+	for _, entity := range domain.TrashEntities {
+		result, err := s.purgeBefore(entity, before)
+		if err != nil {
+			s.log.Error("Error purging expired trash", zap.String("entity", string(entity)), zap.Error(err))
+			continue
+		}
+		if result.Purged > 0 || result.Skipped > 0 || result.Failed > 0 {
+			s.log.Info("Purged expired trash", zap.String("entity", string(entity)), zap.Int("purged", result.Purged), zap.Int("skipped", result.Skipped), zap.Int("failed", result.Failed))
+		}
+	}
+}
+
+func (s *serviceTrash) purgeBefore(entity domain.TrashEntity, before time.Time) (domain.TrashPurge, error) {
+	result := domain.TrashPurge{Entity: entity}
+	ids, err := s.repo.FindDeletedBefore(entity, before)
+	if err != nil {
+		return result, err
+	}
+
+	for _, id := range ids {
+		err := s.Purge(entity, id)
+		switch {
+		case errors.Is(err, domain.ErrStillReferenced):
+			result.Skipped++
+		case err != nil:
+			s.log.Error("Error purging from trash", zap.String("entity", string(entity)), zap.Uint("id", id), zap.Error(err))
+			result.Failed++
+		default:
+			result.Purged++
+		}
+	}
+	return result, nil
+}
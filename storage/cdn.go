@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"project/domain"
+)
+
+// CDN keeps files on the upload API of the academy CDN.
+type CDN struct {
+	url    string
+	client *http.Client
+}
+
+func NewCDN(url string, client *http.Client) *CDN {
+	return &CDN{url: strings.TrimRight(url, "/"), client: client}
+}
+
+func (cdn *CDN) Name() string {
+	return "cdn"
+}
+
+func (cdn *CDN) Put(ctx context.Context, name string, body io.Reader) (Object, error) {
+	form := &bytes.Buffer{}
+	writer := multipart.NewWriter(form)
+	part, err := writer.CreateFormFile("image", filepath.Base(name))
+	if err != nil {
+		return Object{}, err
+	}
+	if _, err := io.Copy(part, body); err != nil {
+		return Object{}, err
+	}
+	if err := writer.Close(); err != nil {
+		return Object{}, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, cdn.url, form)
+	if err != nil {
+		return Object{}, err
+	}
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+
+	response, err := cdn.client.Do(request)
+	if err != nil {
+		return Object{}, fmt.Errorf("cdn: %w", err)
+	}
+	defer response.Body.Close()
+
+	var result domain.CdnResponse
+	if err := json.NewDecoder(response.Body).Decode(&result); err != nil {
+		return Object{}, fmt.Errorf("cdn: invalid response, status %s", response.Status)
+	}
+	if response.StatusCode != http.StatusOK || !result.Success {
+		return Object{}, fmt.Errorf("cdn: upload failed, status %s: %s", response.Status, result.Message)
+	}
+	return Object{Key: result.Data.FileId, URL: result.Data.Url, Size: int64(result.Data.Size)}, nil
+}
+
+// Delete always fails with ErrDeleteUnsupported, the academy CDN only offers
+// an upload API so its files can't be removed from here.
+func (cdn *CDN) Delete(ctx context.Context, key string) error {
+	return ErrDeleteUnsupported
+}
@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"project/domain"
+)
+
+// FakeCDN is an httptest server speaking the CDN upload API, it keeps the
+// uploaded files in memory. Point NewCDN at its URL in tests.
+type FakeCDN struct {
+	*httptest.Server
+	mu      sync.Mutex
+	files   map[string][]byte
+	uploads int
+	failing bool
+}
+
+func NewFakeCDN() *FakeCDN {
+	fake := &FakeCDN{files: map[string][]byte{}}
+	fake.Server = httptest.NewServer(http.HandlerFunc(fake.serve))
+	return fake
+}
+
+// Fail makes the following uploads fail with an internal server error.
+func (fake *FakeCDN) Fail(failing bool) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	fake.failing = failing
+}
+
+// File returns the content of an uploaded file by its key.
+func (fake *FakeCDN) File(key string) ([]byte, bool) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	content, ok := fake.files[key]
+	return content, ok
+}
+
+// Len returns the number of files the fake keeps.
+func (fake *FakeCDN) Len() int {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	return len(fake.files)
+}
+
+func (fake *FakeCDN) serve(w http.ResponseWriter, r *http.Request) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPost:
+		if fake.failing {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(domain.CdnResponse{Message: "upload failed"})
+			return
+		}
+		file, header, err := r.FormFile("image")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(domain.CdnResponse{Message: err.Error()})
+			return
+		}
+		defer file.Close()
+		content, _ := io.ReadAll(file)
+
+		fake.uploads++
+		key := fmt.Sprintf("file-%d", fake.uploads)
+		fake.files[key] = content
+
+		var response domain.CdnResponse
+		response.Success = true
+		response.Data.FileId = key
+		response.Data.Name = header.Filename
+		response.Data.Size = len(content)
+		response.Data.Url = fake.URL + "/files/" + key + "/" + header.Filename
+		json.NewEncoder(w).Encode(response)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
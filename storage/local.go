@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalPath is the path the router serves the files of the local backend
+// under.
+const LocalPath = "/uploads"
+
+// Local keeps files in a directory of the server, they are served under the
+// public url.
+type Local struct {
+	dir       string
+	publicURL string
+}
+
+func NewLocal(dir, publicURL string) *Local {
+	return &Local{dir: dir, publicURL: strings.TrimRight(publicURL, "/")}
+}
+
+func (local *Local) Name() string {
+	return "local"
+}
+
+func (local *Local) Put(ctx context.Context, name string, body io.Reader) (Object, error) {
+	key, err := newKey(name)
+	if err != nil {
+		return Object{}, err
+	}
+	if err := os.MkdirAll(local.dir, 0o755); err != nil {
+		return Object{}, fmt.Errorf("local: %w", err)
+	}
+
+	path := filepath.Join(local.dir, key)
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return Object{}, fmt.Errorf("local: %w", err)
+	}
+	size, err := io.Copy(file, body)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return Object{}, fmt.Errorf("local: %w", err)
+	}
+	return Object{Key: key, URL: local.publicURL + "/" + key, Size: size}, nil
+}
+
+func (local *Local) Delete(ctx context.Context, key string) error {
+	if key == "" || filepath.Base(key) != key || key == "." || key == ".." {
+		return fmt.Errorf("local: invalid key %q", key)
+	}
+	err := os.Remove(filepath.Join(local.dir, key))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("local: %w", err)
+	}
+	return nil
+}
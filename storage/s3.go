@@ -0,0 +1,164 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type S3Options struct {
+	// Endpoint of the S3 compatible service like https://s3.ap-southeast-1.amazonaws.com
+	// or the address of a MinIO server, buckets are addressed by path.
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	// PublicURL is where the bucket is served from, the endpoint is used when
+	// it is empty.
+	PublicURL string
+}
+
+// S3 keeps files in a bucket of an S3 compatible object storage, requests are
+// signed with AWS signature version 4.
+type S3 struct {
+	options S3Options
+	client  *http.Client
+	now     func() time.Time
+}
+
+func NewS3(options S3Options, client *http.Client) *S3 {
+	options.Endpoint = strings.TrimRight(options.Endpoint, "/")
+	options.PublicURL = strings.TrimRight(options.PublicURL, "/")
+	if options.Region == "" {
+		options.Region = "us-east-1"
+	}
+	return &S3{options: options, client: client, now: time.Now}
+}
+
+func (s3 *S3) Name() string {
+	return "s3"
+}
+
+func (s3 *S3) Put(ctx context.Context, name string, body io.Reader) (Object, error) {
+	key, err := newKey(name)
+	if err != nil {
+		return Object{}, err
+	}
+	content, err := io.ReadAll(body)
+	if err != nil {
+		return Object{}, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPut, s3.objectURL(key), bytes.NewReader(content))
+	if err != nil {
+		return Object{}, err
+	}
+	request.Header.Set("Content-Type", http.DetectContentType(content))
+	if err := s3.do(request, content, http.StatusOK); err != nil {
+		return Object{}, err
+	}
+
+	publicURL := s3.options.PublicURL
+	if publicURL == "" {
+		publicURL = s3.options.Endpoint + "/" + s3.options.Bucket
+	}
+	return Object{Key: key, URL: publicURL + "/" + key, Size: int64(len(content))}, nil
+}
+
+func (s3 *S3) Delete(ctx context.Context, key string) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodDelete, s3.objectURL(key), nil)
+	if err != nil {
+		return err
+	}
+	return s3.do(request, nil, http.StatusNoContent, http.StatusOK, http.StatusNotFound)
+}
+
+func (s3 *S3) objectURL(key string) string {
+	return s3.options.Endpoint + "/" + url.PathEscape(s3.options.Bucket) + "/" + url.PathEscape(key)
+}
+
+func (s3 *S3) do(request *http.Request, payload []byte, expected ...int) error {
+	s3.sign(request, payload)
+	response, err := s3.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("s3: %w", err)
+	}
+	defer response.Body.Close()
+
+	for _, status := range expected {
+		if response.StatusCode == status {
+			return nil
+		}
+	}
+	message, _ := io.ReadAll(io.LimitReader(response.Body, 512))
+	return fmt.Errorf("s3: %s %s failed, status %s: %s", request.Method, request.URL.Path, response.Status, bytes.TrimSpace(message))
+}
+
+// sign adds the signature version 4 authorization header to a request.
+func (s3 *S3) sign(request *http.Request, payload []byte) {
+	now := s3.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(payload)
+
+	request.Header.Set("X-Amz-Date", amzDate)
+	request.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	values := map[string]string{
+		"host":                 request.URL.Host,
+		"x-amz-content-sha256": payloadHash,
+		"x-amz-date":           amzDate,
+	}
+	if contentType := request.Header.Get("Content-Type"); contentType != "" {
+		headers = append([]string{"content-type"}, headers...)
+		values["content-type"] = contentType
+	}
+
+	var canonicalHeaders strings.Builder
+	for _, header := range headers {
+		canonicalHeaders.WriteString(header + ":" + strings.TrimSpace(values[header]) + "\n")
+	}
+	signedHeaders := strings.Join(headers, ";")
+
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		request.URL.EscapedPath(),
+		request.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s3.options.Region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s3.options.SecretKey), date)
+	key = hmacSHA256(key, s3.options.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	request.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3.options.AccessKey, scope, signedHeaders, signature))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,123 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"project/config"
+)
+
+// Object is a file kept by a backend.
+type Object struct {
+	// Key identifies the file when deleting it.
+	Key  string `json:"key"`
+	URL  string `json:"url"`
+	Size int64  `json:"size"`
+}
+
+// ErrDeleteUnsupported is returned by backends that can't remove their files.
+var ErrDeleteUnsupported = errors.New("storage: backend doesn't support deleting files")
+
+// Backend is implemented by every place uploaded files can be kept in.
+// Deleting a file that doesn't exist is not an error.
+type Backend interface {
+	Name() string
+	Put(ctx context.Context, name string, body io.Reader) (Object, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// New returns the backend selected by the storage driver of the config.
+func New(cfg config.StorageConfig) (Backend, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+
+	switch cfg.Driver {
+	case "", "cdn":
+		if cfg.CdnURL == "" {
+			return nil, fmt.Errorf("storage: cdn url is not set")
+		}
+		return NewCDN(cfg.CdnURL, client), nil
+	case "local":
+		publicURL := cfg.PublicURL
+		if publicURL == "" {
+			publicURL = LocalPath
+		}
+		return NewLocal(cfg.LocalDir, publicURL), nil
+	case "s3":
+		if cfg.S3Endpoint == "" || cfg.S3Bucket == "" {
+			return nil, fmt.Errorf("storage: s3 endpoint and bucket are required")
+		}
+		return NewS3(S3Options{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			PublicURL: cfg.PublicURL,
+		}, client), nil
+	}
+	return nil, fmt.Errorf("storage: unknown driver %q", cfg.Driver)
+}
+
+// UploadAll stores the files concurrently and returns them in the order they
+// were given. When one of them fails the files already stored are deleted
+// again and the first error is returned.
+func UploadAll(ctx context.Context, backend Backend, files []*multipart.FileHeader) ([]Object, error) {
+	objects := make([]Object, len(files))
+	errs := make([]error, len(files))
+
+	var wg sync.WaitGroup
+	for i, file := range files {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			objects[i], errs[i] = put(ctx, backend, file)
+		}()
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		for j := range objects {
+			if errs[j] == nil {
+				backend.Delete(ctx, objects[j].Key)
+			}
+		}
+		return nil, fmt.Errorf("failed to upload %s: %w", files[i].Filename, err)
+	}
+	return objects, nil
+}
+
+func put(ctx context.Context, backend Backend, file *multipart.FileHeader) (Object, error) {
+	f, err := file.Open()
+	if err != nil {
+		return Object{}, err
+	}
+	defer f.Close()
+	return backend.Put(ctx, file.Filename, f)
+}
+
+// newKey names a stored file uniquely, keeping the extension of its original
+// name.
+func newKey(name string) (string, error) {
+	random := make([]byte, 12)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	ext := strings.ToLower(filepath.Ext(filepath.Base(name)))
+	if len(ext) > 10 || strings.ContainsAny(ext, `/\ `) {
+		ext = ""
+	}
+	return time.Now().Format("20060102") + "-" + hex.EncodeToString(random) + ext, nil
+}
@@ -0,0 +1,182 @@
+package storage_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"project/config"
+	"project/storage"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fileHeaders builds the file headers of a multipart form holding the files.
+func fileHeaders(t *testing.T, files map[string]string, names ...string) []*multipart.FileHeader {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for _, name := range names {
+		part, err := writer.CreateFormFile("images", name)
+		assert.NoError(t, err)
+		part.Write([]byte(files[name]))
+	}
+	writer.Close()
+
+	request := httptest.NewRequest(http.MethodPost, "/", body)
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+	assert.NoError(t, request.ParseMultipartForm(1<<20))
+	return request.MultipartForm.File["images"]
+}
+
+func TestCDN(t *testing.T) {
+	fake := storage.NewFakeCDN()
+	defer fake.Close()
+	cdn := storage.NewCDN(fake.URL, fake.Client())
+	ctx := context.Background()
+
+	t.Run("Successfully upload a file", func(t *testing.T) {
+		object, err := cdn.Put(ctx, "apple.jpg", strings.NewReader("apple"))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), object.Size)
+		assert.True(t, strings.HasSuffix(object.URL, "/apple.jpg"))
+
+		content, ok := fake.File(object.Key)
+		assert.True(t, ok)
+		assert.Equal(t, "apple", string(content))
+
+	})
+
+	t.Run("Deleting a file is not supported", func(t *testing.T) {
+		object, err := cdn.Put(ctx, "apple.jpg", strings.NewReader("apple"))
+		assert.NoError(t, err)
+
+		assert.ErrorIs(t, cdn.Delete(ctx, object.Key), storage.ErrDeleteUnsupported)
+		_, ok := fake.File(object.Key)
+		assert.True(t, ok)
+	})
+
+	t.Run("Failed upload returns an error", func(t *testing.T) {
+		fake.Fail(true)
+		defer fake.Fail(false)
+
+		_, err := cdn.Put(ctx, "apple.jpg", strings.NewReader("apple"))
+		assert.ErrorContains(t, err, "upload failed")
+	})
+}
+
+func TestUploadAll(t *testing.T) {
+	files := map[string]string{"a.jpg": "first", "b.png": "second", "c.gif": "third"}
+
+	t.Run("Successfully upload files concurrently in order", func(t *testing.T) {
+		fake := storage.NewFakeCDN()
+		defer fake.Close()
+		cdn := storage.NewCDN(fake.URL, fake.Client())
+
+		objects, err := storage.UploadAll(context.Background(), cdn, fileHeaders(t, files, "a.jpg", "b.png", "c.gif"))
+		assert.NoError(t, err)
+		assert.Len(t, objects, 3)
+		assert.Equal(t, 3, fake.Len())
+		for i, name := range []string{"a.jpg", "b.png", "c.gif"} {
+			content, _ := fake.File(objects[i].Key)
+			assert.Equal(t, files[name], string(content))
+		}
+	})
+
+	t.Run("Stored files are removed when an upload fails", func(t *testing.T) {
+		dir := t.TempDir()
+		local := storage.NewLocal(dir, "/uploads")
+		failing := failingBackend{Backend: local, name: "b.png"}
+
+		_, err := storage.UploadAll(context.Background(), failing, fileHeaders(t, files, "a.jpg", "b.png", "c.gif"))
+		assert.ErrorContains(t, err, "b.png")
+
+		entries, _ := os.ReadDir(dir)
+		assert.Empty(t, entries)
+	})
+}
+
+// failingBackend fails to store the file with the given name.
+type failingBackend struct {
+	storage.Backend
+	name string
+}
+
+func (backend failingBackend) Put(ctx context.Context, name string, body io.Reader) (storage.Object, error) {
+	if name == backend.name {
+		return storage.Object{}, errors.New("disk full")
+	}
+	return backend.Backend.Put(ctx, name, body)
+}
+
+func TestLocal(t *testing.T) {
+	dir := t.TempDir()
+	local := storage.NewLocal(dir, "http://localhost:8080/uploads/")
+	ctx := context.Background()
+
+	object, err := local.Put(ctx, "../Mango.JPG", strings.NewReader("mango"))
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Base(object.Key), object.Key)
+	assert.True(t, strings.HasSuffix(object.Key, ".jpg"))
+	assert.Equal(t, "http://localhost:8080/uploads/"+object.Key, object.URL)
+
+	content, err := os.ReadFile(filepath.Join(dir, object.Key))
+	assert.NoError(t, err)
+	assert.Equal(t, "mango", string(content))
+
+	assert.NoError(t, local.Delete(ctx, object.Key))
+	assert.NoError(t, local.Delete(ctx, object.Key))
+	assert.Error(t, local.Delete(ctx, "../secret"))
+}
+
+func TestS3(t *testing.T) {
+	var requests []*http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	s3 := storage.NewS3(storage.S3Options{
+		Endpoint:  server.URL,
+		Region:    "ap-southeast-1",
+		Bucket:    "products",
+		AccessKey: "AKIDEXAMPLE",
+		SecretKey: "secret",
+		PublicURL: "https://img.example.com",
+	}, server.Client())
+	ctx := context.Background()
+
+	object, err := s3.Put(ctx, "banana.png", strings.NewReader("\x89PNG\r\n\x1a\nbanana"))
+	assert.NoError(t, err)
+	assert.Equal(t, "https://img.example.com/"+object.Key, object.URL)
+	assert.NoError(t, s3.Delete(ctx, object.Key))
+
+	assert.Len(t, requests, 2)
+	put := requests[0]
+	assert.Equal(t, http.MethodPut, put.Method)
+	assert.Equal(t, "/products/"+object.Key, put.URL.Path)
+	assert.Equal(t, "image/png", put.Header.Get("Content-Type"))
+	assert.Regexp(t, `^AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/\d{8}/ap-southeast-1/s3/aws4_request, SignedHeaders=content-type;host;x-amz-content-sha256;x-amz-date, Signature=[0-9a-f]{64}$`, put.Header.Get("Authorization"))
+	assert.Equal(t, http.MethodDelete, requests[1].Method)
+}
+
+func TestNew(t *testing.T) {
+	backend, err := storage.New(config.StorageConfig{Driver: "local", LocalDir: t.TempDir()})
+	assert.NoError(t, err)
+	assert.Equal(t, "local", backend.Name())
+
+	_, err = storage.New(config.StorageConfig{Driver: "s3"})
+	assert.Error(t, err)
+
+	_, err = storage.New(config.StorageConfig{Driver: "ftp"})
+	assert.Error(t, err)
+}